@@ -54,6 +54,7 @@ type DockerCli struct {
 	client         client.APIClient
 	defaultVersion string
 	server         ServerInfo
+	tlsOptions     *tlsconfig.Options
 }
 
 // DefaultVersion returns api.defaultVersion or DOCKER_API_VERSION if specified.
@@ -137,6 +138,7 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions) error {
 		return err
 	}
 
+	cli.tlsOptions = opts.Common.TLSOptions
 	cli.defaultVersion = cli.client.ClientVersion()
 
 	if ping, err := cli.client.Ping(context.Background()); err == nil {
@@ -154,6 +156,17 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions) error {
 	return nil
 }
 
+// NewNodeClient returns an APIClient for the engine of another node of the
+// swarm, at the given host. It uses the same TLS options and configuration as
+// the client of the CLI.
+func (cli *DockerCli) NewNodeClient(host string) (client.APIClient, error) {
+	opts := &cliflags.CommonOptions{
+		Hosts:      []string{host},
+		TLSOptions: cli.tlsOptions,
+	}
+	return NewAPIClientFromFlags(opts, cli.configFile)
+}
+
 // NotaryClient provides a Notary Repository to interact with signed metadata for an image
 func (cli *DockerCli) NotaryClient(imgRefAndAuth trust.ImageRefAndAuth, actions []string) (notaryclient.Repository, error) {
 	return trust.GetNotaryRepository(cli.In(), cli.Out(), UserAgent(), imgRefAndAuth.RepoInfo(), imgRefAndAuth.AuthConfig(), actions...)
@@ -24,6 +24,12 @@ import (
 
 // AddCommands adds all the commands from cli/command to the root command
 func AddCommands(cmd *cobra.Command, dockerCli *command.DockerCli) {
+	// `service export` shares the conversion to a Compose file with `stack
+	// export`, which can't be imported by the service package as the compose
+	// converter depends on it.
+	serviceCmd := service.NewServiceCommand(dockerCli)
+	serviceCmd.AddCommand(stack.NewServiceExportCommand(dockerCli))
+
 	cmd.AddCommand(
 		// checkpoint
 		checkpoint.NewCheckpointCommand(dockerCli),
@@ -57,7 +63,7 @@ func AddCommands(cmd *cobra.Command, dockerCli *command.DockerCli) {
 		secret.NewSecretCommand(dockerCli),
 
 		// service
-		service.NewServiceCommand(dockerCli),
+		serviceCmd,
 
 		// system
 		system.NewSystemCommand(dockerCli),
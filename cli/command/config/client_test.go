@@ -9,10 +9,13 @@ import (
 
 type fakeClient struct {
 	client.Client
-	configCreateFunc  func(swarm.ConfigSpec) (types.ConfigCreateResponse, error)
-	configInspectFunc func(string) (swarm.Config, []byte, error)
-	configListFunc    func(types.ConfigListOptions) ([]swarm.Config, error)
-	configRemoveFunc  func(string) error
+	configCreateFunc   func(swarm.ConfigSpec) (types.ConfigCreateResponse, error)
+	configInspectFunc  func(string) (swarm.Config, []byte, error)
+	configListFunc     func(types.ConfigListOptions) ([]swarm.Config, error)
+	configRemoveFunc   func(string) error
+	serviceListFunc    func(types.ServiceListOptions) ([]swarm.Service, error)
+	serviceInspectFunc func(string) (swarm.Service, []byte, error)
+	serviceUpdateFunc  func(string, swarm.Version, swarm.ServiceSpec, types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
 }
 
 func (c *fakeClient) ConfigCreate(ctx context.Context, spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
@@ -42,3 +45,24 @@ func (c *fakeClient) ConfigRemove(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+func (c *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+	if c.serviceListFunc != nil {
+		return c.serviceListFunc(options)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+	if c.serviceUpdateFunc != nil {
+		return c.serviceUpdateFunc(serviceID, version, service, options)
+	}
+	return types.ServiceUpdateResponse{}, nil
+}
+
+func (c *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+	if c.serviceInspectFunc != nil {
+		return c.serviceInspectFunc(serviceID)
+	}
+	return swarm.Service{}, nil, nil
+}
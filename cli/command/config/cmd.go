@@ -22,6 +22,7 @@ func NewConfigCommand(dockerCli *command.DockerCli) *cobra.Command {
 		newConfigCreateCommand(dockerCli),
 		newConfigInspectCommand(dockerCli),
 		newConfigRemoveCommand(dockerCli),
+		newConfigRotateCommand(dockerCli),
 	)
 	return cmd
 }
@@ -2,15 +2,11 @@ package config
 
 import (
 	"fmt"
-	"io"
-	"io/ioutil"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/swarm"
-	"github.com/docker/docker/pkg/system"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
@@ -19,6 +15,7 @@ type createOptions struct {
 	name   string
 	file   string
 	labels opts.ListOpts
+	data   command.DataOptions
 }
 
 func newConfigCreateCommand(dockerCli command.Cli) *cobra.Command {
@@ -27,17 +24,20 @@ func newConfigCreateCommand(dockerCli command.Cli) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "create [OPTIONS] CONFIG file|-",
+		Use:   "create [OPTIONS] CONFIG [file|-]",
 		Short: "Create a configuration file from a file or STDIN as content",
-		Args:  cli.ExactArgs(2),
+		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			createOpts.name = args[0]
-			createOpts.file = args[1]
+			if len(args) == 2 {
+				createOpts.file = args[1]
+			}
 			return runConfigCreate(dockerCli, createOpts)
 		},
 	}
 	flags := cmd.Flags()
 	flags.VarP(&createOpts.labels, "label", "l", "Config labels")
+	command.AddDataFlags(flags, &createOpts.data)
 
 	return cmd
 }
@@ -46,19 +46,9 @@ func runConfigCreate(dockerCli command.Cli, options createOptions) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 
-	var in io.Reader = dockerCli.In()
-	if options.file != "-" {
-		file, err := system.OpenSequential(options.file)
-		if err != nil {
-			return err
-		}
-		in = file
-		defer file.Close()
-	}
-
-	configData, err := ioutil.ReadAll(in)
+	configData, err := command.ReadData(dockerCli.In(), options.file, options.data)
 	if err != nil {
-		return errors.Errorf("Error reading content from %q: %v", options.file, err)
+		return err
 	}
 
 	spec := swarm.ConfigSpec{
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type rotateOptions struct {
+	config string
+	file   string
+	name   string
+	labels opts.ListOpts
+	remove bool
+	detach bool
+	quiet  bool
+}
+
+func newConfigRotateCommand(dockerCli command.Cli) *cobra.Command {
+	options := rotateOptions{
+		labels: opts.NewListOpts(opts.ValidateEnv),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "rotate [OPTIONS] CONFIG file|-",
+		Short: "Replace a config with a new version in all the services that use it",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.config = args[0]
+			options.file = args[1]
+			return runConfigRotate(dockerCli, options)
+		},
+		Tags: map[string]string{"version": "1.29"},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&options.name, "name", "", "Name of the new config (default: the name of the config with a version suffix)")
+	flags.VarP(&options.labels, "label", "l", "Config labels, in addition to the labels of the config")
+	flags.BoolVar(&options.remove, "rm", false, "Remove the config once the services are updated")
+	flags.BoolVarP(&options.detach, "detach", "d", false, "Exit immediately instead of waiting for the services to converge")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress progress output")
+
+	return cmd
+}
+
+func runConfigRotate(dockerCli command.Cli, options rotateOptions) error {
+	client := dockerCli.Client()
+
+	rotation := service.Rotation{
+		Kind: "config",
+		Inspect: func(ctx context.Context) (string, string, map[string]string, error) {
+			config, _, err := client.ConfigInspectWithRaw(ctx, options.config)
+			return config.ID, config.Spec.Name, config.Spec.Labels, err
+		},
+		Create: func(ctx context.Context, name string, labels map[string]string, data []byte) (string, error) {
+			spec := swarm.ConfigSpec{
+				Annotations: swarm.Annotations{
+					Name:   name,
+					Labels: labels,
+				},
+				Data: data,
+			}
+			r, err := client.ConfigCreate(ctx, spec)
+			return r.ID, err
+		},
+		Remove: func(ctx context.Context, id string) error {
+			return client.ConfigRemove(ctx, id)
+		},
+		Rotate: rotateServiceConfig,
+	}
+	return service.RunRotate(dockerCli, rotation, service.RotateOptions{
+		File:   options.file,
+		Name:   options.name,
+		Labels: options.labels.GetAll(),
+		Remove: options.remove,
+		Detach: options.detach,
+		Quiet:  options.quiet,
+	})
+}
+
+// rotateServiceConfig changes the references to a config in a service spec to
+// a new config, with the same target, uid, gid and mode. It returns whether
+// the spec references the config.
+func rotateServiceConfig(spec *swarm.ServiceSpec, oldID, newID, newName string) bool {
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if containerSpec == nil {
+		return false
+	}
+	found := false
+	for i, ref := range containerSpec.Configs {
+		if ref.ConfigID != oldID {
+			continue
+		}
+		rotated := *ref
+		rotated.ConfigID = newID
+		rotated.ConfigName = newName
+		containerSpec.Configs[i] = &rotated
+		found = true
+	}
+	return found
+}
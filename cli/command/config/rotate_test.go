@@ -0,0 +1,88 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRotate(t *testing.T) {
+	target := &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx.conf", UID: "0", GID: "0", Mode: 0444}
+	services := []swarm.Service{
+		{
+			ID: "web-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "web"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Configs: []*swarm.ConfigReference{
+						{ConfigID: "nginx-id", ConfigName: "nginx", File: target},
+					},
+				}},
+			},
+		},
+		{
+			ID: "db-id",
+			Spec: swarm.ServiceSpec{
+				Annotations:  swarm.Annotations{Name: "db"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}},
+			},
+		},
+	}
+
+	var (
+		created swarm.ConfigSpec
+		updated = map[string]swarm.ServiceSpec{}
+		removed []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(name string) (swarm.Config, []byte, error) {
+			return swarm.Config{
+				ID:   "nginx-id",
+				Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "nginx"}},
+			}, nil, nil
+		},
+		configCreateFunc: func(spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+			created = spec
+			return types.ConfigCreateResponse{ID: "new-id"}, nil
+		},
+		configRemoveFunc: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+		serviceListFunc: func(types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated[serviceID] = spec
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newConfigRotateCommand(cli)
+	cmd.SetArgs([]string{"nginx", filepath.Join("testdata", configDataFile)})
+	cmd.Flags().Set("name", "nginx-2017-10")
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, "nginx-2017-10", created.Name)
+	assert.Equal(t, "new-id\n", cli.OutBuffer().String())
+
+	require.Len(t, updated, 1)
+	assert.Equal(t, []*swarm.ConfigReference{
+		{ConfigID: "new-id", ConfigName: "nginx-2017-10", File: target},
+	}, updated["web-id"].TaskTemplate.ContainerSpec.Configs)
+	assert.Len(t, removed, 0)
+}
+
+func TestConfigRotateErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newConfigRotateCommand(cli)
+	cmd.SetArgs([]string{"nginx", "-"})
+	cmd.Flags().Set("rm", "true")
+	cmd.Flags().Set("detach", "true")
+	testutil.ErrorContains(t, cmd.Execute(), "--rm can't be used with --detach")
+}
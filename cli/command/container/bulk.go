@@ -0,0 +1,113 @@
+package container
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/net/context"
+)
+
+// bulkOptions holds the options of commands that act on several containers
+// at once, which can be selected by name or by filter.
+type bulkOptions struct {
+	filter opts.FilterOpt
+	dryRun bool
+	force  bool
+}
+
+func newBulkOptions() bulkOptions {
+	return bulkOptions{filter: opts.NewFilterOpt()}
+}
+
+// addBulkFlags adds the flags to select containers by filter to flags. The
+// force flag is only added if the command does not have one already.
+func addBulkFlags(flags *pflag.FlagSet, options *bulkOptions, withForce bool) {
+	flags.Var(&options.filter, "filter", "Select containers based on conditions provided, like 'docker ps'")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Only print the selected containers")
+	if withForce {
+		flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
+	}
+}
+
+// requiresContainers requires at least one container argument, unless the
+// containers are selected by filter.
+func (o *bulkOptions) requiresContainers() cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if o.filter.Value().Len() > 0 {
+			return nil
+		}
+		return cli.RequiresMinArgs(1)(cmd, args)
+	}
+}
+
+// selectContainers returns the given containers, followed by those matching
+// the filter. Only running containers are matched unless all is true. The
+// given containers are resolved to their full ID so that a container matching
+// the filter isn't selected twice; those that can't be resolved are kept as
+// is, for the command to report the error.
+func selectContainers(ctx context.Context, dockerCli command.Cli, names []string, options bulkOptions, all bool) ([]string, error) {
+	if options.filter.Value().Len() == 0 {
+		return names, nil
+	}
+
+	containers, err := dockerCli.Client().ContainerList(ctx, types.ContainerListOptions{
+		All:     all,
+		Filters: options.filter.Value(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	selected := append([]string{}, names...)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+		if c, err := dockerCli.Client().ContainerInspect(ctx, name); err == nil && c.ContainerJSONBase != nil {
+			seen[c.ID] = true
+		}
+	}
+	for _, c := range containers {
+		name := stringid.TruncateID(c.ID)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		if seen[name] || seen[c.ID] {
+			continue
+		}
+		seen[name] = true
+		seen[c.ID] = true
+		selected = append(selected, name)
+	}
+	return selected, nil
+}
+
+// confirmBulkOperation returns whether the operation must be run on the
+// given containers. The selected containers are only printed in dry-run
+// mode, and the user is prompted for confirmation when containers are
+// selected by filter, unless forced.
+func confirmBulkOperation(dockerCli command.Cli, action string, containers []string, options bulkOptions) bool {
+	if options.dryRun {
+		for _, name := range containers {
+			fmt.Fprintln(dockerCli.Out(), name)
+		}
+		return false
+	}
+	if len(containers) == 0 {
+		fmt.Fprintln(dockerCli.Err(), "No containers match the given filters")
+		return false
+	}
+	if options.force || options.filter.Value().Len() == 0 {
+		return true
+	}
+
+	message := fmt.Sprintf("WARNING! This will %s the following %d container(s):\n  %s\nAre you sure you want to continue?",
+		action, len(containers), strings.Join(containers, "\n  "))
+	return command.PromptForConfirmation(dockerCli.In(), dockerCli.Out(), message)
+}
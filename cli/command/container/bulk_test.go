@@ -0,0 +1,159 @@
+package container
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestSelectContainers(t *testing.T) {
+	var listOptions types.ContainerListOptions
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options types.ContainerListOptions) ([]types.Container, error) {
+			listOptions = options
+			return []types.Container{
+				{ID: "aaaaaaaaaaaaaaaaaaaa", Names: []string{"/web"}},
+				{ID: "bbbbbbbbbbbbbbbbbbbb", Names: []string{"/db"}},
+				{ID: "cccccccccccccccccccc"},
+			}, nil
+		},
+	})
+
+	options := newBulkOptions()
+	require.NoError(t, options.filter.Set("label=env=test"))
+
+	containers, err := selectContainers(context.Background(), cli, []string{"db", "other"}, options, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "other", "web", "cccccccccccc"}, containers)
+	assert.True(t, listOptions.All)
+	assert.Equal(t, []string{"env=test"}, listOptions.Filters.Get("label"))
+}
+
+func TestSelectContainersResolvesArguments(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(container string) (types.ContainerJSON, error) {
+			switch container {
+			case "aaaa":
+				return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: "aaaaaaaaaaaaaaaaaaaa"}}, nil
+			case "db":
+				return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: "bbbbbbbbbbbbbbbbbbbb"}}, nil
+			}
+			return types.ContainerJSON{}, errors.Errorf("No such container: %s", container)
+		},
+		containerListFunc: func(options types.ContainerListOptions) ([]types.Container, error) {
+			return []types.Container{
+				{ID: "aaaaaaaaaaaaaaaaaaaa", Names: []string{"/web"}},
+				{ID: "bbbbbbbbbbbbbbbbbbbb", Names: []string{"/db"}},
+				{ID: "cccccccccccccccccccc", Names: []string{"/cache"}},
+			}, nil
+		},
+	})
+
+	options := newBulkOptions()
+	require.NoError(t, options.filter.Set("status=exited"))
+
+	containers, err := selectContainers(context.Background(), cli, []string{"aaaa", "db", "other"}, options, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aaaa", "db", "other", "cache"}, containers)
+}
+
+func TestSelectContainersWithoutFilter(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	containers, err := selectContainers(context.Background(), cli, []string{"web"}, newBulkOptions(), false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web"}, containers)
+}
+
+func TestConfirmBulkOperation(t *testing.T) {
+	filtered := newBulkOptions()
+	require.NoError(t, filtered.filter.Set("status=exited"))
+
+	testCases := []struct {
+		name           string
+		options        func() bulkOptions
+		input          string
+		expected       bool
+		expectedOutput string
+	}{
+		{
+			name:     "explicit-containers",
+			options:  newBulkOptions,
+			expected: true,
+		},
+		{
+			name: "dry-run",
+			options: func() bulkOptions {
+				o := filtered
+				o.dryRun = true
+				return o
+			},
+			expected:       false,
+			expectedOutput: "web\ndb\n",
+		},
+		{
+			name:           "prompt-yes",
+			options:        func() bulkOptions { return filtered },
+			input:          "y\n",
+			expected:       true,
+			expectedOutput: "WARNING! This will remove the following 2 container(s):\n  web\n  db\n",
+		},
+		{
+			name:           "prompt-no",
+			options:        func() bulkOptions { return filtered },
+			input:          "n\n",
+			expected:       false,
+			expectedOutput: "Are you sure you want to continue? [y/N] ",
+		},
+		{
+			name: "force",
+			options: func() bulkOptions {
+				o := filtered
+				o.force = true
+				return o
+			},
+			expected: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.SetIn(command.NewInStream(ioutil.NopCloser(strings.NewReader(tc.input))))
+
+			confirmed := confirmBulkOperation(cli, "remove", []string{"web", "db"}, tc.options())
+			assert.Equal(t, tc.expected, confirmed)
+			assert.Contains(t, cli.OutBuffer().String(), tc.expectedOutput)
+		})
+	}
+}
+
+func TestConfirmBulkOperationNoMatch(t *testing.T) {
+	options := newBulkOptions()
+	require.NoError(t, options.filter.Set("status=exited"))
+
+	cli := test.NewFakeCli(&fakeClient{})
+	errBuf := new(bytes.Buffer)
+	cli.SetErr(errBuf)
+	assert.False(t, confirmBulkOperation(cli, "stop", nil, options))
+	assert.Equal(t, "No containers match the given filters\n", errBuf.String())
+}
+
+func TestRequiresContainers(t *testing.T) {
+	options := newBulkOptions()
+	cmd := &cobra.Command{Use: "stop"}
+
+	assert.Error(t, options.requiresContainers()(cmd, nil))
+	assert.NoError(t, options.requiresContainers()(cmd, []string{"web"}))
+
+	require.NoError(t, options.filter.Set("name=web"))
+	assert.NoError(t, options.requiresContainers()(cmd, nil))
+}
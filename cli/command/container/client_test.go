@@ -5,6 +5,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"golang.org/x/net/context"
@@ -18,6 +19,9 @@ type fakeClient struct {
 	createContainerFunc func(config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error)
 	imageCreateFunc     func(parentReference string, options types.ImageCreateOptions) (io.ReadCloser, error)
 	infoFunc            func() (types.Info, error)
+	containerWaitFunc   func(container string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error)
+	eventsFunc          func(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+	containerListFunc   func(options types.ContainerListOptions) ([]types.Container, error)
 }
 
 func (f *fakeClient) ContainerInspect(_ context.Context, containerID string) (types.ContainerJSON, error) {
@@ -71,3 +75,24 @@ func (f *fakeClient) Info(_ context.Context) (types.Info, error) {
 	}
 	return types.Info{}, nil
 }
+
+func (f *fakeClient) ContainerWait(_ context.Context, container string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+	if f.containerWaitFunc != nil {
+		return f.containerWaitFunc(container, condition)
+	}
+	return nil, nil
+}
+
+func (f *fakeClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	if f.eventsFunc != nil {
+		return f.eventsFunc(ctx, options)
+	}
+	return nil, nil
+}
+
+func (f *fakeClient) ContainerList(_ context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	if f.containerListFunc != nil {
+		return f.containerListFunc(options)
+	}
+	return []types.Container{}, nil
+}
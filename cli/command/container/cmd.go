@@ -20,6 +20,7 @@ func NewContainerCommand(dockerCli *command.DockerCli) *cobra.Command {
 		NewCommitCommand(dockerCli),
 		NewCopyCommand(dockerCli),
 		NewCreateCommand(dockerCli),
+		NewDebugCommand(dockerCli),
 		NewDiffCommand(dockerCli),
 		NewExecCommand(dockerCli),
 		NewExportCommand(dockerCli),
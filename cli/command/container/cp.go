@@ -10,6 +10,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -21,6 +22,7 @@ type copyOptions struct {
 	destination string
 	followLink  bool
 	copyUIDGID  bool
+	exclude     []string
 }
 
 type copyDirection int
@@ -33,6 +35,7 @@ const (
 
 type cpConfig struct {
 	followLink bool
+	exclude    []string
 }
 
 // NewCopyCommand creates a new `docker cp` command
@@ -48,7 +51,10 @@ func NewCopyCommand(dockerCli *command.DockerCli) *cobra.Command {
 			"\nUse '-' as the source to read a tar archive from stdin\n",
 			"and extract it to a directory destination in a container.\n",
 			"Use '-' as the destination to stream a tar archive of a\n",
-			"container source to stdout.",
+			"container source to stdout.\n",
+			"\nSRC_PATH may contain shell-style glob patterns ('*', '?' and\n",
+			"'[...]'), in which case all matches are copied into the\n",
+			"DEST_PATH directory.",
 		}, ""),
 		Args: cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -68,6 +74,7 @@ func NewCopyCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "Always follow symbol link in SRC_PATH")
 	flags.BoolVarP(&opts.copyUIDGID, "archive", "a", false, "Archive mode (copy all uid/gid information)")
+	flags.StringSliceVar(&opts.exclude, "exclude", []string{}, "Exclude files matching the given pattern from SRC_PATH")
 
 	return cmd
 }
@@ -86,6 +93,7 @@ func runCopy(dockerCli *command.DockerCli, opts copyOptions) error {
 
 	cpParam := &cpConfig{
 		followLink: opts.followLink,
+		exclude:    opts.exclude,
 	}
 
 	ctx := context.Background()
@@ -125,6 +133,17 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 		}
 	}
 
+	filter, err := newCopyFilter(srcPath, cpParam.exclude, func(path string) bool {
+		_, err := statContainerPath(ctx, dockerCli, srcContainer, path)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if filter.isGlob() {
+		return copyGlobFromContainer(ctx, dockerCli, srcContainer, dstPath, filter)
+	}
+
 	// if client requests to follow symbol link, then must decide target file to be copied
 	var rebaseName string
 	if cpParam.followLink {
@@ -151,9 +170,16 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 	}
 	defer content.Close()
 
+	var preArchive io.Reader = content
+	if !filter.isNoop() {
+		filtered := filter.apply(content)
+		defer filtered.Close()
+		preArchive = filtered
+	}
+
 	if dstPath == "-" {
 		// Send the response to STDOUT.
-		_, err = io.Copy(os.Stdout, content)
+		_, err = io.Copy(os.Stdout, preArchive)
 
 		return err
 	}
@@ -166,10 +192,9 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 		RebaseName: rebaseName,
 	}
 
-	preArchive := content
 	if len(srcInfo.RebaseName) != 0 {
 		_, srcBase := archive.SplitPathDirEntry(srcInfo.Path)
-		preArchive = archive.RebaseArchiveEntries(content, srcBase, srcInfo.RebaseName)
+		preArchive = archive.RebaseArchiveEntries(preArchive, srcBase, srcInfo.RebaseName)
 	}
 	// See comments in the implementation of `archive.CopyTo` for exactly what
 	// goes into deciding how and whether the source archive needs to be
@@ -177,13 +202,64 @@ func copyFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcCon
 	return archive.CopyTo(preArchive, srcInfo, dstPath)
 }
 
+// copyGlobFromContainer copies all paths in the container that match the
+// filter's glob pattern into the dstPath directory. The directory holding the
+// matches is archived by the daemon, and the resulting archive is filtered
+// before it is extracted.
+func copyGlobFromContainer(ctx context.Context, dockerCli *command.DockerCli, srcContainer, dstPath string, filter *copyFilter) error {
+	if dstPath != "-" {
+		dstStat, err := os.Stat(dstPath)
+		if err != nil {
+			return err
+		}
+		if !dstStat.IsDir() {
+			return errors.Errorf("destination %q must be a directory when copying multiple files", dstPath)
+		}
+	}
+
+	content, stat, err := dockerCli.Client().CopyFromContainer(ctx, srcContainer, filter.base)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if !stat.Mode.IsDir() {
+		return errors.Errorf("source \"%s:%s\" is not a directory", srcContainer, filter.base)
+	}
+
+	filtered := filter.apply(content)
+	defer filtered.Close()
+
+	if dstPath == "-" {
+		// Send the response to STDOUT.
+		_, err = io.Copy(os.Stdout, filtered)
+
+		return err
+	}
+
+	return archive.Untar(filtered, dstPath, &archive.TarOptions{
+		NoLchown:             true,
+		NoOverwriteDirNonDir: true,
+	})
+}
+
 func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath, dstContainer, dstPath string, cpParam *cpConfig, copyUIDGID bool) (err error) {
+	var filter *copyFilter
 	if srcPath != "-" {
 		// Get an absolute source path.
 		srcPath, err = resolveLocalPath(srcPath)
 		if err != nil {
 			return err
 		}
+		filter, err = newCopyFilter(filepath.ToSlash(srcPath), cpParam.exclude, func(path string) bool {
+			_, err := os.Lstat(filepath.FromSlash(path))
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	} else if len(cpParam.exclude) > 0 {
+		return errors.New("--exclude can not be used when reading from stdin")
 	}
 
 	// In order to get the copy behavior right, we need to know information
@@ -230,6 +306,20 @@ func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath,
 		if !dstInfo.IsDir {
 			return errors.Errorf("destination \"%s:%s\" must be a directory", dstContainer, dstPath)
 		}
+	} else if filter.isGlob() {
+		srcArchive, err := tarGlobSource(filter)
+		if err != nil {
+			return err
+		}
+		defer srcArchive.Close()
+
+		// All matches are extracted relative to the destination, so it has to
+		// be an existing directory.
+		resolvedDstPath = dstInfo.Path
+		if !dstInfo.IsDir {
+			return errors.Errorf("destination \"%s:%s\" must be a directory when copying multiple files", dstContainer, dstPath)
+		}
+		content = srcArchive
 	} else {
 		// Prepare source copy info.
 		srcInfo, err := archive.CopyInfoSourcePath(srcPath, cpParam.followLink)
@@ -243,6 +333,11 @@ func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath,
 		}
 		defer srcArchive.Close()
 
+		if !filter.isNoop() {
+			srcArchive = filter.apply(srcArchive)
+			defer srcArchive.Close()
+		}
+
 		// With the stat info about the local source as well as the
 		// destination, we have enough information to know whether we need to
 		// alter the archive that we upload so that when the server extracts
@@ -273,6 +368,29 @@ func copyToContainer(ctx context.Context, dockerCli *command.DockerCli, srcPath,
 	return dockerCli.Client().CopyToContainer(ctx, dstContainer, resolvedDstPath, content, options)
 }
 
+// tarGlobSource archives the local directory holding all paths that match
+// the filter's glob pattern, and filters the archive down to those matches.
+func tarGlobSource(filter *copyFilter) (io.ReadCloser, error) {
+	base := filepath.FromSlash(filter.base)
+	stat, err := os.Stat(base)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return nil, errors.Errorf("source %q is not a directory", base)
+	}
+
+	srcArchive, err := archive.TarResource(archive.CopyInfo{Path: base})
+	if err != nil {
+		return nil, err
+	}
+	filtered := filter.apply(srcArchive)
+	return ioutils.NewReadCloserWrapper(filtered, func() error {
+		filtered.Close()
+		return srcArchive.Close()
+	}), nil
+}
+
 // We use `:` as a delimiter between CONTAINER and PATH, but `:` could also be
 // in a valid LOCALPATH, like `file:name.txt`. We can resolve this ambiguity by
 // requiring a LOCALPATH with a `:` to be made explicit with a relative or
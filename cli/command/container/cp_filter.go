@@ -0,0 +1,219 @@
+package container
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// copyFilter selects and renames the entries of a tar archive that is
+// transferred by `docker cp`. It implements shell-style glob patterns in
+// SRC_PATH as well as the `--exclude` patterns.
+//
+// All paths handled by a copyFilter use forward slashes, which is also the
+// separator used for the names of tar archive entries.
+type copyFilter struct {
+	// pattern is the original source path.
+	pattern string
+	// base is the longest leading part of the source path that does not
+	// contain any glob meta characters. It is the path that is archived
+	// when the source path is a glob pattern.
+	base string
+	// glob holds the path elements of the source path following base. It is
+	// empty if the source path is not a glob pattern.
+	glob []string
+	// excludes holds the patterns of entries that must not be copied.
+	excludes []string
+}
+
+// newCopyFilter creates a copyFilter for the given (slash separated) source
+// path and exclude patterns. A source path with glob meta characters is only
+// a glob pattern if exists reports that there is no such literal path, so
+// that a file named like `file[1].txt` can still be copied.
+func newCopyFilter(srcPath string, excludes []string, exists func(path string) bool) (*copyFilter, error) {
+	f := &copyFilter{pattern: srcPath, base: srcPath}
+
+	if !hasGlobMeta(srcPath) || exists(srcPath) {
+		return f, f.addExcludes(excludes)
+	}
+
+	elems := strings.Split(srcPath, "/")
+	for i, elem := range elems {
+		if !hasGlobMeta(elem) {
+			continue
+		}
+		f.base = strings.Join(elems[:i], "/")
+		if f.base == "" && strings.HasPrefix(srcPath, "/") {
+			f.base = "/"
+		}
+		for _, g := range elems[i:] {
+			if g == "" {
+				continue
+			}
+			if _, err := path.Match(g, ""); err != nil {
+				return nil, errors.Errorf("invalid pattern %q in source path", srcPath)
+			}
+			f.glob = append(f.glob, g)
+		}
+		break
+	}
+	return f, f.addExcludes(excludes)
+}
+
+func (f *copyFilter) addExcludes(excludes []string) error {
+	for _, pattern := range excludes {
+		pattern = strings.Trim(path.Clean(pattern), "/")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Errorf("invalid exclude pattern %q", pattern)
+		}
+		f.excludes = append(f.excludes, pattern)
+	}
+	return nil
+}
+
+func hasGlobMeta(elem string) bool {
+	return strings.ContainsAny(elem, `*?[`)
+}
+
+// isGlob returns true if the source path is a glob pattern.
+func (f *copyFilter) isGlob() bool {
+	return len(f.glob) > 0
+}
+
+// isNoop returns true if the filter does not alter the archive.
+func (f *copyFilter) isNoop() bool {
+	return !f.isGlob() && len(f.excludes) == 0
+}
+
+// entryName returns the new name of the archive entry with the given name,
+// and whether the entry must be kept at all.
+//
+// The first path element of every entry is the base name of the archived
+// source. Exclude patterns are matched against the remainder of the path.
+// For glob sources, the remainder must also match the glob pattern and
+// becomes the new name of the entry, so that all matches are extracted
+// relative to the destination directory.
+func (f *copyFilter) entryName(name string) (string, bool, error) {
+	trimmed := strings.TrimSuffix(name, "/")
+	var rel string
+	if i := strings.Index(trimmed, "/"); i >= 0 {
+		rel = trimmed[i+1:]
+	}
+
+	if rel != "" {
+		excluded, err := f.excluded(rel)
+		if err != nil || excluded {
+			return "", false, err
+		}
+	}
+	if !f.isGlob() {
+		return name, true, nil
+	}
+	if rel == "" {
+		return "", false, nil
+	}
+
+	elems := strings.Split(rel, "/")
+	if len(elems) < len(f.glob) {
+		// Intermediate directories are created as needed on extraction,
+		// so there is no need to keep those that lead to a match.
+		return "", false, nil
+	}
+	for i, g := range f.glob {
+		matched, err := path.Match(g, elems[i])
+		if err != nil || !matched {
+			return "", false, err
+		}
+	}
+	if strings.HasSuffix(name, "/") {
+		rel += "/"
+	}
+	return rel, true, nil
+}
+
+// excluded returns true if the given relative path matches any of the
+// exclude patterns. Patterns that contain a separator are matched against
+// the path and each of its parents, other patterns are matched against each
+// element of the path.
+func (f *copyFilter) excluded(rel string) (bool, error) {
+	elems := strings.Split(rel, "/")
+	for _, pattern := range f.excludes {
+		candidates := elems
+		if strings.Contains(pattern, "/") {
+			candidates = make([]string, len(elems))
+			for i := range elems {
+				candidates[i] = strings.Join(elems[:i+1], "/")
+			}
+		}
+		for _, candidate := range candidates {
+			matched, err := path.Match(pattern, candidate)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// apply returns a tar archive that contains the entries of the given archive
+// selected by the filter. An error is returned when reading the archive if a
+// glob pattern does not match anything.
+func (f *copyFilter) apply(content io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(f.copyArchive(tar.NewWriter(pw), tar.NewReader(content)))
+	}()
+	return pr
+}
+
+func (f *copyFilter) copyArchive(tw *tar.Writer, tr *tar.Reader) error {
+	var matched int
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name, keep, err := f.entryName(hdr.Name)
+		if err != nil {
+			return err
+		}
+		if !keep {
+			continue
+		}
+		if hdr.Typeflag == tar.TypeLink {
+			// Hard links to entries that are not copied can't be extracted.
+			linkname, keepLink, err := f.entryName(hdr.Linkname)
+			if err != nil {
+				return err
+			}
+			if !keepLink {
+				continue
+			}
+			hdr.Linkname = linkname
+		}
+		hdr.Name = name
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+		matched++
+	}
+
+	if f.isGlob() && matched == 0 {
+		return errors.Errorf("no such file or directory matching %q", f.pattern)
+	}
+	return tw.Close()
+}
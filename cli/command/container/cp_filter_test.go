@@ -0,0 +1,162 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCopyFilter(t *testing.T) {
+	testCases := []struct {
+		srcPath      string
+		expectedBase string
+		expectedGlob []string
+	}{
+		{srcPath: "/var/app/out.log", expectedBase: "/var/app/out.log"},
+		{srcPath: "/var/app/*.log", expectedBase: "/var/app", expectedGlob: []string{"*.log"}},
+		{srcPath: "/var/*/logs/app.[0-9]", expectedBase: "/var", expectedGlob: []string{"*", "logs", "app.[0-9]"}},
+		{srcPath: "/*.log", expectedBase: "/", expectedGlob: []string{"*.log"}},
+		{srcPath: "logs/?/", expectedBase: "logs", expectedGlob: []string{"?"}},
+	}
+	for _, tc := range testCases {
+		filter, err := newCopyFilter(tc.srcPath, nil, noSuchPath)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedBase, filter.base, tc.srcPath)
+		assert.Equal(t, tc.expectedGlob, filter.glob, tc.srcPath)
+	}
+}
+
+func noSuchPath(string) bool {
+	return false
+}
+
+func TestNewCopyFilterLiteralPath(t *testing.T) {
+	exists := func(path string) bool {
+		return path == "/data/file[1].txt"
+	}
+	filter, err := newCopyFilter("/data/file[1].txt", nil, exists)
+	require.NoError(t, err)
+	assert.False(t, filter.isGlob())
+	assert.Equal(t, "/data/file[1].txt", filter.base)
+
+	filter, err = newCopyFilter("/data/file[2].txt", nil, exists)
+	require.NoError(t, err)
+	assert.True(t, filter.isGlob())
+	assert.Equal(t, "/data", filter.base)
+	assert.Equal(t, []string{"file[2].txt"}, filter.glob)
+}
+
+func TestNewCopyFilterInvalidPattern(t *testing.T) {
+	_, err := newCopyFilter("/var/app/[.log", nil, noSuchPath)
+	testutil.ErrorContains(t, err, "invalid pattern")
+
+	_, err = newCopyFilter("/var/app", []string{"[a-"}, noSuchPath)
+	testutil.ErrorContains(t, err, "invalid exclude pattern")
+}
+
+func TestCopyFilterApply(t *testing.T) {
+	entries := []string{
+		"app/",
+		"app/out.log",
+		"app/out.log.1",
+		"app/cache/",
+		"app/cache/data.bin",
+		"app/svc/",
+		"app/svc/err.log",
+		"app/svc/err.log.1",
+	}
+
+	testCases := []struct {
+		srcPath  string
+		excludes []string
+		expected []string
+	}{
+		{
+			srcPath:  "/var/app",
+			expected: entries,
+		},
+		{
+			srcPath:  "/var/app",
+			excludes: []string{"*.1", "cache"},
+			expected: []string{"app/", "app/out.log", "app/svc/", "app/svc/err.log"},
+		},
+		{
+			srcPath:  "/var/app",
+			excludes: []string{"svc/*.log"},
+			expected: []string{"app/", "app/out.log", "app/out.log.1", "app/cache/", "app/cache/data.bin", "app/svc/", "app/svc/err.log.1"},
+		},
+		{
+			srcPath:  "/var/app/*.log*",
+			expected: []string{"out.log", "out.log.1"},
+		},
+		{
+			srcPath:  "/var/app/*/*.log",
+			expected: []string{"svc/err.log"},
+		},
+		{
+			srcPath:  "/var/app/*",
+			excludes: []string{"*.1", "cache"},
+			expected: []string{"out.log", "svc/", "svc/err.log"},
+		},
+	}
+	for _, tc := range testCases {
+		filter, err := newCopyFilter(tc.srcPath, tc.excludes, noSuchPath)
+		require.NoError(t, err)
+
+		names, err := archiveEntries(filter.apply(newTestArchive(t, entries)))
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, names, tc.srcPath)
+	}
+}
+
+func TestCopyFilterApplyNoMatch(t *testing.T) {
+	filter, err := newCopyFilter("/var/app/*.txt", nil, noSuchPath)
+	require.NoError(t, err)
+
+	_, err = archiveEntries(filter.apply(newTestArchive(t, []string{"app/", "app/out.log"})))
+	testutil.ErrorContains(t, err, `no such file or directory matching "/var/app/*.txt"`)
+}
+
+func newTestArchive(t *testing.T, names []string) io.Reader {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0644, Typeflag: tar.TypeReg}
+		content := []byte("content of " + name)
+		if name[len(name)-1] == '/' {
+			hdr.Mode, hdr.Typeflag, content = 0755, tar.TypeDir, nil
+		}
+		hdr.Size = int64(len(content))
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf
+}
+
+func archiveEntries(r io.ReadCloser) ([]string, error) {
+	defer r.Close()
+
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(ioutil.Discard, tr); err != nil {
+			return nil, err
+		}
+		names = append(names, hdr.Name)
+	}
+}
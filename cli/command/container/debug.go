@@ -0,0 +1,204 @@
+package container
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	networktypes "github.com/docker/docker/api/types/network"
+	apiclient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/term"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+const (
+	defaultDebugImage      = "busybox"
+	defaultDebugTargetPath = "/target"
+)
+
+type debugOptions struct {
+	image      string
+	targetPath string
+	privileged bool
+	detachKeys string
+	container  string
+	command    []string
+}
+
+// NewDebugCommand creates a new cobra.Command for `docker container debug`
+func NewDebugCommand(dockerCli *command.DockerCli) *cobra.Command {
+	var opts debugOptions
+
+	cmd := &cobra.Command{
+		Use:   "debug [OPTIONS] CONTAINER [COMMAND] [ARG...]",
+		Short: "Attach a toolbox container to the namespaces of a running container",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			opts.command = args[1:]
+			return runDebug(dockerCli, &opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+
+	flags.StringVar(&opts.image, "image", defaultDebugImage, "Image to run the debug session in")
+	flags.StringVar(&opts.targetPath, "target-path", defaultDebugTargetPath, "Path to mount the root filesystem of the container at")
+	flags.BoolVar(&opts.privileged, "privileged", false, "Give extended privileges to the debug session")
+	flags.StringVar(&opts.detachKeys, "detach-keys", "", "Override the key sequence for detaching from the debug session")
+
+	return cmd
+}
+
+func runDebug(dockerCli *command.DockerCli, opts *debugOptions) error {
+	ctx, cancelFun := context.WithCancel(context.Background())
+	defer cancelFun()
+	client := dockerCli.Client()
+	stderr := dockerCli.Err()
+
+	target, err := client.ContainerInspect(ctx, opts.container)
+	if err != nil {
+		return err
+	}
+
+	containerConfig, err := buildDebugConfig(target, opts, dockerCli.In().IsTerminal())
+	if err != nil {
+		return err
+	}
+	config := containerConfig.Config
+	if err := dockerCli.In().CheckTty(config.AttachStdin, config.Tty); err != nil {
+		return err
+	}
+	if len(containerConfig.HostConfig.Mounts) == 0 {
+		fmt.Fprintln(stderr, "The root filesystem of the container can not be mounted, it is available at /proc/1/root instead.")
+	}
+	if !containerConfig.HostConfig.IpcMode.IsContainer() {
+		fmt.Fprintln(stderr, "The IPC namespace of the container is not shareable, the debug session uses its own.")
+	}
+
+	if runtime.GOOS == "windows" {
+		containerConfig.HostConfig.ConsoleSize[0], containerConfig.HostConfig.ConsoleSize[1] = dockerCli.Out().GetTtySize()
+	}
+
+	createResponse, err := createContainer(ctx, dockerCli, containerConfig, "")
+	if err != nil {
+		return err
+	}
+	// The container is removed by the daemon once the session ends, but make
+	// sure it does not outlive the session if we detach or fail to start it.
+	defer func() {
+		err := client.ContainerRemove(context.Background(), createResponse.ID, types.ContainerRemoveOptions{Force: true})
+		if err != nil && !apiclient.IsErrNotFound(err) {
+			logrus.Debugf("Error removing debug container: %s", err)
+		}
+	}()
+
+	if !config.Tty {
+		sigc := ForwardAllSignals(ctx, dockerCli, createResponse.ID)
+		defer signal.StopCatch(sigc)
+	}
+
+	if opts.detachKeys != "" {
+		dockerCli.ConfigFile().DetachKeys = opts.detachKeys
+	}
+	var errCh chan error
+	closeFn, err := attachContainer(ctx, dockerCli, &errCh, config, createResponse.ID)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	statusChan := waitExitOrRemoved(ctx, dockerCli, createResponse.ID, true)
+
+	if err := client.ContainerStart(ctx, createResponse.ID, types.ContainerStartOptions{}); err != nil {
+		cancelFun()
+		<-errCh
+		return err
+	}
+
+	if config.Tty && dockerCli.Out().IsTerminal() {
+		if err := MonitorTtySize(ctx, dockerCli, createResponse.ID, false); err != nil {
+			fmt.Fprintln(stderr, "Error monitoring TTY size:", err)
+		}
+	}
+
+	if err := <-errCh; err != nil {
+		if _, ok := err.(term.EscapeError); ok {
+			// The user entered the detach escape sequence, which ends the
+			// session.
+			return nil
+		}
+
+		logrus.Debugf("Error hijack: %s", err)
+		return err
+	}
+
+	if status := <-statusChan; status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// buildDebugConfig returns the configuration of a container that joins the
+// PID, network and IPC namespaces of the given target container.
+func buildDebugConfig(target types.ContainerJSON, opts *debugOptions, tty bool) (*containerConfig, error) {
+	if target.State == nil || !target.State.Running {
+		return nil, errors.Errorf("container %s is not running", opts.container)
+	}
+	if target.Platform == "windows" {
+		return nil, errors.New("debugging Windows containers is not supported")
+	}
+
+	mode := "container:" + target.ID
+	hostConfig := &container.HostConfig{
+		PidMode:     container.PidMode(mode),
+		NetworkMode: container.NetworkMode(mode),
+		Privileged:  opts.privileged,
+		CapAdd:      []string{"SYS_PTRACE"},
+		AutoRemove:  true,
+	}
+
+	// The IPC namespace of a container can only be joined if it was made
+	// shareable.
+	if target.HostConfig != nil {
+		if ipcMode := target.HostConfig.IpcMode; !ipcMode.IsPrivate() && !ipcMode.IsNone() {
+			hostConfig.IpcMode = container.IpcMode(mode)
+		}
+	}
+
+	// Mounting the root filesystem requires it to be a directory on the
+	// daemon host, which is the case for the overlay storage drivers.
+	if mergedDir := target.GraphDriver.Data["MergedDir"]; mergedDir != "" && opts.targetPath != "" {
+		hostConfig.Mounts = []mount.Mount{{
+			Type:   mount.TypeBind,
+			Source: mergedDir,
+			Target: opts.targetPath,
+		}}
+	}
+
+	config := &container.Config{
+		Image:        opts.image,
+		Cmd:          opts.command,
+		Tty:          tty,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+
+	return &containerConfig{
+		Config:           config,
+		HostConfig:       hostConfig,
+		NetworkingConfig: &networktypes.NetworkingConfig{},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebugTarget(running bool, ipcMode container.IpcMode, graphDriverData map[string]string) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:          "target-id",
+			State:       &types.ContainerState{Running: running},
+			HostConfig:  &container.HostConfig{IpcMode: ipcMode},
+			GraphDriver: types.GraphDriverData{Name: "overlay2", Data: graphDriverData},
+		},
+	}
+}
+
+func TestBuildDebugConfig(t *testing.T) {
+	opts := &debugOptions{
+		image:      "busybox",
+		targetPath: "/target",
+		container:  "target",
+		command:    []string{"sh", "-l"},
+	}
+	target := newDebugTarget(true, container.IpcMode("shareable"), map[string]string{"MergedDir": "/var/lib/docker/overlay2/abc/merged"})
+
+	config, err := buildDebugConfig(target, opts, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "busybox", config.Config.Image)
+	assert.Equal(t, []string{"sh", "-l"}, []string(config.Config.Cmd))
+	assert.True(t, config.Config.Tty)
+	assert.True(t, config.Config.AttachStdin)
+
+	hostConfig := config.HostConfig
+	assert.Equal(t, container.PidMode("container:target-id"), hostConfig.PidMode)
+	assert.Equal(t, container.NetworkMode("container:target-id"), hostConfig.NetworkMode)
+	assert.Equal(t, container.IpcMode("container:target-id"), hostConfig.IpcMode)
+	assert.True(t, hostConfig.AutoRemove)
+	assert.Equal(t, []mount.Mount{{
+		Type:   mount.TypeBind,
+		Source: "/var/lib/docker/overlay2/abc/merged",
+		Target: "/target",
+	}}, hostConfig.Mounts)
+}
+
+func TestBuildDebugConfigWithoutSharedNamespaces(t *testing.T) {
+	opts := &debugOptions{image: "busybox", targetPath: "/target", container: "target"}
+	target := newDebugTarget(true, container.IpcMode("private"), nil)
+
+	config, err := buildDebugConfig(target, opts, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, container.IpcMode(""), config.HostConfig.IpcMode)
+	assert.Len(t, config.HostConfig.Mounts, 0)
+	assert.False(t, config.Config.Tty)
+}
+
+func TestBuildDebugConfigNotRunning(t *testing.T) {
+	opts := &debugOptions{image: "busybox", container: "target"}
+
+	_, err := buildDebugConfig(newDebugTarget(false, "", nil), opts, false)
+	testutil.ErrorContains(t, err, "container target is not running")
+}
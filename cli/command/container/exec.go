@@ -65,14 +65,20 @@ func NewExecCommand(dockerCli command.Cli) *cobra.Command {
 
 func runExec(dockerCli command.Cli, options execOptions) error {
 	execConfig := parseExec(options, dockerCli.ConfigFile())
-	ctx := context.Background()
+	return ExecInContainer(context.Background(), dockerCli, options.container, execConfig)
+}
+
+// ExecInContainer runs a command in a running container, and attaches to it
+// unless it is detached. It returns a cli.StatusError if the command exits
+// with a non-zero status.
+func ExecInContainer(ctx context.Context, dockerCli command.Cli, container string, execConfig *types.ExecConfig) error {
 	client := dockerCli.Client()
 
 	// We need to check the tty _before_ we do the ContainerExecCreate, because
 	// otherwise if we error out we will leak execIDs on the server (and
 	// there's no easy way to clean those up). But also in order to make "not
 	// exist" errors take precedence we do a dummy inspect first.
-	if _, err := client.ContainerInspect(ctx, options.container); err != nil {
+	if _, err := client.ContainerInspect(ctx, container); err != nil {
 		return err
 	}
 	if !execConfig.Detach {
@@ -81,7 +87,7 @@ func runExec(dockerCli command.Cli, options execOptions) error {
 		}
 	}
 
-	response, err := client.ContainerExecCreate(ctx, options.container, *execConfig)
+	response, err := client.ContainerExecCreate(ctx, container, *execConfig)
 	if err != nil {
 		return err
 	}
@@ -176,26 +182,55 @@ func getExecExitStatus(ctx context.Context, client apiclient.ContainerAPIClient,
 // parseExec parses the specified args for the specified command and generates
 // an ExecConfig from it.
 func parseExec(opts execOptions, configFile *configfile.ConfigFile) *types.ExecConfig {
+	return NewExecConfig(ExecOptions{
+		User:        opts.user,
+		Privileged:  opts.privileged,
+		Tty:         opts.tty,
+		Interactive: opts.interactive,
+		Detach:      opts.detach,
+		DetachKeys:  opts.detachKeys,
+		Env:         opts.env.GetAll(),
+		Command:     opts.command,
+	}, configFile)
+}
+
+// ExecOptions are the options of a command that is run in a running
+// container.
+type ExecOptions struct {
+	User        string
+	Privileged  bool
+	Tty         bool
+	Interactive bool
+	Detach      bool
+	DetachKeys  string
+	Env         []string
+	Command     []string
+}
+
+// NewExecConfig generates the ExecConfig of a command that is run in a
+// running container. It is shared by the commands that run a command in a
+// container, so that they handle their options the same way.
+func NewExecConfig(options ExecOptions, configFile *configfile.ConfigFile) *types.ExecConfig {
 	execConfig := &types.ExecConfig{
-		User:       opts.user,
-		Privileged: opts.privileged,
-		Tty:        opts.tty,
-		Cmd:        opts.command,
-		Detach:     opts.detach,
-		Env:        opts.env.GetAll(),
+		User:       options.User,
+		Privileged: options.Privileged,
+		Tty:        options.Tty,
+		Cmd:        options.Command,
+		Detach:     options.Detach,
+		Env:        options.Env,
 	}
 
 	// If -d is not set, attach to everything by default
-	if !opts.detach {
+	if !options.Detach {
 		execConfig.AttachStdout = true
 		execConfig.AttachStderr = true
-		if opts.interactive {
+		if options.Interactive {
 			execConfig.AttachStdin = true
 		}
 	}
 
-	if opts.detachKeys != "" {
-		execConfig.DetachKeys = opts.detachKeys
+	if options.DetachKeys != "" {
+		execConfig.DetachKeys = options.DetachKeys
 	} else {
 		execConfig.DetachKeys = configFile.DetachKeys
 	}
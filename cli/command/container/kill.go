@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -13,18 +12,19 @@ import (
 
 type killOptions struct {
 	signal string
+	bulk   bulkOptions
 
 	containers []string
 }
 
 // NewKillCommand creates a new cobra.Command for `docker kill`
 func NewKillCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts killOptions
+	opts := killOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "kill [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Kill one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.requiresContainers(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runKill(dockerCli, &opts)
@@ -33,16 +33,26 @@ func NewKillCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.signal, "signal", "s", "KILL", "Signal to send to the container")
+	addBulkFlags(flags, &opts.bulk, true)
 	return cmd
 }
 
 func runKill(dockerCli *command.DockerCli, opts *killOptions) error {
 	var errs []string
 	ctx := context.Background()
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+
+	containers, err := selectContainers(ctx, dockerCli, opts.containers, opts.bulk, false)
+	if err != nil {
+		return err
+	}
+	if !confirmBulkOperation(dockerCli, "kill", containers, opts.bulk) {
+		return nil
+	}
+
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, container string) error {
 		return dockerCli.Client().ContainerKill(ctx, container, opts.signal)
 	})
-	for _, name := range opts.containers {
+	for _, name := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 		} else {
@@ -1,6 +1,7 @@
 package container
 
 import (
+	"io"
 	"io/ioutil"
 
 	"github.com/docker/cli/cli"
@@ -9,6 +10,8 @@ import (
 	"github.com/docker/cli/opts"
 	"github.com/docker/cli/templates"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
@@ -22,6 +25,7 @@ type psOptions struct {
 	last    int
 	format  string
 	filter  opts.FilterOpt
+	watch   bool
 }
 
 // NewPsCommand creates a new cobra.Command for `docker ps`
@@ -47,6 +51,7 @@ func NewPsCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.IntVarP(&options.last, "last", "n", -1, "Show n last created containers (includes all states)")
 	flags.StringVarP(&options.format, "format", "", "", "Pretty-print containers using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.watch, "watch", false, "Redraw the list whenever containers change")
 
 	return cmd
 }
@@ -117,11 +122,6 @@ func runPs(dockerCli *command.DockerCli, options *psOptions) error {
 		return err
 	}
 
-	containers, err := dockerCli.Client().ContainerList(ctx, *listOptions)
-	if err != nil {
-		return err
-	}
-
 	format := options.format
 	if len(format) == 0 {
 		if len(dockerCli.ConfigFile().PsFormat) > 0 && !options.quiet {
@@ -131,10 +131,33 @@ func runPs(dockerCli *command.DockerCli, options *psOptions) error {
 		}
 	}
 
-	containerCtx := formatter.Context{
+	containerFormat := formatter.NewContainerFormat(format, options.quiet, listOptions.Size)
+	render := func(out io.Writer) error {
+		containers, err := dockerCli.Client().ContainerList(ctx, *listOptions)
+		if err != nil {
+			return err
+		}
+
+		containerCtx := formatter.Context{
+			Output: out,
+			Format: containerFormat,
+			Trunc:  !options.noTrunc,
+		}
+		return formatter.ContainerWrite(containerCtx, containers)
+	}
+
+	if !options.watch {
+		return render(dockerCli.Out())
+	}
+
+	eventFilters := filters.NewArgs()
+	eventFilters.Add("type", events.ContainerEventType)
+	eventC, errC := dockerCli.Client().Events(ctx, types.EventsOptions{Filters: eventFilters})
+
+	watchCtx := formatter.WatchContext{
 		Output: dockerCli.Out(),
-		Format: formatter.NewContainerFormat(format, options.quiet, listOptions.Size),
-		Trunc:  !options.noTrunc,
+		Format: containerFormat,
+		Render: render,
 	}
-	return formatter.ContainerWrite(containerCtx, containers)
+	return watchCtx.Watch(ctx, eventC, errC)
 }
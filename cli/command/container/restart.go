@@ -5,7 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -15,18 +14,19 @@ import (
 type restartOptions struct {
 	nSeconds        int
 	nSecondsChanged bool
+	bulk            bulkOptions
 
 	containers []string
 }
 
 // NewRestartCommand creates a new cobra.Command for `docker restart`
 func NewRestartCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts restartOptions
+	opts := restartOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "restart [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Restart one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.requiresContainers(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			opts.nSecondsChanged = cmd.Flags().Changed("time")
@@ -36,6 +36,7 @@ func NewRestartCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.nSeconds, "time", "t", 10, "Seconds to wait for stop before killing the container")
+	addBulkFlags(flags, &opts.bulk, true)
 	return cmd
 }
 
@@ -48,8 +49,19 @@ func runRestart(dockerCli *command.DockerCli, opts *restartOptions) error {
 		timeout = &timeoutValue
 	}
 
-	for _, name := range opts.containers {
-		if err := dockerCli.Client().ContainerRestart(ctx, name, timeout); err != nil {
+	containers, err := selectContainers(ctx, dockerCli, opts.containers, opts.bulk, true)
+	if err != nil {
+		return err
+	}
+	if !confirmBulkOperation(dockerCli, "restart", containers, opts.bulk) {
+		return nil
+	}
+
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, id string) error {
+		return dockerCli.Client().ContainerRestart(ctx, id, timeout)
+	})
+	for _, name := range containers {
+		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
 		}
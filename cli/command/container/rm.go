@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
@@ -16,20 +15,22 @@ type rmOptions struct {
 	rmVolumes bool
 	rmLink    bool
 	force     bool
+	bulk      bulkOptions
 
 	containers []string
 }
 
 // NewRmCommand creates a new cobra.Command for `docker rm`
 func NewRmCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts rmOptions
+	opts := rmOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "rm [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Remove one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.requiresContainers(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
+			opts.bulk.force = opts.force
 			return runRm(dockerCli, &opts)
 		},
 	}
@@ -38,12 +39,21 @@ func NewRmCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVarP(&opts.rmVolumes, "volumes", "v", false, "Remove the volumes associated with the container")
 	flags.BoolVarP(&opts.rmLink, "link", "l", false, "Remove the specified link")
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force the removal of a running container (uses SIGKILL)")
+	addBulkFlags(flags, &opts.bulk, false)
 	return cmd
 }
 
 func runRm(dockerCli *command.DockerCli, opts *rmOptions) error {
 	ctx := context.Background()
 
+	containers, err := selectContainers(ctx, dockerCli, opts.containers, opts.bulk, true)
+	if err != nil {
+		return err
+	}
+	if !confirmBulkOperation(dockerCli, "remove", containers, opts.bulk) {
+		return nil
+	}
+
 	var errs []string
 	options := types.ContainerRemoveOptions{
 		RemoveVolumes: opts.rmVolumes,
@@ -51,7 +61,7 @@ func runRm(dockerCli *command.DockerCli, opts *rmOptions) error {
 		Force:         opts.force,
 	}
 
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, container string) error {
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, container string) error {
 		container = strings.Trim(container, "/")
 		if container == "" {
 			return errors.New("Container name cannot be empty")
@@ -59,7 +69,7 @@ func runRm(dockerCli *command.DockerCli, opts *rmOptions) error {
 		return dockerCli.Client().ContainerRemove(ctx, container, options)
 	})
 
-	for _, name := range opts.containers {
+	for _, name := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
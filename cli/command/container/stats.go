@@ -109,7 +109,7 @@ func runStats(dockerCli *command.DockerCli, opts *statsOptions) error {
 			s := formatter.NewContainerStats(container.ID[:12])
 			if cStats.add(s) {
 				waitFirst.Add(1)
-				go collect(ctx, s, dockerCli.Client(), !opts.noStream, waitFirst)
+				go CollectStats(ctx, s, dockerCli.Client(), "", !opts.noStream, waitFirst)
 			}
 		}
 	}
@@ -126,7 +126,7 @@ func runStats(dockerCli *command.DockerCli, opts *statsOptions) error {
 				s := formatter.NewContainerStats(e.ID[:12])
 				if cStats.add(s) {
 					waitFirst.Add(1)
-					go collect(ctx, s, dockerCli.Client(), !opts.noStream, waitFirst)
+					go CollectStats(ctx, s, dockerCli.Client(), "", !opts.noStream, waitFirst)
 				}
 			}
 		})
@@ -135,7 +135,7 @@ func runStats(dockerCli *command.DockerCli, opts *statsOptions) error {
 			s := formatter.NewContainerStats(e.ID[:12])
 			if cStats.add(s) {
 				waitFirst.Add(1)
-				go collect(ctx, s, dockerCli.Client(), !opts.noStream, waitFirst)
+				go CollectStats(ctx, s, dockerCli.Client(), "", !opts.noStream, waitFirst)
 			}
 		})
 
@@ -161,7 +161,7 @@ func runStats(dockerCli *command.DockerCli, opts *statsOptions) error {
 			s := formatter.NewContainerStats(name)
 			if cStats.add(s) {
 				waitFirst.Add(1)
-				go collect(ctx, s, dockerCli.Client(), !opts.noStream, waitFirst)
+				go CollectStats(ctx, s, dockerCli.Client(), "", !opts.noStream, waitFirst)
 			}
 		}
 
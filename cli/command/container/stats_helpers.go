@@ -52,7 +52,11 @@ func (s *stats) isKnownContainer(cid string) (int, bool) {
 	return -1, false
 }
 
-func collect(ctx context.Context, s *formatter.ContainerStats, cli client.APIClient, streamStats bool, waitFirst *sync.WaitGroup) {
+// CollectStats collects the resource usage statistics of a container in s,
+// streaming them if streamStats is set. osType is the OS of the daemon the
+// container runs on; if it is empty, it is the OS reported by the daemon.
+// waitFirst is done once the first statistics are received, or on error.
+func CollectStats(ctx context.Context, s *formatter.ContainerStats, cli client.APIClient, osType string, streamStats bool, waitFirst *sync.WaitGroup) {
 	logrus.Debugf("collecting stats for %s", s.Container)
 	var (
 		getFirst       bool
@@ -97,9 +101,13 @@ func collect(ctx context.Context, s *formatter.ContainerStats, cli client.APICli
 				continue
 			}
 
-			daemonOSType = response.OSType
+			statsOSType := osType
+			if statsOSType == "" {
+				daemonOSType = response.OSType
+				statsOSType = response.OSType
+			}
 
-			if daemonOSType != "windows" {
+			if statsOSType != "windows" {
 				previousCPU = v.PreCPUStats.CPUUsage.TotalUsage
 				previousSystem = v.PreCPUStats.SystemUsage
 				cpuPercent = calculateCPUPercentUnix(previousCPU, previousSystem, v)
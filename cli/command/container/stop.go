@@ -5,7 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -15,18 +14,19 @@ import (
 type stopOptions struct {
 	time        int
 	timeChanged bool
+	bulk        bulkOptions
 
 	containers []string
 }
 
 // NewStopCommand creates a new cobra.Command for `docker stop`
 func NewStopCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts stopOptions
+	opts := stopOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
 		Use:   "stop [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Stop one or more running containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.requiresContainers(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			opts.timeChanged = cmd.Flags().Changed("time")
@@ -36,6 +36,7 @@ func NewStopCommand(dockerCli *command.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.time, "time", "t", 10, "Seconds to wait for stop before killing it")
+	addBulkFlags(flags, &opts.bulk, true)
 	return cmd
 }
 
@@ -48,12 +49,20 @@ func runStop(dockerCli *command.DockerCli, opts *stopOptions) error {
 		timeout = &timeoutValue
 	}
 
+	containers, err := selectContainers(ctx, dockerCli, opts.containers, opts.bulk, false)
+	if err != nil {
+		return err
+	}
+	if !confirmBulkOperation(dockerCli, "stop", containers, opts.bulk) {
+		return nil
+	}
+
 	var errs []string
 
-	errChan := parallelOperation(ctx, opts.containers, func(ctx context.Context, id string) error {
+	errChan := parallelOperation(ctx, containers, func(ctx context.Context, id string) error {
 		return dockerCli.Client().ContainerStop(ctx, id, timeout)
 	})
-	for _, container := range opts.containers {
+	for _, container := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
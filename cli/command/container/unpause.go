@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -12,31 +11,43 @@ import (
 )
 
 type unpauseOptions struct {
+	bulk bulkOptions
+
 	containers []string
 }
 
 // NewUnpauseCommand creates a new cobra.Command for `docker unpause`
 func NewUnpauseCommand(dockerCli *command.DockerCli) *cobra.Command {
-	var opts unpauseOptions
+	opts := unpauseOptions{bulk: newBulkOptions()}
 
 	cmd := &cobra.Command{
-		Use:   "unpause CONTAINER [CONTAINER...]",
+		Use:   "unpause [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Unpause all processes within one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args:  opts.bulk.requiresContainers(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.containers = args
 			return runUnpause(dockerCli, &opts)
 		},
 	}
+
+	addBulkFlags(cmd.Flags(), &opts.bulk, true)
 	return cmd
 }
 
 func runUnpause(dockerCli *command.DockerCli, opts *unpauseOptions) error {
 	ctx := context.Background()
 
+	containers, err := selectContainers(ctx, dockerCli, opts.containers, opts.bulk, false)
+	if err != nil {
+		return err
+	}
+	if !confirmBulkOperation(dockerCli, "unpause", containers, opts.bulk) {
+		return nil
+	}
+
 	var errs []string
-	errChan := parallelOperation(ctx, opts.containers, dockerCli.Client().ContainerUnpause)
-	for _, container := range opts.containers {
+	errChan := parallelOperation(ctx, containers, dockerCli.Client().ContainerUnpause)
+	for _, container := range containers {
 		if err := <-errChan; err != nil {
 			errs = append(errs, err.Error())
 			continue
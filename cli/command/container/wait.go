@@ -3,24 +3,42 @@ package container
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
+// waitConditionHealthy is used to wait for a container to become healthy. It
+// is not a condition that is supported by the daemon, but is implemented by
+// watching the health_status events of the container.
+const waitConditionHealthy container.WaitCondition = "healthy"
+
 type waitOptions struct {
+	condition  string
+	timeout    time.Duration
 	containers []string
 }
 
+// waitResult holds the final state of a container that was waited for.
+type waitResult struct {
+	state string
+	err   error
+}
+
 // NewWaitCommand creates a new cobra.Command for `docker wait`
-func NewWaitCommand(dockerCli *command.DockerCli) *cobra.Command {
+func NewWaitCommand(dockerCli command.Cli) *cobra.Command {
 	var opts waitOptions
 
 	cmd := &cobra.Command{
-		Use:   "wait CONTAINER [CONTAINER...]",
+		Use:   "wait [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Block until one or more containers stop, then print their exit codes",
 		Args:  cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -29,25 +47,123 @@ func NewWaitCommand(dockerCli *command.DockerCli) *cobra.Command {
 		},
 	}
 
+	flags := cmd.Flags()
+	flags.StringVar(&opts.condition, "condition", string(container.WaitConditionNotRunning), "Condition to wait for (not-running, next-exit, removed, healthy)")
+	flags.SetAnnotation("condition", "version", []string{"1.30"})
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Maximum duration to wait for (0 waits indefinitely)")
+
 	return cmd
 }
 
-func runWait(dockerCli *command.DockerCli, opts *waitOptions) error {
+func runWait(dockerCli command.Cli, opts *waitOptions) error {
+	condition := container.WaitCondition(opts.condition)
+	switch condition {
+	case container.WaitConditionNotRunning, container.WaitConditionNextExit, container.WaitConditionRemoved, waitConditionHealthy:
+	default:
+		return errors.Errorf("invalid condition %q: must be one of not-running, next-exit, removed or healthy", opts.condition)
+	}
+
 	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
 
-	var errs []string
-	for _, container := range opts.containers {
-		resultC, errC := dockerCli.Client().ContainerWait(ctx, container, "")
+	// Wait for all containers at once, so that no state change is missed
+	// while waiting for another container.
+	results := make([]chan waitResult, len(opts.containers))
+	for i, name := range opts.containers {
+		results[i] = make(chan waitResult, 1)
+		go func(name string, resultC chan<- waitResult) {
+			var result waitResult
+			if condition == waitConditionHealthy {
+				result = waitHealthy(ctx, dockerCli, name)
+			} else {
+				result = waitExit(ctx, dockerCli, name, condition)
+			}
+			resultC <- result
+		}(name, results[i])
+	}
 
-		select {
-		case result := <-resultC:
-			fmt.Fprintf(dockerCli.Out(), "%d\n", result.StatusCode)
-		case err := <-errC:
-			errs = append(errs, err.Error())
+	var errs []string
+	for _, resultC := range results {
+		result := <-resultC
+		if result.err != nil {
+			errs = append(errs, result.err.Error())
+			continue
 		}
+		fmt.Fprintln(dockerCli.Out(), result.state)
 	}
 	if len(errs) > 0 {
 		return errors.New(strings.Join(errs, "\n"))
 	}
 	return nil
 }
+
+// waitExit waits for the container to reach one of the conditions handled by
+// the daemon, and returns its exit code as final state.
+func waitExit(ctx context.Context, dockerCli command.Cli, name string, condition container.WaitCondition) waitResult {
+	resultC, errC := dockerCli.Client().ContainerWait(ctx, name, condition)
+
+	select {
+	case result := <-resultC:
+		return waitResult{state: fmt.Sprintf("%d", result.StatusCode)}
+	case err := <-errC:
+		if ctx.Err() == context.DeadlineExceeded {
+			return waitResult{err: errors.Errorf("timed out waiting for container %s", name)}
+		}
+		return waitResult{err: err}
+	}
+}
+
+// waitHealthy waits for the health status of the container to become
+// healthy. It fails if the container has no health check, or stops before it
+// becomes healthy.
+func waitHealthy(ctx context.Context, dockerCli command.Cli, name string) waitResult {
+	client := dockerCli.Client()
+
+	c, err := client.ContainerInspect(ctx, name)
+	if err != nil {
+		return waitResult{err: err}
+	}
+
+	// Subscribe to events before checking the current state, so that no
+	// health status change can be missed.
+	eventCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	f := filters.NewArgs()
+	f.Add("type", events.ContainerEventType)
+	f.Add("container", c.ID)
+	eventq, errq := client.Events(eventCtx, types.EventsOptions{Filters: f})
+
+	c, err = client.ContainerInspect(ctx, c.ID)
+	if err != nil {
+		return waitResult{err: err}
+	}
+	if c.State == nil || c.State.Health == nil {
+		return waitResult{err: errors.Errorf("container %s has no health check", name)}
+	}
+	if !c.State.Running {
+		return waitResult{err: errors.Errorf("container %s is not running", name)}
+	}
+
+	status := c.State.Health.Status
+	for status != types.Healthy {
+		select {
+		case e := <-eventq:
+			switch {
+			case strings.HasPrefix(e.Action, "health_status:"):
+				status = strings.TrimSpace(strings.TrimPrefix(e.Action, "health_status:"))
+			case e.Action == "die", e.Action == "destroy":
+				return waitResult{err: errors.Errorf("container %s stopped before it became healthy", name)}
+			}
+		case err := <-errq:
+			if ctx.Err() == context.DeadlineExceeded {
+				return waitResult{err: errors.Errorf("timed out waiting for container %s to become healthy (status: %s)", name, status)}
+			}
+			return waitResult{err: err}
+		}
+	}
+	return waitResult{state: status}
+}
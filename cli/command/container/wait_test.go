@@ -0,0 +1,160 @@
+package container
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func waitFn(statusCodes map[string]int64) func(string, container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+	return func(name string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+		resultC := make(chan container.ContainerWaitOKBody, 1)
+		errC := make(chan error, 1)
+		if code, ok := statusCodes[name]; ok {
+			resultC <- container.ContainerWaitOKBody{StatusCode: code}
+		} else {
+			errC <- errors.Errorf("No such container: %s", name)
+		}
+		return resultC, errC
+	}
+}
+
+func healthInspectFn(running bool, health *types.Health) func(string) (types.ContainerJSON, error) {
+	return func(name string) (types.ContainerJSON, error) {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    name,
+				State: &types.ContainerState{Running: running, Health: health},
+			},
+		}, nil
+	}
+}
+
+func eventsFn(messages ...events.Message) func(context.Context, types.EventsOptions) (<-chan events.Message, <-chan error) {
+	return func(ctx context.Context, _ types.EventsOptions) (<-chan events.Message, <-chan error) {
+		eventC := make(chan events.Message, len(messages))
+		errC := make(chan error, 1)
+		for _, message := range messages {
+			eventC <- message
+		}
+		go func() {
+			<-ctx.Done()
+			errC <- ctx.Err()
+		}()
+		return eventC, errC
+	}
+}
+
+func TestWaitNotRunning(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		conditions []container.WaitCondition
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		containerWaitFunc: func(name string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+			mu.Lock()
+			conditions = append(conditions, condition)
+			mu.Unlock()
+			return waitFn(map[string]int64{"foo": 0, "bar": 3})(name, condition)
+		},
+	})
+	cmd := NewWaitCommand(cli)
+	cmd.SetArgs([]string{"foo", "bar"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, "0\n3\n", cli.OutBuffer().String())
+	assert.Equal(t, []container.WaitCondition{container.WaitConditionNotRunning, container.WaitConditionNotRunning}, conditions)
+}
+
+func TestWaitErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerWaitFunc: waitFn(map[string]int64{"foo": 1}),
+	})
+	cmd := NewWaitCommand(cli)
+	cmd.SetArgs([]string{"foo", "missing"})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), "No such container: missing")
+	assert.Equal(t, "1\n", cli.OutBuffer().String())
+}
+
+func TestWaitInvalidCondition(t *testing.T) {
+	cmd := NewWaitCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--condition", "started", "foo"})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), `invalid condition "started"`)
+}
+
+func TestWaitHealthy(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: healthInspectFn(true, &types.Health{Status: types.Starting}),
+		eventsFunc: eventsFn(
+			events.Message{Action: "exec_start: /healthcheck"},
+			events.Message{Action: "health_status: unhealthy"},
+			events.Message{Action: "health_status: healthy"},
+		),
+	})
+	cmd := NewWaitCommand(cli)
+	cmd.SetArgs([]string{"--condition", "healthy", "foo"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "healthy\n", cli.OutBuffer().String())
+}
+
+func TestWaitHealthyErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		inspectFunc   func(string) (types.ContainerJSON, error)
+		eventsFunc    func(context.Context, types.EventsOptions) (<-chan events.Message, <-chan error)
+		expectedError string
+	}{
+		{
+			name:          "no-healthcheck",
+			args:          []string{"foo"},
+			inspectFunc:   healthInspectFn(true, nil),
+			eventsFunc:    eventsFn(),
+			expectedError: "container foo has no health check",
+		},
+		{
+			name:          "not-running",
+			args:          []string{"foo"},
+			inspectFunc:   healthInspectFn(false, &types.Health{Status: types.Unhealthy}),
+			eventsFunc:    eventsFn(),
+			expectedError: "container foo is not running",
+		},
+		{
+			name:          "died",
+			args:          []string{"foo"},
+			inspectFunc:   healthInspectFn(true, &types.Health{Status: types.Starting}),
+			eventsFunc:    eventsFn(events.Message{Action: "die"}),
+			expectedError: "container foo stopped before it became healthy",
+		},
+		{
+			name:          "timeout",
+			args:          []string{"--timeout", "10ms", "foo"},
+			inspectFunc:   healthInspectFn(true, &types.Health{Status: types.Starting}),
+			eventsFunc:    eventsFn(events.Message{Action: "health_status: unhealthy"}),
+			expectedError: "timed out waiting for container foo to become healthy (status: unhealthy)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewWaitCommand(test.NewFakeCli(&fakeClient{
+				inspectFunc: tc.inspectFunc,
+				eventsFunc:  tc.eventsFunc,
+			}))
+			cmd.SetArgs(append([]string{"--condition", "healthy"}, tc.args...))
+			cmd.SetOutput(ioutil.Discard)
+			testutil.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package command
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// passwordCharacters are the characters of generated passwords.
+const passwordCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#%+,-.:=?@^_~"
+
+// DataOptions are the options to create the data of a secret or a config
+// without reading it from a file: generated random data, or a bundle of
+// key-value pairs.
+type DataOptions struct {
+	generate     string
+	fromEnv      opts.ListOpts
+	fromLiteral  opts.ListOpts
+	bundleFormat string
+}
+
+// AddDataFlags adds the flags of DataOptions to a flag set.
+func AddDataFlags(flags *pflag.FlagSet, options *DataOptions) {
+	options.fromEnv = opts.NewListOpts(nil)
+	options.fromLiteral = opts.NewListOpts(nil)
+	flags.StringVar(&options.generate, "generate", "", "Generate random data (format: random:LENGTH/hex|base64|password)")
+	flags.Var(&options.fromEnv, "from-env", "Add the value of an environment variable to the data, as a key")
+	flags.Var(&options.fromLiteral, "from-literal", "Add a key and value to the data (format: KEY=VALUE)")
+	flags.StringVar(&options.bundleFormat, "bundle-format", "env", "Format of the keys added with --from-env and --from-literal (env|json)")
+}
+
+// IsSet returns whether the data is not read from a file.
+func (o DataOptions) IsSet() bool {
+	return o.generate != "" || o.fromEnv.Len() > 0 || o.fromLiteral.Len() > 0
+}
+
+// ReadData returns the data of a secret or a config: generated or bundled as
+// set in options, or else read from a file, or from in if the file is "-".
+func ReadData(in io.Reader, file string, options DataOptions) ([]byte, error) {
+	bundle := options.fromEnv.Len() > 0 || options.fromLiteral.Len() > 0
+	switch {
+	case options.IsSet() && file != "":
+		return nil, errors.New("a file can't be used with --generate, --from-env or --from-literal")
+	case options.generate != "" && bundle:
+		return nil, errors.New("--generate can't be used with --from-env or --from-literal")
+	case options.generate != "":
+		return generateData(options.generate)
+	case bundle:
+		return bundleData(options.fromEnv.GetAll(), options.fromLiteral.GetAll(), options.bundleFormat)
+	case file == "":
+		return nil, errors.New("a file, or one of --generate, --from-env or --from-literal, is required")
+	}
+
+	if file != "-" {
+		f, err := system.OpenSequential(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, errors.Errorf("Error reading content from %q: %v", file, err)
+	}
+	return data, nil
+}
+
+// generateData returns random data, as set by a spec such as random:32/hex.
+// With the hex and base64 encodings, the length is the number of random
+// bytes; with the password encoding, it is the number of characters.
+func generateData(spec string) ([]byte, error) {
+	invalid := errors.Errorf("invalid --generate %s: the format is random:LENGTH/hex|base64|password", spec)
+	if !strings.HasPrefix(spec, "random:") {
+		return nil, invalid
+	}
+	parts := strings.SplitN(strings.TrimPrefix(spec, "random:"), "/", 2)
+	if len(parts) != 2 {
+		return nil, invalid
+	}
+	length, err := strconv.Atoi(parts[0])
+	if err != nil || length <= 0 {
+		return nil, invalid
+	}
+
+	if parts[1] == "password" {
+		password := make([]byte, length)
+		max := big.NewInt(int64(len(passwordCharacters)))
+		for i := range password {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, err
+			}
+			password[i] = passwordCharacters[n.Int64()]
+		}
+		return password, nil
+	}
+
+	random := make([]byte, length)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	switch parts[1] {
+	case "hex":
+		return []byte(hex.EncodeToString(random)), nil
+	case "base64":
+		return []byte(base64.StdEncoding.EncodeToString(random)), nil
+	default:
+		return nil, invalid
+	}
+}
+
+// bundleData returns the values of environment variables and literal
+// key-value pairs, as an env file or as a JSON object.
+func bundleData(envVars, literals []string, format string) ([]byte, error) {
+	values := make(map[string]string)
+	for _, name := range envVars {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, errors.Errorf("environment variable %s is not set", name)
+		}
+		values[name] = value
+	}
+	for _, literal := range literals {
+		kv := strings.SplitN(literal, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid --from-literal %s: the format is KEY=VALUE", literal)
+		}
+		values[kv[0]] = kv[1]
+	}
+
+	switch format {
+	case "json":
+		return json.Marshal(values)
+	case "env":
+		var keys []string
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf := new(bytes.Buffer)
+		for _, key := range keys {
+			if strings.ContainsAny(values[key], "\r\n") {
+				return nil, errors.Errorf("the value of %s has several lines, which the env format doesn't support: use --bundle-format json", key)
+			}
+			fmt.Fprintf(buf, "%s=%s\n", key, values[key])
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Errorf("invalid --bundle-format %s: the format is env or json", format)
+	}
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateData(t *testing.T) {
+	data, err := generateData("random:32/hex")
+	require.NoError(t, err)
+	decoded, err := hex.DecodeString(string(data))
+	require.NoError(t, err)
+	assert.Len(t, decoded, 32)
+
+	data, err = generateData("random:16/base64")
+	require.NoError(t, err)
+	decoded, err = base64.StdEncoding.DecodeString(string(data))
+	require.NoError(t, err)
+	assert.Len(t, decoded, 16)
+
+	data, err = generateData("random:20/password")
+	require.NoError(t, err)
+	assert.Len(t, data, 20)
+	for _, c := range string(data) {
+		assert.True(t, strings.ContainsRune(passwordCharacters, c), "unexpected character %q", c)
+	}
+
+	other, err := generateData("random:20/password")
+	require.NoError(t, err)
+	assert.NotEqual(t, data, other)
+}
+
+func TestGenerateDataErrors(t *testing.T) {
+	for _, spec := range []string{"32/hex", "random:32", "random:0/hex", "random:foo/hex", "random:32/base32"} {
+		_, err := generateData(spec)
+		testutil.ErrorContains(t, err, "the format is random:LENGTH/hex|base64|password")
+	}
+}
+
+func TestBundleData(t *testing.T) {
+	os.Setenv("DOCKER_TEST_BUNDLE_TOKEN", "abc")
+	defer os.Unsetenv("DOCKER_TEST_BUNDLE_TOKEN")
+
+	data, err := bundleData([]string{"DOCKER_TEST_BUNDLE_TOKEN"}, []string{"user=admin", "url=http://host/?a=b"}, "env")
+	require.NoError(t, err)
+	assert.Equal(t, "DOCKER_TEST_BUNDLE_TOKEN=abc\nurl=http://host/?a=b\nuser=admin\n", string(data))
+
+	data, err = bundleData(nil, []string{"user=admin"}, "json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"user":"admin"}`, string(data))
+}
+
+func TestBundleDataErrors(t *testing.T) {
+	os.Unsetenv("DOCKER_TEST_BUNDLE_UNSET")
+	_, err := bundleData([]string{"DOCKER_TEST_BUNDLE_UNSET"}, nil, "env")
+	testutil.ErrorContains(t, err, "environment variable DOCKER_TEST_BUNDLE_UNSET is not set")
+
+	_, err = bundleData(nil, []string{"user"}, "env")
+	testutil.ErrorContains(t, err, "the format is KEY=VALUE")
+
+	_, err = bundleData(nil, []string{"key=a\nb"}, "env")
+	testutil.ErrorContains(t, err, "use --bundle-format json")
+
+	_, err = bundleData(nil, []string{"user=admin"}, "yaml")
+	testutil.ErrorContains(t, err, "invalid --bundle-format yaml")
+}
@@ -51,6 +51,9 @@ Platform:
 Resources:
  CPUs:			{{.ResourceNanoCPUs}}
  Memory:		{{.ResourceMemory}}
+{{- range $kind, $value := .ResourceGenericResources }}
+ {{ $kind }}:		{{ $value }}
+{{- end }}
 {{- if .HasEnginePlugins}}
 Plugins:
 {{- range $k, $v := .EnginePlugins }}
@@ -287,6 +290,10 @@ func (ctx *nodeInspectContext) ResourceMemory() string {
 	return units.BytesSize(float64(ctx.Node.Description.Resources.MemoryBytes))
 }
 
+func (ctx *nodeInspectContext) ResourceGenericResources() map[string]string {
+	return genericResourcesByKind(ctx.Node.Description.Resources.GenericResources)
+}
+
 func (ctx *nodeInspectContext) HasEnginePlugins() bool {
 	return len(ctx.Node.Description.Engine.Plugins) > 0
 }
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -105,6 +106,9 @@ Resources:
 {{- end }}
 {{- if .ResourceReservationMemory }}
   Memory:	{{ .ResourceReservationMemory }}
+{{- end }}
+{{- range $kind, $value := .ResourceReservationGenericResources }}
+  {{ $kind }}:	{{ $value }}
 {{- end }}{{ end }}
 {{- if .HasResourceLimits }}
  Limits:
@@ -363,7 +367,8 @@ func (ctx *serviceInspectContext) HasResourceReservations() bool {
 	if ctx.Service.Spec.TaskTemplate.Resources == nil || ctx.Service.Spec.TaskTemplate.Resources.Reservations == nil {
 		return false
 	}
-	return ctx.Service.Spec.TaskTemplate.Resources.Reservations.NanoCPUs > 0 || ctx.Service.Spec.TaskTemplate.Resources.Reservations.MemoryBytes > 0
+	reservations := ctx.Service.Spec.TaskTemplate.Resources.Reservations
+	return reservations.NanoCPUs > 0 || reservations.MemoryBytes > 0 || len(reservations.GenericResources) > 0
 }
 
 func (ctx *serviceInspectContext) ResourceReservationNanoCPUs() float64 {
@@ -380,6 +385,29 @@ func (ctx *serviceInspectContext) ResourceReservationMemory() string {
 	return units.BytesSize(float64(ctx.Service.Spec.TaskTemplate.Resources.Reservations.MemoryBytes))
 }
 
+func (ctx *serviceInspectContext) ResourceReservationGenericResources() map[string]string {
+	return genericResourcesByKind(ctx.Service.Spec.TaskTemplate.Resources.Reservations.GenericResources)
+}
+
+// genericResourcesByKind returns the amount of discrete resources, and the
+// comma separated values of named resources, indexed by kind.
+func genericResourcesByKind(resources []swarm.GenericResource) map[string]string {
+	byKind := make(map[string]string, len(resources))
+	for _, res := range resources {
+		switch {
+		case res.DiscreteResourceSpec != nil:
+			byKind[res.DiscreteResourceSpec.Kind] = strconv.FormatInt(res.DiscreteResourceSpec.Value, 10)
+		case res.NamedResourceSpec != nil:
+			kind := res.NamedResourceSpec.Kind
+			if byKind[kind] != "" {
+				byKind[kind] += ", "
+			}
+			byKind[kind] += res.NamedResourceSpec.Value
+		}
+	}
+	return byKind
+}
+
 func (ctx *serviceInspectContext) HasResourceLimits() bool {
 	if ctx.Service.Spec.TaskTemplate.Resources == nil || ctx.Service.Spec.TaskTemplate.Resources.Limits == nil {
 		return false
@@ -0,0 +1,270 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command/inspect"
+	"github.com/docker/docker/api/types/swarm"
+	units "github.com/docker/go-units"
+)
+
+// ServiceSpecChange is a change of a single field between two service specs.
+// Fields are named as in the pretty output of `docker service inspect`.
+// Fields holding a list have an entry for every value that was removed or
+// added; Old is empty for added fields, and New for removed ones.
+type ServiceSpecChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// serviceSpecField is a field of a service spec with its values. Fields that
+// hold a single value are compared as a whole, fields that hold a list are
+// compared value by value.
+type serviceSpecField struct {
+	name   string
+	values []string
+	list   bool
+}
+
+// ServiceSpecDiff returns the changes between two specs of a service, in the
+// order in which the fields are shown by `docker service inspect --pretty`.
+func ServiceSpecDiff(previous, current swarm.ServiceSpec, getNetwork inspect.GetRefFunc) []ServiceSpecChange {
+	oldFields := serviceSpecFields(previous, getNetwork)
+	newFields := serviceSpecFields(current, getNetwork)
+
+	var changes []ServiceSpecChange
+	for i, field := range newFields {
+		oldField := oldFields[i]
+		if !field.list {
+			oldValue, newValue := strings.Join(oldField.values, " "), strings.Join(field.values, " ")
+			if oldValue != newValue {
+				changes = append(changes, ServiceSpecChange{Field: field.name, Old: oldValue, New: newValue})
+			}
+			continue
+		}
+		for _, value := range subtract(oldField.values, field.values) {
+			changes = append(changes, ServiceSpecChange{Field: field.name, Old: value})
+		}
+		for _, value := range subtract(field.values, oldField.values) {
+			changes = append(changes, ServiceSpecChange{Field: field.name, New: value})
+		}
+	}
+	return changes
+}
+
+// ServiceSpecDiffWrite writes the changes between two specs of a service.
+// Changed values are prefixed with "~", removed values with "-" and added
+// values with "+".
+func ServiceSpecDiffWrite(w io.Writer, changes []ServiceSpecChange) {
+	for _, change := range changes {
+		switch {
+		case change.Old == "":
+			fmt.Fprintf(w, "+ %s: %s\n", change.Field, change.New)
+		case change.New == "":
+			fmt.Fprintf(w, "- %s: %s\n", change.Field, change.Old)
+		default:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", change.Field, change.Old, change.New)
+		}
+	}
+}
+
+// subtract returns the values of a that are not in b.
+func subtract(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, value := range b {
+		inB[value] = true
+	}
+	var out []string
+	for _, value := range a {
+		if !inB[value] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+// nolint: gocyclo
+func serviceSpecFields(spec swarm.ServiceSpec, getNetwork inspect.GetRefFunc) []serviceSpecField {
+	service := swarm.Service{Spec: spec}
+	ctx := &serviceInspectContext{Service: service, networkNames: resolveNetworks(service, getNetwork)}
+	task := spec.TaskTemplate
+
+	// Values are only empty when the field is not set, zero values are
+	// compared like any other value
+	var fields []serviceSpecField
+	value := func(name string, v interface{}) {
+		s := fmt.Sprintf("%v", v)
+		field := serviceSpecField{name: name}
+		if s != "" {
+			field.values = []string{s}
+		}
+		fields = append(fields, field)
+	}
+	list := func(name string, values []string) {
+		fields = append(fields, serviceSpecField{name: name, values: values, list: true})
+	}
+
+	value("Name", ctx.Name())
+	list("Labels", keyValues(ctx.Labels()))
+	switch {
+	case ctx.IsModeGlobal():
+		value("Service Mode", "Global")
+		value("Service Mode.Replicas", "")
+	case ctx.IsModeReplicated():
+		value("Service Mode", "Replicated")
+		if replicas := ctx.ModeReplicatedReplicas(); replicas != nil {
+			value("Service Mode.Replicas", *replicas)
+		} else {
+			value("Service Mode.Replicas", "")
+		}
+	default:
+		value("Service Mode", "")
+		value("Service Mode.Replicas", "")
+	}
+
+	list("Placement.Constraints", ctx.TaskPlacementConstraints())
+	// The order of placement preferences is significant
+	value("Placement.Preferences", strings.Join(ctx.TaskPlacementPreferences(), ", "))
+
+	updateConfigFields("UpdateConfig", spec.UpdateConfig, value)
+	updateConfigFields("RollbackConfig", spec.RollbackConfig, value)
+
+	cspec := task.ContainerSpec
+	if cspec == nil {
+		cspec = &swarm.ContainerSpec{}
+	}
+	value("ContainerSpec.Image", cspec.Image)
+	value("ContainerSpec.Command", strings.Join(cspec.Command, " "))
+	value("ContainerSpec.Args", strings.Join(cspec.Args, " "))
+	list("ContainerSpec.Env", cspec.Env)
+	list("ContainerSpec.Labels", keyValues(cspec.Labels))
+	value("ContainerSpec.Dir", cspec.Dir)
+	value("ContainerSpec.User", cspec.User)
+	list("ContainerSpec.Groups", cspec.Groups)
+	value("ContainerSpec.Hostname", cspec.Hostname)
+	list("ContainerSpec.Hosts", cspec.Hosts)
+	value("ContainerSpec.TTY", cspec.TTY)
+	value("ContainerSpec.ReadOnly", cspec.ReadOnly)
+	value("ContainerSpec.StopSignal", cspec.StopSignal)
+	value("ContainerSpec.StopGracePeriod", durationValue(cspec.StopGracePeriod))
+	if hc := cspec.Healthcheck; hc != nil {
+		value("ContainerSpec.Healthcheck", fmt.Sprintf("Test=%s, Interval=%s, Timeout=%s, Retries=%d, StartPeriod=%s",
+			strings.Join(hc.Test, " "), hc.Interval, hc.Timeout, hc.Retries, hc.StartPeriod))
+	} else {
+		value("ContainerSpec.Healthcheck", "")
+	}
+	var secrets, configs []string
+	for _, secret := range cspec.Secrets {
+		secrets = append(secrets, fmt.Sprintf("%s (%s)", secret.SecretName, secret.SecretID))
+	}
+	for _, config := range cspec.Configs {
+		configs = append(configs, fmt.Sprintf("%s (%s)", config.ConfigName, config.ConfigID))
+	}
+	list("ContainerSpec.Secrets", secrets)
+	list("ContainerSpec.Configs", configs)
+
+	var mounts []string
+	for _, mount := range cspec.Mounts {
+		mounts = append(mounts, fmt.Sprintf("Target=%s, Source=%s, ReadOnly=%t, Type=%s", mount.Target, mount.Source, mount.ReadOnly, mount.Type))
+	}
+	list("Mounts", mounts)
+
+	var reservations, limits swarm.Resources
+	if task.Resources != nil && task.Resources.Reservations != nil {
+		reservations = *task.Resources.Reservations
+	}
+	if task.Resources != nil && task.Resources.Limits != nil {
+		limits = *task.Resources.Limits
+	}
+	resourcesFields("Resources.Reservations", reservations, value, list)
+	resourcesFields("Resources.Limits", limits, value, list)
+
+	if policy := task.RestartPolicy; policy != nil {
+		value("RestartPolicy.Condition", policy.Condition)
+		value("RestartPolicy.Delay", durationValue(policy.Delay))
+		if policy.MaxAttempts != nil {
+			value("RestartPolicy.MaxAttempts", *policy.MaxAttempts)
+		} else {
+			value("RestartPolicy.MaxAttempts", "")
+		}
+		value("RestartPolicy.Window", durationValue(policy.Window))
+	} else {
+		for _, name := range []string{"Condition", "Delay", "MaxAttempts", "Window"} {
+			value("RestartPolicy."+name, "")
+		}
+	}
+
+	if task.LogDriver != nil {
+		value("LogDriver", task.LogDriver.Name)
+		list("LogDriver.Options", keyValues(task.LogDriver.Options))
+	} else {
+		value("LogDriver", "")
+		list("LogDriver.Options", nil)
+	}
+
+	list("Networks", ctx.Networks())
+	value("Endpoint Mode", ctx.EndpointMode())
+	var ports []string
+	if spec.EndpointSpec != nil {
+		for _, port := range spec.EndpointSpec.Ports {
+			ports = append(ports, fmt.Sprintf("PublishedPort=%d, Protocol=%s, TargetPort=%d, PublishMode=%s", port.PublishedPort, port.Protocol, port.TargetPort, port.PublishMode))
+		}
+	}
+	list("Ports", ports)
+
+	return fields
+}
+
+func updateConfigFields(prefix string, config *swarm.UpdateConfig, value func(string, interface{})) {
+	if config == nil {
+		for _, name := range []string{"Parallelism", "Delay", "On failure", "Monitoring Period", "Max failure ratio", "Order"} {
+			value(prefix+"."+name, "")
+		}
+		return
+	}
+	value(prefix+".Parallelism", config.Parallelism)
+	value(prefix+".Delay", config.Delay)
+	value(prefix+".On failure", config.FailureAction)
+	value(prefix+".Monitoring Period", config.Monitor)
+	value(prefix+".Max failure ratio", config.MaxFailureRatio)
+	value(prefix+".Order", config.Order)
+}
+
+func resourcesFields(prefix string, resources swarm.Resources, value func(string, interface{}), list func(string, []string)) {
+	if resources.NanoCPUs > 0 {
+		value(prefix+".CPU", float64(resources.NanoCPUs)/1e9)
+	} else {
+		value(prefix+".CPU", "")
+	}
+	value(prefix+".Memory", memoryValue(resources.MemoryBytes))
+	list(prefix+".Generic", keyValues(genericResourcesByKind(resources.GenericResources)))
+}
+
+func memoryValue(bytes int64) string {
+	if bytes == 0 {
+		return ""
+	}
+	return units.BytesSize(float64(bytes))
+}
+
+func durationValue(d *time.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+// keyValues returns the entries of a map as sorted key=value strings.
+func keyValues(m map[string]string) []string {
+	var out []string
+	for k, v := range m {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,102 @@
+package formatter
+
+import (
+	units "github.com/docker/go-units"
+)
+
+const defaultServiceStatsTableFormat = "table {{.Name}}\t{{.Node}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.MemPerc}}\t{{.NetIO}}"
+
+// ServiceStatsEntry represents the statistics data of a task of a service, or
+// the sum of the statistics data of the tasks of a service
+type ServiceStatsEntry struct {
+	Name   string
+	Node   string // Empty for the sum of the tasks of a service
+	OSType string // The OS of the node of a task
+	Stats  StatsEntry
+}
+
+// NewServiceStatsFormat returns a format for rendering a service stats Context
+func NewServiceStatsFormat(source string) Format {
+	if source == TableFormatKey {
+		return Format(defaultServiceStatsTableFormat)
+	}
+	return Format(source)
+}
+
+// ServiceStatsWrite renders the context for a list of statistics of services
+// and tasks
+func ServiceStatsWrite(ctx Context, entries []ServiceStatsEntry) error {
+	render := func(format func(subContext subContext) error) error {
+		for _, entry := range entries {
+			if err := format(&serviceStatsContext{e: entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	serviceStatsCtx := serviceStatsContext{}
+	serviceStatsCtx.header = map[string]string{
+		"Name":     nameHeader,
+		"Node":     nodeHeader,
+		"CPUPerc":  cpuPercHeader,
+		"MemUsage": memUseHeader,
+		"MemPerc":  memPercHeader,
+		"NetIO":    netIOHeader,
+		"BlockIO":  blockIOHeader,
+		"PIDs":     pidsHeader,
+	}
+	return ctx.Write(&serviceStatsCtx, render)
+}
+
+type serviceStatsContext struct {
+	HeaderContext
+	e ServiceStatsEntry
+}
+
+func (c *serviceStatsContext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c)
+}
+
+func (c *serviceStatsContext) stats() *containerStatsContext {
+	return &containerStatsContext{s: c.e.Stats, os: c.e.OSType}
+}
+
+func (c *serviceStatsContext) Name() string {
+	return c.e.Name
+}
+
+func (c *serviceStatsContext) Node() string {
+	return c.e.Node
+}
+
+func (c *serviceStatsContext) CPUPerc() string {
+	return c.stats().CPUPerc()
+}
+
+// MemUsage returns the memory usage and limit of a task. A service has no
+// limit, as its tasks can run on different nodes.
+func (c *serviceStatsContext) MemUsage() string {
+	if c.e.Node == "" && !c.e.Stats.IsInvalid {
+		return units.BytesSize(c.e.Stats.Memory) + " / --"
+	}
+	return c.stats().MemUsage()
+}
+
+func (c *serviceStatsContext) MemPerc() string {
+	if c.e.Node == "" {
+		return "--"
+	}
+	return c.stats().MemPerc()
+}
+
+func (c *serviceStatsContext) NetIO() string {
+	return c.stats().NetIO()
+}
+
+func (c *serviceStatsContext) BlockIO() string {
+	return c.stats().BlockIO()
+}
+
+func (c *serviceStatsContext) PIDs() string {
+	return c.stats().PIDs()
+}
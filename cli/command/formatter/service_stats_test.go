@@ -0,0 +1,70 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceStatsContextWrite(t *testing.T) {
+	entries := []ServiceStatsEntry{
+		{
+			Name: "web",
+			Stats: StatsEntry{
+				CPUPercentage: 30,
+				Memory:        30 * 1024 * 1024,
+				NetworkRx:     3000,
+				NetworkTx:     300,
+				PidsCurrent:   4,
+			},
+		},
+		{
+			Name: "web.1",
+			Node: "node-1",
+			Stats: StatsEntry{
+				CPUPercentage:    10,
+				Memory:           10 * 1024 * 1024,
+				MemoryLimit:      1024 * 1024 * 1024,
+				MemoryPercentage: 0.98,
+				NetworkRx:        1000,
+				NetworkTx:        100,
+				PidsCurrent:      2,
+			},
+		},
+		{
+			Name:  "web.2",
+			Node:  "node-2",
+			Stats: StatsEntry{IsInvalid: true},
+		},
+	}
+
+	tt := []struct {
+		context  Context
+		expected string
+	}{
+		{
+			Context{Format: NewServiceStatsFormat("table")},
+			`NAME                NODE                CPU %               MEM USAGE / LIMIT   MEM %               NET I/O
+web                                     30.00%              30MiB / --          --                  3kB / 300B
+web.1               node-1              10.00%              10MiB / 1GiB        0.98%               1kB / 100B
+web.2               node-2              --                  -- / --             --                  --
+`,
+		},
+		{
+			Context{Format: NewServiceStatsFormat("{{.Name}} {{.Node}} {{.PIDs}} {{.BlockIO}}")},
+			`web  4 0B / 0B
+web.1 node-1 2 0B / 0B
+web.2 node-2 -- --
+`,
+		},
+	}
+
+	for _, te := range tt {
+		var out bytes.Buffer
+		te.context.Output = &out
+		err := ServiceStatsWrite(te.context, entries)
+		assert.NoError(t, err)
+		assert.Equal(t, te.expected, out.String())
+	}
+}
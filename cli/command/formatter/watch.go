@@ -0,0 +1,203 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/events"
+	"golang.org/x/net/context"
+)
+
+const (
+	// watchDebounce is how long to wait for more events before redrawing,
+	// so that a burst of events results in a single redraw.
+	watchDebounce = 200 * time.Millisecond
+	// watchRefreshInterval is how often the list is refreshed without
+	// receiving an event, to catch changes that are not reported as events,
+	// such as task state changes.
+	watchRefreshInterval = 5 * time.Second
+
+	ansiClearScreen = "\033[2J\033[H"
+	ansiReset       = "\033[0m"
+	ansiGreen       = "\033[32m"
+	ansiYellow      = "\033[33m"
+	ansiRed         = "\033[31m"
+)
+
+// rowChange describes how a row changed between two renderings of a list.
+type rowChange int
+
+const (
+	rowUnchanged rowChange = iota
+	rowAdded
+	rowChanged
+	rowRemoved
+)
+
+// watchRow is a single line of a list rendered in watch mode.
+type watchRow struct {
+	line   string
+	change rowChange
+}
+
+// WatchContext contains the information required to redraw the output of a
+// list command in place whenever it changes.
+type WatchContext struct {
+	// Output is the stream to which the list is drawn.
+	Output *command.OutStream
+	// Format is the format of the list. The first line of table formats is
+	// the header, which is never highlighted.
+	Format Format
+	// Render writes the current state of the list to the given writer,
+	// typically using a Context that has it as Output.
+	Render func(w io.Writer) error
+}
+
+// Watch draws the list, and redraws it whenever an event is received that
+// changes its output. Rows that were added, changed or removed since the
+// previous drawing are highlighted when the output is a terminal. It returns
+// when the context is done or an error is received.
+func (c WatchContext) Watch(ctx context.Context, eventC <-chan events.Message, errC <-chan error) error {
+	previous, err := c.render()
+	if err != nil {
+		return err
+	}
+	c.draw(diffRows(previous, previous, c.Format.IsTable()))
+
+	refresh := time.NewTicker(watchRefreshInterval)
+	defer refresh.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errC:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case <-eventC:
+			if err := c.debounce(ctx, eventC); err != nil {
+				return err
+			}
+		case <-refresh.C:
+		}
+
+		current, err := c.render()
+		if err != nil {
+			return err
+		}
+		if strings.Join(current, "\n") == strings.Join(previous, "\n") {
+			continue
+		}
+		c.draw(diffRows(previous, current, c.Format.IsTable()))
+		previous = current
+	}
+}
+
+// debounce consumes the events that are received shortly after a first one.
+func (c WatchContext) debounce(ctx context.Context, eventC <-chan events.Message) error {
+	timer := time.NewTimer(watchDebounce)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-eventC:
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+func (c WatchContext) render() ([]string, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := c.Render(buf); err != nil {
+		return nil, err
+	}
+	out := strings.TrimRight(buf.String(), "\n")
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+func (c WatchContext) draw(rows []watchRow) {
+	if !c.Output.IsTerminal() {
+		// Without a terminal the list can't be redrawn in place, so every
+		// drawing is appended to the output instead.
+		for _, row := range rows {
+			if row.change != rowRemoved {
+				fmt.Fprintln(c.Output, row.line)
+			}
+		}
+		fmt.Fprintln(c.Output)
+		return
+	}
+
+	buf := bytes.NewBufferString(ansiClearScreen)
+	for _, row := range rows {
+		switch row.change {
+		case rowAdded:
+			fmt.Fprintln(buf, ansiGreen+row.line+ansiReset)
+		case rowChanged:
+			fmt.Fprintln(buf, ansiYellow+row.line+ansiReset)
+		case rowRemoved:
+			fmt.Fprintln(buf, ansiRed+row.line+ansiReset)
+		default:
+			fmt.Fprintln(buf, row.line)
+		}
+	}
+	buf.WriteTo(c.Output)
+}
+
+// diffRows compares two renderings of a list. Rows are identified by their
+// first field, which is the ID for the default formats. Rows that were
+// removed are kept at the end of the list, so that they are shown once.
+func diffRows(previous, current []string, table bool) []watchRow {
+	var header []watchRow
+	if table && len(current) > 0 {
+		header = []watchRow{{line: current[0]}}
+		current = current[1:]
+		if len(previous) > 0 {
+			previous = previous[1:]
+		}
+	}
+
+	previousRows := make(map[string]string, len(previous))
+	for _, line := range previous {
+		previousRows[rowKey(line)] = line
+	}
+
+	rows := header
+	seen := make(map[string]bool, len(current))
+	for _, line := range current {
+		key := rowKey(line)
+		seen[key] = true
+
+		change := rowUnchanged
+		if prev, ok := previousRows[key]; !ok {
+			change = rowAdded
+		} else if prev != line {
+			change = rowChanged
+		}
+		rows = append(rows, watchRow{line: line, change: change})
+	}
+	for _, line := range previous {
+		if !seen[rowKey(line)] {
+			rows = append(rows, watchRow{line: line, change: rowRemoved})
+		}
+	}
+	return rows
+}
+
+func rowKey(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,88 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestDiffRows(t *testing.T) {
+	previous := []string{
+		"ID    NAME   STATUS",
+		"aaa   web    running",
+		"bbb   db     running",
+		"ccc   cache  running",
+	}
+	current := []string{
+		"ID    NAME   STATUS",
+		"aaa   web    running",
+		"ccc   cache  exited",
+		"ddd   worker created",
+	}
+
+	expected := []watchRow{
+		{line: "ID    NAME   STATUS"},
+		{line: "aaa   web    running"},
+		{line: "ccc   cache  exited", change: rowChanged},
+		{line: "ddd   worker created", change: rowAdded},
+		{line: "bbb   db     running", change: rowRemoved},
+	}
+	assert.Equal(t, expected, diffRows(previous, current, true))
+}
+
+func TestDiffRowsWithoutHeader(t *testing.T) {
+	expected := []watchRow{
+		{line: "aaa"},
+		{line: "ccc", change: rowAdded},
+		{line: "bbb", change: rowRemoved},
+	}
+	assert.Equal(t, expected, diffRows([]string{"aaa", "bbb"}, []string{"aaa", "ccc"}, false))
+}
+
+func TestWatchRedrawsOnChange(t *testing.T) {
+	renders := []string{
+		"ID    STATUS\naaa   running\n",
+		"ID    STATUS\naaa   running\n",
+		"ID    STATUS\naaa   exited\n",
+	}
+	var count int
+	rendered := make(chan struct{}, len(renders))
+
+	eventC := make(chan events.Message)
+	errC := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out := bytes.NewBuffer(nil)
+	watchCtx := WatchContext{
+		Output: command.NewOutStream(out),
+		Format: NewContainerFormat("table {{.ID}}\t{{.Status}}", false, false),
+		Render: func(w io.Writer) error {
+			_, err := fmt.Fprint(w, renders[count])
+			count++
+			rendered <- struct{}{}
+			if count == len(renders) {
+				cancel()
+			}
+			return err
+		},
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- watchCtx.Watch(ctx, eventC, errC)
+	}()
+	<-rendered
+	eventC <- events.Message{Action: "start"}
+	<-rendered
+	eventC <- events.Message{Action: "die"}
+	require.NoError(t, <-done)
+
+	assert.Equal(t, "ID    STATUS\naaa   running\n\nID    STATUS\naaa   exited\n\n", out.String())
+}
@@ -22,11 +22,14 @@ func NewNodeCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		newDemoteCommand(dockerCli),
+		newDrainCommand(dockerCli),
 		newInspectCommand(dockerCli),
+		newLabelCommand(dockerCli),
 		newListCommand(dockerCli),
 		newPromoteCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		newPsCommand(dockerCli),
+		newUndrainCommand(dockerCli),
 		newUpdateCommand(dockerCli),
 	)
 	return cmd
@@ -0,0 +1,290 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// drainPollInterval is the interval at which the tasks of a node are checked
+// while it is drained.
+var drainPollInterval = time.Second
+
+type drainOptions struct {
+	node    string
+	force   bool
+	detach  bool
+	quiet   bool
+	timeout time.Duration
+}
+
+func newDrainCommand(dockerCli command.Cli) *cobra.Command {
+	var options drainOptions
+
+	cmd := &cobra.Command{
+		Use:   "drain [OPTIONS] NODE",
+		Short: "Drain a node and wait for its tasks to be rescheduled on other nodes",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.node = args[0]
+			return runDrain(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.force, "force", "f", false, "Drain a manager even if the swarm would lose quorum without it")
+	flags.BoolVarP(&options.detach, "detach", "d", false, "Exit immediately instead of waiting for the tasks to be rescheduled")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress progress output")
+	flags.DurationVar(&options.timeout, "timeout", 5*time.Minute, "Time to wait for the tasks to be rescheduled")
+	return cmd
+}
+
+func newUndrainCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undrain NODE [NODE...]",
+		Short: "Make one or more drained nodes available for tasks again",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUndrain(dockerCli, args)
+		},
+	}
+}
+
+func runDrain(dockerCli command.Cli, options drainOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	nodeRef, err := Reference(ctx, client, options.node)
+	if err != nil {
+		return err
+	}
+	node, _, err := client.NodeInspectWithRaw(ctx, nodeRef)
+	if err != nil {
+		return err
+	}
+
+	if node.Spec.Role == swarm.NodeRoleManager && !options.force {
+		if err := checkDrainQuorum(ctx, client, node); err != nil {
+			return err
+		}
+	}
+
+	// The tasks are listed before the node is drained, so that the tasks
+	// that are moved right away are waited on too.
+	slots, err := drainedSlots(ctx, client, node.ID)
+	if err != nil {
+		return err
+	}
+
+	node.Spec.Availability = swarm.NodeAvailabilityDrain
+	if err := client.NodeUpdate(ctx, node.ID, node.Version, node.Spec); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), options.node)
+
+	if options.detach || len(slots) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, options.timeout)
+	defer cancel()
+
+	errChan := make(chan error, 1)
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		errChan <- waitOnDrain(ctx, client, node.ID, slots, pipeWriter)
+		pipeWriter.Close()
+	}()
+
+	if options.quiet {
+		io.Copy(ioutil.Discard, pipeReader)
+	} else if err := jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil); err != nil {
+		return err
+	}
+	return <-errChan
+}
+
+func runUndrain(dockerCli command.Cli, nodes []string) error {
+	undrain := func(node *swarm.Node) error {
+		node.Spec.Availability = swarm.NodeAvailabilityActive
+		return nil
+	}
+	success := func(nodeID string) {
+		fmt.Fprintf(dockerCli.Out(), "Node %s is active.\n", nodeID)
+	}
+	return updateNodes(dockerCli, nodes, undrain, success)
+}
+
+// checkDrainQuorum returns an error if the swarm would lose quorum when a
+// manager is drained and taken down for maintenance.
+func checkDrainQuorum(ctx context.Context, client client.NodeAPIClient, node swarm.Node) error {
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+
+	managers, reachable := 0, 0
+	for _, n := range nodes {
+		if n.ManagerStatus == nil {
+			continue
+		}
+		managers++
+		if n.ID != node.ID && n.ManagerStatus.Reachability == swarm.ReachabilityReachable {
+			reachable++
+		}
+	}
+	quorum := managers/2 + 1
+	if reachable < quorum {
+		return errors.Errorf("node %s is a manager: without it, %d of %d managers would be reachable, which is less than the quorum of %d; use --force to drain it anyway", node.ID, reachable, managers, quorum)
+	}
+	return nil
+}
+
+// taskSlot identifies a task of a replicated service, which is rescheduled on
+// another node when its node is drained.
+type taskSlot struct {
+	serviceID string
+	slot      int
+}
+
+// drainedSlots returns the slots of the tasks of replicated services that run
+// on a node. Tasks of global services are not rescheduled on other nodes.
+func drainedSlots(ctx context.Context, client client.APIClient, nodeID string) (map[taskSlot]swarm.Task, error) {
+	filter := filters.NewArgs()
+	filter.Add("node", nodeID)
+	filter.Add("desired-state", string(swarm.TaskStateRunning))
+	tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(map[taskSlot]swarm.Task)
+	for _, task := range tasks {
+		if task.Slot == 0 {
+			continue
+		}
+		slots[taskSlot{serviceID: task.ServiceID, slot: task.Slot}] = task
+	}
+	return slots, nil
+}
+
+// waitOnDrain waits until the tasks of every slot run on another node than the
+// drained node. It writes the progress of each service to out as JSON
+// messages.
+func waitOnDrain(ctx context.Context, client client.APIClient, nodeID string, slots map[taskSlot]swarm.Task, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+
+	serviceNames := make(map[string]string)
+	filter := filters.NewArgs()
+	for slot := range slots {
+		if _, ok := serviceNames[slot.serviceID]; ok {
+			continue
+		}
+		service, _, err := client.ServiceInspectWithRaw(ctx, slot.serviceID, types.ServiceInspectOptions{})
+		if err != nil {
+			return err
+		}
+		serviceNames[slot.serviceID] = service.Spec.Name
+		filter.Add("service", slot.serviceID)
+	}
+	filter.Add("desired-state", string(swarm.TaskStateRunning))
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var moved map[taskSlot]bool
+	for {
+		tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: filter})
+		if err != nil {
+			if ctx.Err() != nil {
+				return stuckTasksError(nodeID, slots, moved, serviceNames)
+			}
+			return err
+		}
+
+		moved = make(map[taskSlot]bool)
+		for _, task := range tasks {
+			slot := taskSlot{serviceID: task.ServiceID, slot: task.Slot}
+			if _, ok := slots[slot]; !ok {
+				continue
+			}
+			if task.NodeID != nodeID && task.Status.State == swarm.TaskStateRunning {
+				moved[slot] = true
+			} else if !moved[slot] {
+				slots[slot] = task
+			}
+		}
+
+		done := true
+		for _, serviceID := range sortedServices(serviceNames) {
+			total, count := 0, 0
+			for slot := range slots {
+				if slot.serviceID != serviceID {
+					continue
+				}
+				total++
+				if moved[slot] {
+					count++
+				}
+			}
+			if count < total {
+				done = false
+			}
+			encoder.Encode(jsonmessage.JSONMessage{
+				ID:     serviceNames[serviceID],
+				Status: fmt.Sprintf("%d/%d tasks rescheduled", count, total),
+			})
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return stuckTasksError(nodeID, slots, moved, serviceNames)
+		}
+	}
+}
+
+// stuckTasksError returns an error that lists the tasks that were not
+// rescheduled in time.
+func stuckTasksError(nodeID string, slots map[taskSlot]swarm.Task, moved map[taskSlot]bool, serviceNames map[string]string) error {
+	var stuck []string
+	for slot, task := range slots {
+		if moved[slot] {
+			continue
+		}
+		where := "on node " + task.NodeID
+		if task.NodeID == "" {
+			where = "not assigned to a node"
+		}
+		stuck = append(stuck, fmt.Sprintf("  %s.%d (%s): %s %s", serviceNames[slot.serviceID], slot.slot, task.ID, task.Status.State, where))
+	}
+	sort.Strings(stuck)
+	return errors.Errorf("timed out waiting for the tasks of node %s to be rescheduled:\n%s", nodeID, strings.Join(stuck, "\n"))
+}
+
+func sortedServices(serviceNames map[string]string) []string {
+	var ids []string
+	for id := range serviceNames {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return serviceNames[ids[i]] < serviceNames[ids[j]] })
+	return ids
+}
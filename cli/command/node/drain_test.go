@@ -0,0 +1,145 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+)
+
+func drainTask(id, serviceID string, slot int, nodeID string, state swarm.TaskState) swarm.Task {
+	return swarm.Task{
+		ID:        id,
+		ServiceID: serviceID,
+		Slot:      slot,
+		NodeID:    nodeID,
+		Status:    swarm.TaskStatus{State: state},
+	}
+}
+
+func TestNodeDrain(t *testing.T) {
+	defer func(interval time.Duration) { drainPollInterval = interval }(drainPollInterval)
+	drainPollInterval = time.Millisecond
+
+	polls := 0
+	var availability swarm.NodeAvailability
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return *Node(NodeID("node1")), nil, nil
+		},
+		nodeUpdateFunc: func(nodeID string, version swarm.Version, node swarm.NodeSpec) error {
+			availability = node.Availability
+			return nil
+		},
+		serviceInspectFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			return *Service(ServiceID(serviceID), ServiceName(serviceID+"-name")), nil, nil
+		},
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			if options.Filters.Include("node") {
+				return []swarm.Task{
+					drainTask("task1", "web", 1, "node1", swarm.TaskStateRunning),
+					drainTask("task2", "agent", 0, "node1", swarm.TaskStateRunning),
+				}, nil
+			}
+			polls++
+			if polls == 1 {
+				return []swarm.Task{
+					drainTask("task3", "web", 1, "node2", swarm.TaskStateStarting),
+				}, nil
+			}
+			return []swarm.Task{
+				drainTask("task3", "web", 1, "node2", swarm.TaskStateRunning),
+			}, nil
+		},
+	})
+	cmd := newDrainCommand(cli)
+	cmd.SetArgs([]string{"node1"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, swarm.NodeAvailabilityDrain, availability)
+	assert.Equal(t, 2, polls)
+	assert.Contains(t, cli.OutBuffer().String(), "web-name: 1/1 tasks rescheduled")
+}
+
+func TestNodeDrainTimeout(t *testing.T) {
+	defer func(interval time.Duration) { drainPollInterval = interval }(drainPollInterval)
+	drainPollInterval = time.Millisecond
+
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return *Node(NodeID("node1")), nil, nil
+		},
+		serviceInspectFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			return *Service(ServiceID(serviceID), ServiceName("web")), nil, nil
+		},
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			return []swarm.Task{
+				drainTask("task1", "web-id", 1, "node1", swarm.TaskStateRunning),
+				drainTask("task2", "web-id", 2, "", swarm.TaskStatePending),
+			}, nil
+		},
+	})
+	cmd := newDrainCommand(cli)
+	cmd.SetArgs([]string{"node1"})
+	cmd.Flags().Set("timeout", "10ms")
+	cmd.Flags().Set("quiet", "true")
+	err := cmd.Execute()
+	assert.EqualError(t, err, "timed out waiting for the tasks of node node1 to be rescheduled:\n"+
+		"  web.1 (task1): running on node node1\n"+
+		"  web.2 (task2): pending not assigned to a node")
+}
+
+func TestNodeDrainQuorum(t *testing.T) {
+	manager := func(id string, reachability swarm.Reachability) swarm.Node {
+		return *Node(NodeID(id), Manager(func(status *swarm.ManagerStatus) {
+			status.Reachability = reachability
+		}))
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return manager("manager1", swarm.ReachabilityReachable), nil, nil
+		},
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				manager("manager1", swarm.ReachabilityReachable),
+				manager("manager2", swarm.ReachabilityReachable),
+				manager("manager3", swarm.ReachabilityUnreachable),
+			}, nil
+		},
+	})
+	cmd := newDrainCommand(cli)
+	cmd.SetArgs([]string{"manager1"})
+	testutil.ErrorContains(t, cmd.Execute(), "less than the quorum of 2; use --force")
+
+	cmd = newDrainCommand(cli)
+	cmd.SetArgs([]string{"manager1"})
+	cmd.Flags().Set("force", "true")
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestNodeUndrain(t *testing.T) {
+	var availability swarm.NodeAvailability
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return *Node(NodeID("node1"), func(node *swarm.Node) {
+				node.Spec.Availability = swarm.NodeAvailabilityDrain
+			}), nil, nil
+		},
+		nodeUpdateFunc: func(nodeID string, version swarm.Version, node swarm.NodeSpec) error {
+			availability = node.Availability
+			return nil
+		},
+	})
+	cmd := newUndrainCommand(cli)
+	cmd.SetArgs([]string{"node1"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, swarm.NodeAvailabilityActive, availability)
+	assert.Equal(t, "Node node1 is active.\n", cli.OutBuffer().String())
+}
@@ -0,0 +1,21 @@
+package node
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+func newLabelCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "Manage the labels of nodes",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newLabelApplyCommand(dockerCli),
+		newLabelListCommand(dockerCli),
+	)
+	return cmd
+}
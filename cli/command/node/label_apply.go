@@ -0,0 +1,281 @@
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// labelUpdateRetries is how many times the labels of a node are updated again
+// when the node was changed since it was inspected.
+const labelUpdateRetries = 3
+
+type labelApplyOptions struct {
+	file   string
+	dryRun bool
+	prune  bool
+}
+
+// labelFile is a file of node labels, as read by `docker node label apply`.
+type labelFile struct {
+	Nodes []labelFileEntry `yaml:"nodes"`
+}
+
+// labelFileEntry holds the labels of the nodes that match its hostname, ID or
+// label.
+type labelFileEntry struct {
+	Hostname string            `yaml:"hostname"`
+	ID       string            `yaml:"id"`
+	Label    string            `yaml:"label"`
+	Labels   map[string]string `yaml:"labels"`
+	Remove   []string          `yaml:"remove"`
+}
+
+// nodeLabels holds the changes to the labels of a node. The labels in keep
+// select the node in the file, so they are not pruned.
+type nodeLabels struct {
+	node   swarm.Node
+	set    map[string]string
+	remove map[string]bool
+	keep   map[string]bool
+}
+
+func newLabelApplyCommand(dockerCli command.Cli) *cobra.Command {
+	var options labelApplyOptions
+
+	cmd := &cobra.Command{
+		Use:   "apply [OPTIONS]",
+		Short: "Set the labels of nodes from a file",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLabelApply(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&options.file, "file", "f", "", "Path to a YAML file of node labels")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the changes without making them")
+	flags.BoolVar(&options.prune, "prune", false, "Remove the labels of the matched nodes that are not in the file")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func runLabelApply(dockerCli command.Cli, options labelApplyOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	file, err := readLabelFile(options.file)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	changes, err := matchLabelFile(file, nodes)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, change := range changes {
+		_, diff := change.apply(change.node.Spec.Labels, options.prune)
+		if len(diff) == 0 {
+			continue
+		}
+		changed++
+		fmt.Fprintf(dockerCli.Out(), "%s (%s):\n", change.node.Description.Hostname, change.node.ID)
+		for _, line := range diff {
+			fmt.Fprintf(dockerCli.Out(), "  %s\n", line)
+		}
+	}
+	if changed == 0 {
+		fmt.Fprintln(dockerCli.Out(), "No changes.")
+		return nil
+	}
+	if options.dryRun {
+		return nil
+	}
+
+	var errs []string
+	for _, change := range changes {
+		if err := updateNodeLabels(ctx, client, change, options.prune); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", change.node.Description.Hostname, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func readLabelFile(filename string) (labelFile, error) {
+	var file labelFile
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return file, err
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return file, errors.Wrapf(err, "invalid node labels in %s", filename)
+	}
+	for i, entry := range file.Nodes {
+		selectors := 0
+		for _, selector := range []string{entry.Hostname, entry.ID, entry.Label} {
+			if selector != "" {
+				selectors++
+			}
+		}
+		if selectors != 1 {
+			return file, errors.Errorf("invalid node labels in %s: entry %d must have one of hostname, id or label", filename, i+1)
+		}
+	}
+	return file, nil
+}
+
+// matchLabelFile returns the changes of the labels of the nodes that match
+// the entries of a label file. The entries are applied in order, so that a
+// later entry overrides the labels set by an earlier one.
+func matchLabelFile(file labelFile, nodes []swarm.Node) ([]*nodeLabels, error) {
+	var changes []*nodeLabels
+	byID := make(map[string]*nodeLabels)
+
+	for _, entry := range file.Nodes {
+		matched, err := entry.match(nodes)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			return nil, errors.Errorf("no node matches %s", entry.selector())
+		}
+		for _, node := range matched {
+			change, ok := byID[node.ID]
+			if !ok {
+				change = &nodeLabels{node: node, set: make(map[string]string), remove: make(map[string]bool), keep: make(map[string]bool)}
+				byID[node.ID] = change
+				changes = append(changes, change)
+			}
+			if entry.Label != "" {
+				change.keep[strings.SplitN(entry.Label, "=", 2)[0]] = true
+			}
+			for k, v := range entry.Labels {
+				change.set[k] = v
+				delete(change.remove, k)
+			}
+			for _, k := range entry.Remove {
+				change.remove[k] = true
+				delete(change.set, k)
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].node.Description.Hostname < changes[j].node.Description.Hostname
+	})
+	return changes, nil
+}
+
+// match returns the nodes that an entry selects. An ID selects the node with
+// that ID, or else the only node whose ID starts with it.
+func (e labelFileEntry) match(nodes []swarm.Node) ([]swarm.Node, error) {
+	var matched []swarm.Node
+	for _, node := range nodes {
+		switch {
+		case e.Hostname != "":
+			if node.Description.Hostname == e.Hostname {
+				matched = append(matched, node)
+			}
+		case e.ID != "":
+			if node.ID == e.ID {
+				return []swarm.Node{node}, nil
+			}
+			if strings.HasPrefix(node.ID, e.ID) {
+				matched = append(matched, node)
+			}
+		default:
+			kv := strings.SplitN(e.Label, "=", 2)
+			if value, ok := node.Spec.Labels[kv[0]]; ok && (len(kv) == 1 || value == kv[1]) {
+				matched = append(matched, node)
+			}
+		}
+	}
+	if e.ID != "" && len(matched) > 1 {
+		return nil, errors.Errorf("id %s is ambiguous (%d matches found)", e.ID, len(matched))
+	}
+	return matched, nil
+}
+
+func (e labelFileEntry) selector() string {
+	switch {
+	case e.Hostname != "":
+		return "hostname " + e.Hostname
+	case e.ID != "":
+		return "id " + e.ID
+	default:
+		return "label " + e.Label
+	}
+}
+
+// apply returns the labels of a node once changed, and the differences with
+// its current labels. With prune, the labels that are not set are removed,
+// except the labels that select the node, so that the file still matches the
+// same nodes when it is applied again.
+func (c *nodeLabels) apply(current map[string]string, prune bool) (map[string]string, []string) {
+	labels := make(map[string]string)
+	var diff []string
+	for k, v := range current {
+		if c.remove[k] || (prune && !hasKey(c.set, k) && !c.keep[k]) {
+			diff = append(diff, fmt.Sprintf("- %s=%s", k, v))
+			continue
+		}
+		labels[k] = v
+	}
+	for k, v := range c.set {
+		old, ok := labels[k]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("+ %s=%s", k, v))
+		case old != v:
+			diff = append(diff, fmt.Sprintf("~ %s: %s -> %s", k, old, v))
+		}
+		labels[k] = v
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i][2:] < diff[j][2:] })
+	return labels, diff
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// updateNodeLabels updates the labels of a node. If the node was changed since
+// it was inspected, it is inspected and updated again.
+func updateNodeLabels(ctx context.Context, client client.APIClient, change *nodeLabels, prune bool) error {
+	node := change.node
+	for attempt := 0; ; attempt++ {
+		labels, diff := change.apply(node.Spec.Labels, prune)
+		if len(diff) == 0 {
+			return nil
+		}
+		node.Spec.Labels = labels
+		err := client.NodeUpdate(ctx, node.ID, node.Version, node.Spec)
+		if err == nil || attempt == labelUpdateRetries || !strings.Contains(err.Error(), "update out of sequence") {
+			return err
+		}
+		if node, _, err = client.NodeInspectWithRaw(ctx, node.ID); err != nil {
+			return err
+		}
+	}
+}
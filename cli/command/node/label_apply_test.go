@@ -0,0 +1,196 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+)
+
+const labelFileContent = `
+nodes:
+  - hostname: node-1
+    labels:
+      zone: us-east
+      disk: ssd
+  - label: role=db
+    labels:
+      backup: daily
+    remove: [tmp]
+`
+
+func labelNodes() []swarm.Node {
+	return []swarm.Node{
+		*Node(NodeID("node-2-id"), Hostname("node-2"), NodeLabels(map[string]string{"role": "db", "tmp": "x", "zone": "us-west"})),
+		*Node(NodeID("node-1-id"), Hostname("node-1"), NodeLabels(map[string]string{"disk": "hdd", "old": "y"})),
+		*Node(NodeID("node-3-id"), Hostname("node-3")),
+	}
+}
+
+func writeLabelFile(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "node-labels")
+	require.NoError(t, err)
+	filename := filepath.Join(dir, "labels.yaml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0600))
+	return filename, func() { os.RemoveAll(dir) }
+}
+
+func TestNodeLabelApplyDryRun(t *testing.T) {
+	filename, cleanup := writeLabelFile(t, labelFileContent)
+	defer cleanup()
+
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return labelNodes(), nil
+		},
+		nodeUpdateFunc: func(nodeID string, version swarm.Version, node swarm.NodeSpec) error {
+			return errors.New("the node should not be updated")
+		},
+	})
+	cmd := newLabelApplyCommand(cli)
+	cmd.SetArgs([]string{"--file", filename, "--dry-run", "--prune"})
+	require.NoError(t, cmd.Execute())
+
+	expected := `node-1 (node-1-id):
+  ~ disk: hdd -> ssd
+  - old=y
+  + zone=us-east
+node-2 (node-2-id):
+  + backup=daily
+  - tmp=x
+  - zone=us-west
+`
+	assert.Equal(t, expected, cli.OutBuffer().String())
+}
+
+func TestNodeLabelApplyPruneTwice(t *testing.T) {
+	filename, cleanup := writeLabelFile(t, labelFileContent)
+	defer cleanup()
+
+	nodes := labelNodes()
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return nodes, nil
+		},
+		nodeUpdateFunc: func(nodeID string, version swarm.Version, spec swarm.NodeSpec) error {
+			for i := range nodes {
+				if nodes[i].ID == nodeID {
+					nodes[i].Spec = spec
+				}
+			}
+			return nil
+		},
+	})
+	cmd := newLabelApplyCommand(cli)
+	cmd.SetArgs([]string{"--file", filename, "--prune"})
+	require.NoError(t, cmd.Execute())
+
+	// The label that selects node-2 in the file is kept, so that the file
+	// still matches it
+	assert.Equal(t, map[string]string{"backup": "daily", "role": "db"}, nodes[0].Spec.Labels)
+	assert.Equal(t, map[string]string{"disk": "ssd", "zone": "us-east"}, nodes[1].Spec.Labels)
+
+	cli.OutBuffer().Reset()
+	cmd = newLabelApplyCommand(cli)
+	cmd.SetArgs([]string{"--file", filename, "--prune"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "No changes.\n", cli.OutBuffer().String())
+}
+
+func TestNodeLabelApply(t *testing.T) {
+	filename, cleanup := writeLabelFile(t, labelFileContent)
+	defer cleanup()
+
+	updated := make(map[string]map[string]string)
+	conflicts := 1
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return labelNodes(), nil
+		},
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			// the labels of node-2 were changed since it was listed
+			node := labelNodes()[0]
+			node.Spec.Labels["rack"] = "r1"
+			return node, nil, nil
+		},
+		nodeUpdateFunc: func(nodeID string, version swarm.Version, node swarm.NodeSpec) error {
+			if nodeID == "node-2-id" && conflicts > 0 {
+				conflicts--
+				return errors.New("Error response from daemon: rpc error: code = 2 desc = update out of sequence")
+			}
+			updated[nodeID] = node.Labels
+			return nil
+		},
+	})
+	cmd := newLabelApplyCommand(cli)
+	cmd.SetArgs([]string{"--file", filename})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, map[string]map[string]string{
+		"node-1-id": {"disk": "ssd", "old": "y", "zone": "us-east"},
+		"node-2-id": {"backup": "daily", "rack": "r1", "role": "db", "zone": "us-west"},
+	}, updated)
+}
+
+func TestMatchLabelFileID(t *testing.T) {
+	nodes := append(labelNodes(), *Node(NodeID("node-1-id-2"), Hostname("node-4")))
+	file := labelFile{Nodes: []labelFileEntry{
+		// An exact match is used even if the ID is the prefix of another one
+		{ID: "node-1-id", Labels: map[string]string{"zone": "a"}},
+		{ID: "node-2", Labels: map[string]string{"zone": "b"}},
+	}}
+	changes, err := matchLabelFile(file, nodes)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "node-1-id", changes[0].node.ID)
+	assert.Equal(t, "node-2-id", changes[1].node.ID)
+}
+
+func TestNodeLabelApplyErrors(t *testing.T) {
+	testCases := []struct {
+		content       string
+		expectedError string
+	}{
+		{
+			content:       "nodes:\n  - labels: {zone: a}\n",
+			expectedError: "entry 1 must have one of hostname, id or label",
+		},
+		{
+			content:       "nodes:\n  - hostname: node-1\n    id: node-1-id\n",
+			expectedError: "entry 1 must have one of hostname, id or label",
+		},
+		{
+			content:       "nodes:\n  - hostname: node-9\n    labels: {zone: a}\n",
+			expectedError: "no node matches hostname node-9",
+		},
+		{
+			content:       "nodes:\n  - id: node-\n    labels: {zone: a}\n",
+			expectedError: "id node- is ambiguous (3 matches found)",
+		},
+		{
+			content:       "nodes: [",
+			expectedError: "invalid node labels",
+		},
+	}
+	for _, tc := range testCases {
+		filename, cleanup := writeLabelFile(t, tc.content)
+		cmd := newLabelApplyCommand(test.NewFakeCli(&fakeClient{
+			nodeListFunc: func() ([]swarm.Node, error) {
+				return labelNodes(), nil
+			},
+		}))
+		cmd.SetArgs([]string{"--file", filename})
+		cmd.SetOutput(ioutil.Discard)
+		testutil.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		cleanup()
+	}
+}
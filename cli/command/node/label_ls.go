@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type labelListOptions struct {
+	filter opts.FilterOpt
+	keys   []string
+}
+
+func newLabelListCommand(dockerCli command.Cli) *cobra.Command {
+	options := labelListOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the labels of nodes, with a column per label key",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLabelList(dockerCli, options)
+		},
+	}
+	flags := cmd.Flags()
+	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.StringSliceVar(&options.keys, "key", nil, "Only show these label keys")
+	return cmd
+}
+
+func runLabelList(dockerCli command.Cli, options labelListOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{Filters: options.filter.Value()})
+	if err != nil {
+		return err
+	}
+	sort.Sort(byHostname(nodes))
+
+	keys := options.keys
+	if len(keys) == 0 {
+		seen := make(map[string]bool)
+		for _, node := range nodes {
+			for key := range node.Spec.Labels {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+		sort.Strings(keys)
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tHOSTNAME\t%s\n", strings.Join(keys, "\t"))
+	for _, node := range nodes {
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			value, ok := node.Spec.Labels[key]
+			switch {
+			case !ok:
+				value = "-"
+			case value == "":
+				value = `""`
+			}
+			values[i] = value
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", node.ID, node.Description.Hostname, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,33 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/gotestyourself/gotestyourself/golden"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeLabelList(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return labelNodes(), nil
+		},
+	})
+	cmd := newLabelListCommand(cli)
+	require.NoError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "node-label-list.golden")
+}
+
+func TestNodeLabelListWithKeys(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return labelNodes(), nil
+		},
+	})
+	cmd := newLabelListCommand(cli)
+	cmd.SetArgs([]string{"--key", "zone,role"})
+	require.NoError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "node-label-list-with-keys.golden")
+}
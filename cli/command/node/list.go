@@ -1,6 +1,7 @@
 package node
 
 import (
+	"io"
 	"sort"
 
 	"github.com/docker/cli/cli"
@@ -8,6 +9,8 @@ import (
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
@@ -26,6 +29,7 @@ type listOptions struct {
 	quiet  bool
 	format string
 	filter opts.FilterOpt
+	watch  bool
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
@@ -44,14 +48,50 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", "Pretty-print nodes using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.watch, "watch", false, "Redraw the list whenever nodes change")
+	flags.SetAnnotation("watch", "version", []string{"1.30"})
 
 	return cmd
 }
 
 func runList(dockerCli command.Cli, options listOptions) error {
-	client := dockerCli.Client()
 	ctx := context.Background()
 
+	format := options.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+		if len(dockerCli.ConfigFile().NodesFormat) > 0 && !options.quiet {
+			format = dockerCli.ConfigFile().NodesFormat
+		}
+	}
+	nodesFormat := formatter.NewNodeFormat(format, options.quiet)
+
+	render := func(out io.Writer) error {
+		return writeNodeList(ctx, dockerCli, options, formatter.Context{
+			Output: out,
+			Format: nodesFormat,
+		})
+	}
+
+	if !options.watch {
+		return render(dockerCli.Out())
+	}
+
+	eventFilters := filters.NewArgs()
+	eventFilters.Add("type", events.NodeEventType)
+	eventC, errC := dockerCli.Client().Events(ctx, types.EventsOptions{Filters: eventFilters})
+
+	watchCtx := formatter.WatchContext{
+		Output: dockerCli.Out(),
+		Format: nodesFormat,
+		Render: render,
+	}
+	return watchCtx.Watch(ctx, eventC, errC)
+}
+
+func writeNodeList(ctx context.Context, dockerCli command.Cli, options listOptions, nodesCtx formatter.Context) error {
+	client := dockerCli.Client()
+
 	nodes, err := client.NodeList(
 		ctx,
 		types.NodeListOptions{Filters: options.filter.Value()})
@@ -68,18 +108,6 @@ func runList(dockerCli command.Cli, options listOptions) error {
 		}
 	}
 
-	format := options.format
-	if len(format) == 0 {
-		format = formatter.TableFormatKey
-		if len(dockerCli.ConfigFile().NodesFormat) > 0 && !options.quiet {
-			format = dockerCli.ConfigFile().NodesFormat
-		}
-	}
-
-	nodesCtx := formatter.Context{
-		Output: dockerCli.Out(),
-		Format: formatter.NewNodeFormat(format, options.quiet),
-	}
 	sort.Sort(byHostname(nodes))
 	return formatter.NodeWrite(nodesCtx, nodes, info)
 }
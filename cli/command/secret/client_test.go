@@ -9,10 +9,13 @@ import (
 
 type fakeClient struct {
 	client.Client
-	secretCreateFunc  func(swarm.SecretSpec) (types.SecretCreateResponse, error)
-	secretInspectFunc func(string) (swarm.Secret, []byte, error)
-	secretListFunc    func(types.SecretListOptions) ([]swarm.Secret, error)
-	secretRemoveFunc  func(string) error
+	secretCreateFunc   func(swarm.SecretSpec) (types.SecretCreateResponse, error)
+	secretInspectFunc  func(string) (swarm.Secret, []byte, error)
+	secretListFunc     func(types.SecretListOptions) ([]swarm.Secret, error)
+	secretRemoveFunc   func(string) error
+	serviceListFunc    func(types.ServiceListOptions) ([]swarm.Service, error)
+	serviceInspectFunc func(string) (swarm.Service, []byte, error)
+	serviceUpdateFunc  func(string, swarm.Version, swarm.ServiceSpec, types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
 }
 
 func (c *fakeClient) SecretCreate(ctx context.Context, spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
@@ -42,3 +45,24 @@ func (c *fakeClient) SecretRemove(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+func (c *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+	if c.serviceListFunc != nil {
+		return c.serviceListFunc(options)
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+	if c.serviceUpdateFunc != nil {
+		return c.serviceUpdateFunc(serviceID, version, service, options)
+	}
+	return types.ServiceUpdateResponse{}, nil
+}
+
+func (c *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+	if c.serviceInspectFunc != nil {
+		return c.serviceInspectFunc(serviceID)
+	}
+	return swarm.Service{}, nil, nil
+}
@@ -22,6 +22,7 @@ func NewSecretCommand(dockerCli *command.DockerCli) *cobra.Command {
 		newSecretCreateCommand(dockerCli),
 		newSecretInspectCommand(dockerCli),
 		newSecretRemoveCommand(dockerCli),
+		newSecretRotateCommand(dockerCli),
 	)
 	return cmd
 }
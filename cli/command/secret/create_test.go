@@ -25,11 +25,19 @@ func TestSecretCreateErrors(t *testing.T) {
 		expectedError    string
 	}{
 		{
-			args:          []string{"too_few"},
-			expectedError: "requires exactly 2 arguments",
+			args:          []string{},
+			expectedError: "requires at least 1 and at most 2 arguments",
 		},
 		{args: []string{"too", "many", "arguments"},
-			expectedError: "requires exactly 2 arguments",
+			expectedError: "requires at least 1 and at most 2 arguments",
+		},
+		{
+			args:          []string{"no_file"},
+			expectedError: "a file, or one of --generate, --from-env or --from-literal, is required",
+		},
+		{
+			args:          []string{"name", filepath.Join("testdata", secretDataFile), "--generate", "random:32/hex"},
+			expectedError: "a file can't be used with --generate, --from-env or --from-literal",
 		},
 		{
 			args: []string{"name", filepath.Join("testdata", secretDataFile)},
@@ -105,3 +113,21 @@ func TestSecretCreateWithLabels(t *testing.T) {
 	assert.NoError(t, cmd.Execute())
 	assert.Equal(t, "ID-"+name, strings.TrimSpace(cli.OutBuffer().String()))
 }
+
+func TestSecretCreateWithLiterals(t *testing.T) {
+	var actual []byte
+	cli := test.NewFakeCli(&fakeClient{
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			actual = spec.Data
+			return types.SecretCreateResponse{ID: "ID-" + spec.Name}, nil
+		},
+	})
+
+	cmd := newSecretCreateCommand(cli)
+	cmd.SetArgs([]string{"foo"})
+	cmd.Flags().Set("from-literal", "user=admin")
+	cmd.Flags().Set("from-literal", "password=secret")
+	cmd.Flags().Set("bundle-format", "json")
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, `{"password":"secret","user":"admin"}`, string(actual))
+}
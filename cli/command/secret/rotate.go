@@ -0,0 +1,104 @@
+package secret
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type rotateOptions struct {
+	secret string
+	file   string
+	name   string
+	labels opts.ListOpts
+	remove bool
+	detach bool
+	quiet  bool
+}
+
+func newSecretRotateCommand(dockerCli command.Cli) *cobra.Command {
+	options := rotateOptions{
+		labels: opts.NewListOpts(opts.ValidateEnv),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "rotate [OPTIONS] SECRET file|-",
+		Short: "Replace a secret with a new version in all the services that use it",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.secret = args[0]
+			options.file = args[1]
+			return runSecretRotate(dockerCli, options)
+		},
+		Tags: map[string]string{"version": "1.29"},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&options.name, "name", "", "Name of the new secret (default: the name of the secret with a version suffix)")
+	flags.VarP(&options.labels, "label", "l", "Secret labels, in addition to the labels of the secret")
+	flags.BoolVar(&options.remove, "rm", false, "Remove the secret once the services are updated")
+	flags.BoolVarP(&options.detach, "detach", "d", false, "Exit immediately instead of waiting for the services to converge")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress progress output")
+
+	return cmd
+}
+
+func runSecretRotate(dockerCli command.Cli, options rotateOptions) error {
+	client := dockerCli.Client()
+
+	rotation := service.Rotation{
+		Kind: "secret",
+		Inspect: func(ctx context.Context) (string, string, map[string]string, error) {
+			secret, _, err := client.SecretInspectWithRaw(ctx, options.secret)
+			return secret.ID, secret.Spec.Name, secret.Spec.Labels, err
+		},
+		Create: func(ctx context.Context, name string, labels map[string]string, data []byte) (string, error) {
+			spec := swarm.SecretSpec{
+				Annotations: swarm.Annotations{
+					Name:   name,
+					Labels: labels,
+				},
+				Data: data,
+			}
+			r, err := client.SecretCreate(ctx, spec)
+			return r.ID, err
+		},
+		Remove: func(ctx context.Context, id string) error {
+			return client.SecretRemove(ctx, id)
+		},
+		Rotate: rotateServiceSecret,
+	}
+	return service.RunRotate(dockerCli, rotation, service.RotateOptions{
+		File:   options.file,
+		Name:   options.name,
+		Labels: options.labels.GetAll(),
+		Remove: options.remove,
+		Detach: options.detach,
+		Quiet:  options.quiet,
+	})
+}
+
+// rotateServiceSecret changes the references to a secret in a service spec to
+// a new secret, with the same target, uid, gid and mode. It returns whether
+// the spec references the secret.
+func rotateServiceSecret(spec *swarm.ServiceSpec, oldID, newID, newName string) bool {
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if containerSpec == nil {
+		return false
+	}
+	found := false
+	for i, ref := range containerSpec.Secrets {
+		if ref.SecretID != oldID {
+			continue
+		}
+		rotated := *ref
+		rotated.SecretID = newID
+		rotated.SecretName = newName
+		containerSpec.Secrets[i] = &rotated
+		found = true
+	}
+	return found
+}
@@ -0,0 +1,210 @@
+package secret
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretRotate(t *testing.T) {
+	target := &swarm.SecretReferenceFileTarget{Name: "db_password", UID: "1000", GID: "1000", Mode: 0400}
+	services := []swarm.Service{
+		{
+			ID: "db-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "db"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Secrets: []*swarm.SecretReference{
+						{SecretID: "other-id", SecretName: "other", File: &swarm.SecretReferenceFileTarget{Name: "other"}},
+						{SecretID: "password-id", SecretName: "password_v1", File: target},
+					},
+				}},
+			},
+		},
+		{
+			ID: "web-id",
+			Spec: swarm.ServiceSpec{
+				Annotations:  swarm.Annotations{Name: "web"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}},
+			},
+		},
+	}
+
+	var (
+		created swarm.SecretSpec
+		updated = map[string]swarm.ServiceSpec{}
+		removed []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{
+				ID:   "password-id",
+				Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "password_v1", Labels: map[string]string{"app": "db"}}},
+			}, nil, nil
+		},
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			created = spec
+			return types.SecretCreateResponse{ID: "new-id"}, nil
+		},
+		secretRemoveFunc: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+		serviceListFunc: func(types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated[serviceID] = spec
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"password_v1", filepath.Join("testdata", secretDataFile)})
+	cmd.Flags().Set("label", "rotated=true")
+	cmd.Flags().Set("rm", "true")
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, "password_v2", created.Name)
+	assert.Equal(t, map[string]string{"app": "db", "rotated": "true"}, created.Labels)
+	assert.Equal(t, "new-id\n", cli.OutBuffer().String())
+
+	require.Len(t, updated, 1)
+	secrets := updated["db-id"].TaskTemplate.ContainerSpec.Secrets
+	assert.Equal(t, []*swarm.SecretReference{
+		{SecretID: "other-id", SecretName: "other", File: &swarm.SecretReferenceFileTarget{Name: "other"}},
+		{SecretID: "new-id", SecretName: "password_v2", File: target},
+	}, secrets)
+	assert.Equal(t, []string{"password-id"}, removed)
+}
+
+func TestSecretRotateErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"password", "-"})
+	cmd.Flags().Set("rm", "true")
+	cmd.Flags().Set("detach", "true")
+	testutil.ErrorContains(t, cmd.Execute(), "--rm can't be used with --detach")
+}
+
+func TestSecretRotatePartialFailure(t *testing.T) {
+	ref := func(id, name string) *swarm.SecretReference {
+		return &swarm.SecretReference{SecretID: id, SecretName: name, File: &swarm.SecretReferenceFileTarget{Name: "password"}}
+	}
+	services := []swarm.Service{}
+	for _, name := range []string{"api", "db", "web"} {
+		services = append(services, swarm.Service{
+			ID: name + "-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: name},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Secrets: []*swarm.SecretReference{ref("password-id", "password")},
+				}},
+			},
+		})
+	}
+
+	var (
+		updated    []string
+		rolledBack []string
+		removed    []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{ID: "password-id", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "password"}}}, nil, nil
+		},
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			return types.SecretCreateResponse{ID: "new-id"}, nil
+		},
+		secretRemoveFunc: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+		serviceListFunc: func(types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceInspectFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: serviceID}, nil, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			switch {
+			case options.Rollback == "previous" && serviceID == "db-id":
+				return types.ServiceUpdateResponse{}, errors.New("rollback failed")
+			case options.Rollback == "previous":
+				rolledBack = append(rolledBack, serviceID)
+			case serviceID == "web-id":
+				return types.ServiceUpdateResponse{}, errors.New("update out of sequence")
+			default:
+				updated = append(updated, serviceID)
+			}
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"--rm", "password", filepath.Join("testdata", secretDataFile)})
+	cmd.SetOutput(ioutil.Discard)
+	err := cmd.Execute()
+	require.Error(t, err)
+
+	// The services that were updated are rolled back, and the error tells
+	// which one still uses the new secret
+	assert.Equal(t, []string{"api-id", "db-id"}, updated)
+	assert.Equal(t, []string{"api-id"}, rolledBack)
+	assert.Equal(t, "web: update out of sequence\ndb: failed to roll back, the service uses secret password_v2: rollback failed", err.Error())
+	assert.Contains(t, cli.ErrBuffer().String(), "Rolled back service api\n")
+	// Neither secret is removed, since both are in use
+	assert.Len(t, removed, 0)
+}
+
+func TestSecretRotatePartialFailureRollback(t *testing.T) {
+	services := []swarm.Service{}
+	for _, name := range []string{"api", "web"} {
+		services = append(services, swarm.Service{
+			ID: name + "-id",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: name},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Secrets: []*swarm.SecretReference{{SecretID: "password-id", SecretName: "password"}},
+				}},
+			},
+		})
+	}
+
+	var removed []string
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(name string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{ID: "password-id", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "password"}}}, nil, nil
+		},
+		secretCreateFunc: func(spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			return types.SecretCreateResponse{ID: "new-id"}, nil
+		},
+		secretRemoveFunc: func(name string) error {
+			removed = append(removed, name)
+			return nil
+		},
+		serviceListFunc: func(types.ServiceListOptions) ([]swarm.Service, error) {
+			return services, nil
+		},
+		serviceUpdateFunc: func(serviceID string, version swarm.Version, spec swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			if serviceID == "web-id" && options.Rollback == "" {
+				return types.ServiceUpdateResponse{}, errors.New("update out of sequence")
+			}
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newSecretRotateCommand(cli)
+	cmd.SetArgs([]string{"--rm", "password", filepath.Join("testdata", secretDataFile)})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), "web: update out of sequence")
+
+	// Once all the services are rolled back, the new secret is removed and
+	// the previous one is kept
+	assert.Equal(t, []string{"new-id"}, removed)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service/progress"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/swarmkit/api/defaults"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+const (
+	// canaryLabel is set on a service while the canary tasks of an update
+	// are running. It holds the update config that is restored when the
+	// update is promoted.
+	canaryLabel = "com.docker.service.canary"
+
+	// canaryDelay is the delay of the update after the canary tasks are
+	// updated. The update is promoted or rolled back before it expires.
+	canaryDelay = 365 * 24 * time.Hour
+
+	// canaryPreviousLabel is set on a service when a canary update is
+	// promoted. It holds the spec the service had before the canary update,
+	// which it is rolled back to instead of the canary spec.
+	canaryPreviousLabel = "com.docker.service.canary.previous"
+)
+
+// startCanary changes a spec so that the update only replaces the given number
+// of tasks, and rolls back if any of them fails.
+func startCanary(spec *swarm.ServiceSpec, canaries uint64) error {
+	if spec.Mode.Replicated == nil {
+		return errors.New("canary updates are only supported for replicated services")
+	}
+
+	updateConfig, err := json.Marshal(spec.UpdateConfig)
+	if err != nil {
+		return err
+	}
+	if spec.Labels == nil {
+		spec.Labels = make(map[string]string)
+	}
+	spec.Labels[canaryLabel] = string(updateConfig)
+
+	canaryConfig := updateConfigFromDefaults(defaults.Service.Update)
+	if spec.UpdateConfig != nil {
+		*canaryConfig = *spec.UpdateConfig
+	}
+	canaryConfig.Parallelism = canaries
+	canaryConfig.Delay = canaryDelay
+	canaryConfig.FailureAction = swarm.UpdateFailureActionRollback
+	canaryConfig.MaxFailureRatio = 0
+	spec.UpdateConfig = canaryConfig
+	return nil
+}
+
+// promoteSpec restores the update config of a spec that was changed by
+// startCanary, so that the remaining tasks are updated.
+func promoteSpec(spec *swarm.ServiceSpec) error {
+	updateConfig, ok := spec.Labels[canaryLabel]
+	if !ok {
+		return errors.Errorf("service %s has no canary update to promote", spec.Name)
+	}
+
+	var config *swarm.UpdateConfig
+	if err := json.Unmarshal([]byte(updateConfig), &config); err != nil {
+		return errors.Wrapf(err, "invalid %s label", canaryLabel)
+	}
+	spec.UpdateConfig = config
+	delete(spec.Labels, canaryLabel)
+	return nil
+}
+
+// waitOnCanaries waits for the canary tasks of an update to be running and
+// stable, then promotes the update unless pause is set.
+func waitOnCanaries(ctx context.Context, dockerCli command.Cli, serviceID string, canaries uint64, pause bool, options *serviceOptions) error {
+	if !options.detach {
+		errChan := make(chan error, 1)
+		pipeReader, pipeWriter := io.Pipe()
+
+		go func() {
+			errChan <- progress.CanaryProgress(ctx, dockerCli.Client(), serviceID, canaries, pipeWriter)
+		}()
+
+		var err error
+		if options.quiet {
+			go io.Copy(ioutil.Discard, pipeReader)
+		} else {
+			err = jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil)
+		}
+		if err == nil {
+			err = <-errChan
+		}
+		// The canary tasks are not promoted if the wait is interrupted,
+		// since they were not verified
+		if err == progress.ErrCanaryInterrupted {
+			pause = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if pause || options.detach {
+		fmt.Fprintf(dockerCli.Out(), "Run `docker service promote %[1]s` to update the remaining tasks, or `docker service rollback %[1]s` to revert the update.\n", serviceID)
+		return nil
+	}
+	if err := promoteService(ctx, dockerCli, serviceID); err != nil {
+		return err
+	}
+	return waitOnService(ctx, dockerCli, serviceID, options.quiet)
+}
+
+func newPromoteCommand(dockerCli command.Cli) *cobra.Command {
+	options := newServiceOptions()
+
+	cmd := &cobra.Command{
+		Use:   "promote [OPTIONS] SERVICE",
+		Short: "Update the remaining tasks of a service after a canary update",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromote(dockerCli, options, args[0])
+		},
+		Tags: map[string]string{"version": "1.29"},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.quiet, flagQuiet, "q", false, "Suppress progress output")
+	addDetachFlag(flags, &options.detach)
+
+	return cmd
+}
+
+func runPromote(dockerCli command.Cli, options *serviceOptions, serviceID string) error {
+	ctx := context.Background()
+
+	if err := promoteService(ctx, dockerCli, serviceID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", serviceID)
+
+	if options.detach || versions.LessThan(dockerCli.Client().ClientVersion(), "1.29") {
+		return nil
+	}
+
+	return waitOnService(ctx, dockerCli, serviceID, options.quiet)
+}
+
+func promoteService(ctx context.Context, dockerCli command.Cli, serviceID string) error {
+	apiClient := dockerCli.Client()
+
+	service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	spec := service.Spec
+	if err := promoteSpec(&spec); err != nil {
+		return err
+	}
+
+	// Updating the service replaces its previous spec, which is the spec it
+	// had before the canary update, with the canary spec. The spec it had
+	// before is kept in a label, so that a rollback reverts the whole update.
+	if service.PreviousSpec != nil {
+		previous := *service.PreviousSpec
+		previous.Labels = make(map[string]string)
+		for k, v := range service.PreviousSpec.Labels {
+			if k != canaryPreviousLabel {
+				previous.Labels[k] = v
+			}
+		}
+		data, err := json.Marshal(previous)
+		if err != nil {
+			return err
+		}
+		spec.Labels[canaryPreviousLabel] = string(data)
+	}
+
+	updateOpts := types.ServiceUpdateOptions{RegistryAuthFrom: types.RegistryAuthFromSpec}
+	response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, spec, updateOpts)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range response.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+	return nil
+}
+
+// canaryRollbackSpec returns the spec a service had before a canary update
+// that was promoted, if it was not updated since, or nil. The daemon would
+// roll the service back to the canary spec instead.
+func canaryRollbackSpec(service swarm.Service) (*swarm.ServiceSpec, error) {
+	previous, ok := service.Spec.Labels[canaryPreviousLabel]
+	if !ok || service.PreviousSpec == nil {
+		return nil, nil
+	}
+	if _, ok := service.PreviousSpec.Labels[canaryLabel]; !ok {
+		return nil, nil
+	}
+
+	var spec swarm.ServiceSpec
+	if err := json.Unmarshal([]byte(previous), &spec); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s label", canaryPreviousLabel)
+	}
+	return &spec, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func canaryTestSpec() swarm.ServiceSpec {
+	replicas := uint64(4)
+	spec := diffTestSpec("nginx:1.12")
+	spec.Mode = swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}
+	spec.UpdateConfig = &swarm.UpdateConfig{Parallelism: 2, Delay: time.Second, FailureAction: swarm.UpdateFailureActionPause}
+	return spec
+}
+
+func TestStartCanaryAndPromote(t *testing.T) {
+	spec := canaryTestSpec()
+	original := *spec.UpdateConfig
+
+	require.NoError(t, startCanary(&spec, 1))
+	assert.Contains(t, spec.Labels, canaryLabel)
+	assert.Equal(t, uint64(1), spec.UpdateConfig.Parallelism)
+	assert.Equal(t, canaryDelay, spec.UpdateConfig.Delay)
+	assert.Equal(t, swarm.UpdateFailureActionRollback, spec.UpdateConfig.FailureAction)
+
+	require.NoError(t, promoteSpec(&spec))
+	assert.NotContains(t, spec.Labels, canaryLabel)
+	assert.Equal(t, original, *spec.UpdateConfig)
+
+	testutil.ErrorContains(t, promoteSpec(&spec), "service web has no canary update to promote")
+}
+
+func TestStartCanaryGlobalService(t *testing.T) {
+	spec := diffTestSpec("nginx")
+	spec.Mode = swarm.ServiceMode{Global: &swarm.GlobalService{}}
+	testutil.ErrorContains(t, startCanary(&spec, 1), "only supported for replicated services")
+}
+
+func TestUpdateCanaryPause(t *testing.T) {
+	var updated swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: canaryTestSpec()}, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--image", "nginx:1.13", "--canary", "1", "--canary-pause", "--detach", "web"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, "nginx:1.13", updated.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, uint64(1), updated.UpdateConfig.Parallelism)
+	assert.Equal(t, `{"Parallelism":2,"Delay":1000000000,"FailureAction":"pause","MaxFailureRatio":0,"Order":""}`, updated.Labels[canaryLabel])
+	assert.Contains(t, cli.OutBuffer().String(), "Run `docker service promote web`")
+}
+
+func TestUpdateCanaryErrors(t *testing.T) {
+	inProgress := canaryTestSpec()
+	require.NoError(t, startCanary(&inProgress, 1))
+
+	testCases := []struct {
+		args          []string
+		spec          swarm.ServiceSpec
+		expectedError string
+	}{
+		{
+			args:          []string{"--canary-pause", "web"},
+			spec:          canaryTestSpec(),
+			expectedError: "--canary-pause can only be used with --canary",
+		},
+		{
+			args:          []string{"--image", "nginx:1.14", "web"},
+			spec:          inProgress,
+			expectedError: "service web has a canary update in progress",
+		},
+	}
+	for _, tc := range testCases {
+		spec := tc.spec
+		cmd := newUpdateCommand(test.NewFakeCli(&fakeClient{
+			serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+				return swarm.Service{ID: "id", Spec: spec}, nil, nil
+			},
+		}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		testutil.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestPromote(t *testing.T) {
+	spec := canaryTestSpec()
+	require.NoError(t, startCanary(&spec, 1))
+
+	var updated swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: spec}, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newPromoteCommand(cli)
+	cmd.SetArgs([]string{"--detach", "web"})
+	require.NoError(t, cmd.Execute())
+
+	assert.NotContains(t, updated.Labels, canaryLabel)
+	assert.Equal(t, canaryTestSpec().UpdateConfig, updated.UpdateConfig)
+	assert.Equal(t, "web\n", cli.OutBuffer().String())
+}
+
+func TestPromoteThenRollback(t *testing.T) {
+	// The fake client keeps the spec and the previous spec of the service the
+	// way the daemon does, and returns a copy of them
+	service := swarm.Service{ID: "id", Spec: canaryTestSpec()}
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			var inspected swarm.Service
+			data, err := json.Marshal(service)
+			if err == nil {
+				err = json.Unmarshal(data, &inspected)
+			}
+			return inspected, nil, err
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, spec swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			if version != service.Version {
+				return types.ServiceUpdateResponse{}, errors.New("update out of sequence")
+			}
+			current := service.Spec
+			if options.Rollback == "previous" {
+				spec = *service.PreviousSpec
+			}
+			service.Spec, service.PreviousSpec = spec, &current
+			service.Version.Index++
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--image", "nginx:1.13", "--canary", "1", "--canary-pause", "--detach", "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, service.Spec.Labels, canaryLabel)
+
+	// The service is promoted with a single update
+	version := service.Version.Index
+	cmd = newPromoteCommand(cli)
+	cmd.SetArgs([]string{"--detach", "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, version+1, service.Version.Index)
+	assert.Equal(t, "nginx:1.13", service.Spec.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, canaryTestSpec().UpdateConfig, service.Spec.UpdateConfig)
+	assert.NotContains(t, service.Spec.Labels, canaryLabel)
+
+	cmd = newRollbackCommand(cli)
+	cmd.SetArgs([]string{"--detach", "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "nginx:1.12", service.Spec.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, canaryTestSpec().UpdateConfig, service.Spec.UpdateConfig)
+	assert.NotContains(t, service.Spec.Labels, canaryLabel)
+	assert.NotContains(t, service.Spec.Labels, canaryPreviousLabel)
+
+	// Rolling back again returns to the promoted spec, as the daemon does
+	cmd = newRollbackCommand(cli)
+	cmd.SetArgs([]string{"--detach", "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "nginx:1.13", service.Spec.TaskTemplate.ContainerSpec.Image)
+}
+
+func TestRollbackAfterPromotedServiceUpdated(t *testing.T) {
+	// The spec before the canary update is only used while the previous spec
+	// is the canary spec
+	spec := canaryTestSpec()
+	spec.Labels = map[string]string{canaryPreviousLabel: "{}"}
+	previous := spec
+
+	rollbackSpec, err := canaryRollbackSpec(swarm.Service{Spec: spec, PreviousSpec: &previous})
+	require.NoError(t, err)
+	assert.Nil(t, rollbackSpec)
+}
@@ -13,18 +13,51 @@ type fakeClient struct {
 	client.Client
 	serviceInspectWithRawFunc func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error)
 	serviceUpdateFunc         func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
+	serviceCreateFunc         func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error)
 	serviceListFunc           func(context.Context, types.ServiceListOptions) ([]swarm.Service, error)
 	infoFunc                  func(ctx context.Context) (types.Info, error)
+	taskInspectWithRawFunc    func(ctx context.Context, taskID string) (swarm.Task, []byte, error)
+	taskListFunc              func(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error)
+	nodeInspectWithRawFunc    func(ctx context.Context, nodeID string) (swarm.Node, []byte, error)
+	nodeListFunc              func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error)
+	containerStatsFunc        func(ctx context.Context, container string, stream bool) (types.ContainerStats, error)
+}
+
+func (f *fakeClient) ContainerStats(ctx context.Context, container string, stream bool) (types.ContainerStats, error) {
+	if f.containerStatsFunc != nil {
+		return f.containerStatsFunc(ctx, container, stream)
+	}
+	return types.ContainerStats{}, nil
 }
 
 func (f *fakeClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+	if f.nodeListFunc != nil {
+		return f.nodeListFunc(ctx, options)
+	}
 	return nil, nil
 }
 
+func (f *fakeClient) NodeInspectWithRaw(ctx context.Context, nodeID string) (swarm.Node, []byte, error) {
+	if f.nodeInspectWithRawFunc != nil {
+		return f.nodeInspectWithRawFunc(ctx, nodeID)
+	}
+	return swarm.Node{}, nil, nil
+}
+
 func (f *fakeClient) TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+	if f.taskListFunc != nil {
+		return f.taskListFunc(ctx, options)
+	}
 	return nil, nil
 }
 
+func (f *fakeClient) TaskInspectWithRaw(ctx context.Context, taskID string) (swarm.Task, []byte, error) {
+	if f.taskInspectWithRawFunc != nil {
+		return f.taskInspectWithRawFunc(ctx, taskID)
+	}
+	return swarm.Task{}, nil, nil
+}
+
 func (f *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
 	if f.serviceInspectWithRawFunc != nil {
 		return f.serviceInspectWithRawFunc(ctx, serviceID, options)
@@ -49,6 +82,14 @@ func (f *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, versio
 	return types.ServiceUpdateResponse{}, nil
 }
 
+func (f *fakeClient) ServiceCreate(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+	if f.serviceCreateFunc != nil {
+		return f.serviceCreateFunc(ctx, service, options)
+	}
+
+	return types.ServiceCreateResponse{}, nil
+}
+
 func (f *fakeClient) Info(ctx context.Context) (types.Info, error) {
 	if f.infoFunc == nil {
 		return types.Info{}, nil
@@ -56,6 +97,9 @@ func (f *fakeClient) Info(ctx context.Context) (types.Info, error) {
 	return f.infoFunc(ctx)
 }
 
+func (f *fakeClient) NegotiateAPIVersion(ctx context.Context) {
+}
+
 func newService(id string, name string) swarm.Service {
 	return swarm.Service{
 		ID:   id,
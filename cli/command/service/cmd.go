@@ -23,10 +23,15 @@ func NewServiceCommand(dockerCli *command.DockerCli) *cobra.Command {
 		newPsCommand(dockerCli),
 		newListCommand(dockerCli),
 		newRemoveCommand(dockerCli),
+		newRestartCommand(dockerCli),
 		newScaleCommand(dockerCli),
 		newUpdateCommand(dockerCli),
 		newLogsCommand(dockerCli),
 		newRollbackCommand(dockerCli),
+		newPromoteCommand(dockerCli),
+		newDiffCommand(dockerCli),
+		newExecCommand(dockerCli),
+		newStatsCommand(dockerCli),
 	)
 	return cmd
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -18,9 +19,17 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create a new service",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			// The image can be set in the spec file instead
+			if opts.specFile != "" {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.image = args[0]
+			if len(args) > 0 {
+				opts.image = args[0]
+			}
 			if len(args) > 1 {
 				opts.args = args[1:]
 			}
@@ -30,6 +39,7 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&opts.mode, flagMode, "replicated", "Service mode (replicated or global)")
 	flags.StringVar(&opts.name, flagName, "", "Service name")
+	flags.StringVar(&opts.specFile, flagSpec, "", "Read the service spec from a JSON or YAML file")
 
 	addServiceFlags(flags, opts, buildServiceDefaultFlagMapping())
 
@@ -57,6 +67,8 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation(flagDNSSearch, "version", []string{"1.25"})
 	flags.Var(&opts.hosts, flagHost, "Set one or more custom host-to-IP mappings (host:ip)")
 	flags.SetAnnotation(flagHost, "version", []string{"1.25"})
+	flags.Var(&opts.resources.resGenericResources, flagGenericResources, "Reserve user defined resources (kind=count)")
+	flags.SetAnnotation(flagGenericResources, "version", []string{"1.32"})
 
 	flags.SetInterspersed(false)
 	return cmd
@@ -73,6 +85,12 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 		return err
 	}
 
+	if opts.specFile != "" {
+		if service, err = mergeCreateSpec(opts.specFile, service, flags); err != nil {
+			return err
+		}
+	}
+
 	specifiedSecrets := opts.secrets.Value()
 	if len(specifiedSecrets) > 0 {
 		// parse and validate secrets
@@ -93,6 +111,17 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 		service.TaskTemplate.ContainerSpec.Configs = configs
 	}
 
+	if opts.specFile != "" {
+		if err := validateServiceSpec(service); err != nil {
+			return err
+		}
+		writeSpecDiff(ctx, dockerCli, swarm.ServiceSpec{}, service)
+	}
+
+	if err := CheckPlacement(ctx, dockerCli, service, opts.strict); err != nil {
+		return err
+	}
+
 	if err := resolveServiceImageDigestContentTrust(dockerCli, &service); err != nil {
 		return err
 	}
@@ -100,7 +129,7 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 	// only send auth if flag was set
 	if opts.registryAuth {
 		// Retrieve encoded auth token from the image reference
-		encodedAuth, err := command.RetrieveAuthTokenFromImage(ctx, dockerCli, opts.image)
+		encodedAuth, err := command.RetrieveAuthTokenFromImage(ctx, dockerCli, service.TaskTemplate.ContainerSpec.Image)
 		if err != nil {
 			return err
 		}
@@ -129,3 +158,174 @@ func runCreate(dockerCli command.Cli, flags *pflag.FlagSet, opts *serviceOptions
 
 	return waitOnService(ctx, dockerCli, response.ID, opts.quiet)
 }
+
+// mergeCreateSpec merges the spec in a file with the spec built from the
+// flags. Flags that are set take precedence over the file, even if they are
+// set to a zero value.
+func mergeCreateSpec(filename string, flagSpec swarm.ServiceSpec, flags *pflag.FlagSet) (swarm.ServiceSpec, error) {
+	fields, err := readSpecFile(filename)
+	if err != nil {
+		return flagSpec, err
+	}
+	overlayFields, err := specToFields(flagSpec)
+	if err != nil {
+		return flagSpec, err
+	}
+
+	containerSpec := []string{"TaskTemplate", "ContainerSpec"}
+	if flagSpec.TaskTemplate.ContainerSpec.Image != "" {
+		overrideSpecField(fields, overlayFields, append(containerSpec, "Image"))
+	}
+	if len(flagSpec.TaskTemplate.ContainerSpec.Args) > 0 {
+		overrideSpecField(fields, overlayFields, append(containerSpec, "Args"))
+	}
+	for name, path := range createSpecFlagFields {
+		if flags.Changed(name) {
+			overrideSpecField(fields, overlayFields, path)
+		}
+	}
+	if noHealthcheck, err := flags.GetBool(flagNoHealthcheck); err == nil && noHealthcheck {
+		overrideSpecField(fields, overlayFields, append(containerSpec, "Healthcheck"))
+	}
+
+	spec, err := specFromFields(fields)
+	if err != nil {
+		return spec, err
+	}
+	// The service mode and the update, rollback and restart settings have
+	// defaults, which are used when the file doesn't set them.
+	if flags.Changed(flagMode) || flags.Changed(flagReplicas) || (spec.Mode.Global == nil && spec.Mode.Replicated == nil) {
+		spec.Mode = flagSpec.Mode
+	}
+	spec.UpdateConfig = mergeUpdateConfig(spec.UpdateConfig, flagSpec.UpdateConfig, flags, updateConfigFlags{
+		parallelism:     flagUpdateParallelism,
+		delay:           flagUpdateDelay,
+		monitor:         flagUpdateMonitor,
+		failureAction:   flagUpdateFailureAction,
+		maxFailureRatio: flagUpdateMaxFailureRatio,
+		order:           flagUpdateOrder,
+	})
+	spec.RollbackConfig = mergeUpdateConfig(spec.RollbackConfig, flagSpec.RollbackConfig, flags, updateConfigFlags{
+		parallelism:     flagRollbackParallelism,
+		delay:           flagRollbackDelay,
+		monitor:         flagRollbackMonitor,
+		failureAction:   flagRollbackFailureAction,
+		maxFailureRatio: flagRollbackMaxFailureRatio,
+		order:           flagRollbackOrder,
+	})
+	spec.TaskTemplate.RestartPolicy = mergeRestartPolicy(spec.TaskTemplate.RestartPolicy, flagSpec.TaskTemplate.RestartPolicy, flags)
+	return spec, nil
+}
+
+// createSpecFlagFields are the fields of a service spec that are set by the
+// flags of `docker service create`, by flag name. The service mode and the
+// update, rollback and restart settings are merged separately.
+var createSpecFlagFields = map[string][]string{
+	flagName:              {"Name"},
+	flagLabel:             {"Labels"},
+	flagContainerLabel:    {"TaskTemplate", "ContainerSpec", "Labels"},
+	flagEnv:               {"TaskTemplate", "ContainerSpec", "Env"},
+	flagEnvFile:           {"TaskTemplate", "ContainerSpec", "Env"},
+	flagEntrypoint:        {"TaskTemplate", "ContainerSpec", "Command"},
+	flagHostname:          {"TaskTemplate", "ContainerSpec", "Hostname"},
+	flagWorkdir:           {"TaskTemplate", "ContainerSpec", "Dir"},
+	flagUser:              {"TaskTemplate", "ContainerSpec", "User"},
+	flagGroup:             {"TaskTemplate", "ContainerSpec", "Groups"},
+	flagCredentialSpec:    {"TaskTemplate", "ContainerSpec", "Privileges", "CredentialSpec"},
+	flagStopSignal:        {"TaskTemplate", "ContainerSpec", "StopSignal"},
+	flagStopGracePeriod:   {"TaskTemplate", "ContainerSpec", "StopGracePeriod"},
+	flagTTY:               {"TaskTemplate", "ContainerSpec", "TTY"},
+	flagReadOnly:          {"TaskTemplate", "ContainerSpec", "ReadOnly"},
+	flagMount:             {"TaskTemplate", "ContainerSpec", "Mounts"},
+	flagDNS:               {"TaskTemplate", "ContainerSpec", "DNSConfig", "Nameservers"},
+	flagDNSOption:         {"TaskTemplate", "ContainerSpec", "DNSConfig", "Options"},
+	flagDNSSearch:         {"TaskTemplate", "ContainerSpec", "DNSConfig", "Search"},
+	flagHost:              {"TaskTemplate", "ContainerSpec", "Hosts"},
+	flagHealthCmd:         {"TaskTemplate", "ContainerSpec", "Healthcheck", "Test"},
+	flagHealthInterval:    {"TaskTemplate", "ContainerSpec", "Healthcheck", "Interval"},
+	flagHealthTimeout:     {"TaskTemplate", "ContainerSpec", "Healthcheck", "Timeout"},
+	flagHealthRetries:     {"TaskTemplate", "ContainerSpec", "Healthcheck", "Retries"},
+	flagHealthStartPeriod: {"TaskTemplate", "ContainerSpec", "Healthcheck", "StartPeriod"},
+	flagLimitCPU:          {"TaskTemplate", "Resources", "Limits", "NanoCPUs"},
+	flagLimitMemory:       {"TaskTemplate", "Resources", "Limits", "MemoryBytes"},
+	flagReserveCPU:        {"TaskTemplate", "Resources", "Reservations", "NanoCPUs"},
+	flagReserveMemory:     {"TaskTemplate", "Resources", "Reservations", "MemoryBytes"},
+	flagGenericResources:  {"TaskTemplate", "Resources", "Reservations", "GenericResources"},
+	flagConstraint:        {"TaskTemplate", "Placement", "Constraints"},
+	flagPlacementPref:     {"TaskTemplate", "Placement", "Preferences"},
+	flagNetwork:           {"TaskTemplate", "Networks"},
+	flagLogDriver:         {"TaskTemplate", "LogDriver", "Name"},
+	flagLogOpt:            {"TaskTemplate", "LogDriver", "Options"},
+	flagEndpointMode:      {"EndpointSpec", "Mode"},
+	flagPublish:           {"EndpointSpec", "Ports"},
+}
+
+// updateConfigFlags are the names of the flags that set the fields of an
+// update or a rollback config.
+type updateConfigFlags struct {
+	parallelism     string
+	delay           string
+	monitor         string
+	failureAction   string
+	maxFailureRatio string
+	order           string
+}
+
+// mergeUpdateConfig sets the fields of the update or rollback config in a
+// file that are set by a flag. The config built from the flags is used if the
+// file has none.
+func mergeUpdateConfig(fileConfig, flagConfig *swarm.UpdateConfig, flags *pflag.FlagSet, names updateConfigFlags) *swarm.UpdateConfig {
+	if fileConfig == nil {
+		return flagConfig
+	}
+	if flagConfig == nil {
+		return fileConfig
+	}
+
+	config := *fileConfig
+	if flags.Changed(names.parallelism) {
+		config.Parallelism = flagConfig.Parallelism
+	}
+	if flags.Changed(names.delay) {
+		config.Delay = flagConfig.Delay
+	}
+	if flags.Changed(names.monitor) {
+		config.Monitor = flagConfig.Monitor
+	}
+	if flags.Changed(names.failureAction) {
+		config.FailureAction = flagConfig.FailureAction
+	}
+	if flags.Changed(names.maxFailureRatio) {
+		config.MaxFailureRatio = flagConfig.MaxFailureRatio
+	}
+	if flags.Changed(names.order) {
+		config.Order = flagConfig.Order
+	}
+	return &config
+}
+
+// mergeRestartPolicy sets the fields of the restart policy in a file that are
+// set by a flag. The policy built from the flags is used if the file has none.
+func mergeRestartPolicy(filePolicy, flagPolicy *swarm.RestartPolicy, flags *pflag.FlagSet) *swarm.RestartPolicy {
+	if filePolicy == nil {
+		return flagPolicy
+	}
+	if flagPolicy == nil {
+		return filePolicy
+	}
+
+	policy := *filePolicy
+	if flags.Changed(flagRestartCondition) {
+		policy.Condition = flagPolicy.Condition
+	}
+	if flags.Changed(flagRestartDelay) {
+		policy.Delay = flagPolicy.Delay
+	}
+	if flags.Changed(flagRestartMaxAttempts) {
+		policy.MaxAttempts = flagPolicy.MaxAttempts
+	}
+	if flags.Changed(flagRestartWindow) {
+		policy.Window = flagPolicy.Window
+	}
+	return &policy
+}
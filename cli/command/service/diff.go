@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/inspect"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type diffOptions struct {
+	service  string
+	specFile string
+}
+
+func newDiffCommand(dockerCli command.Cli) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] SERVICE",
+		Short: "Show the changes between the previous and the current configuration of a service",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.service = args[0]
+			return runDiff(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.specFile, "spec", "", "Show the changes between the current configuration and the one in a JSON or YAML file")
+	return cmd
+}
+
+func runDiff(dockerCli command.Cli, opts diffOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	service, _, err := client.ServiceInspectWithRaw(ctx, opts.service, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	var previous, current swarm.ServiceSpec
+	if opts.specFile != "" {
+		// The spec file is merged into the current spec the way
+		// `docker service update --spec` does
+		previous = service.Spec
+		if current, err = mergeUpdateSpec(opts.specFile, service.Spec); err != nil {
+			return err
+		}
+	} else {
+		if service.PreviousSpec == nil {
+			return errors.Errorf("service %s has no previous configuration", opts.service)
+		}
+		previous, current = *service.PreviousSpec, service.Spec
+	}
+
+	changes := formatter.ServiceSpecDiff(previous, current, networkRefFunc(ctx, client))
+	if len(changes) == 0 {
+		fmt.Fprintln(dockerCli.Err(), "No changes")
+		return nil
+	}
+	formatter.ServiceSpecDiffWrite(dockerCli.Out(), changes)
+	return nil
+}
+
+// networkRefFunc returns a function that resolves the networks of a service,
+// for the diff of two specs.
+func networkRefFunc(ctx context.Context, apiClient client.NetworkAPIClient) inspect.GetRefFunc {
+	return func(ref string) (interface{}, []byte, error) {
+		network, _, err := apiClient.NetworkInspectWithRaw(ctx, ref, types.NetworkInspectOptions{Scope: "swarm"})
+		return network, nil, err
+	}
+}
+
+// writeSpecDiff shows the changes that are made by creating or updating a
+// service from a spec file, before they are applied. They are written to the
+// standard error, so that the standard output only holds the ID of the service.
+func writeSpecDiff(ctx context.Context, dockerCli command.Cli, previous, current swarm.ServiceSpec) {
+	changes := formatter.ServiceSpecDiff(previous, current, networkRefFunc(ctx, dockerCli.Client()))
+	formatter.ServiceSpecDiffWrite(dockerCli.Err(), changes)
+}
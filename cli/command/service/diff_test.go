@@ -0,0 +1,143 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func diffTestSpec(image string, env ...string) swarm.ServiceSpec {
+	return swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{Image: image, Env: env},
+		},
+	}
+}
+
+func TestServiceDiff(t *testing.T) {
+	previous := diffTestSpec("nginx:1.12", "A=1", "B=2")
+	current := diffTestSpec("nginx:1.13", "A=1", "B=3")
+	current.TaskTemplate.Resources = &swarm.ResourceRequirements{
+		Limits: &swarm.Resources{MemoryBytes: 64 * 1024 * 1024},
+	}
+
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: current, PreviousSpec: &previous}, nil, nil
+		},
+	})
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	require.NoError(t, cmd.Execute())
+
+	expected := `~ ContainerSpec.Image: nginx:1.12 -> nginx:1.13
+- ContainerSpec.Env: B=2
++ ContainerSpec.Env: B=3
++ Resources.Limits.Memory: 64MiB
+`
+	assert.Equal(t, expected, cli.OutBuffer().String())
+}
+
+func TestServiceDiffZeroValues(t *testing.T) {
+	previousReplicas, currentReplicas := uint64(3), uint64(0)
+	previous := diffTestSpec("nginx:1.13")
+	previous.Mode.Replicated = &swarm.ReplicatedService{Replicas: &previousReplicas}
+	previous.UpdateConfig = &swarm.UpdateConfig{Parallelism: 2, Delay: time.Second}
+	current := diffTestSpec("nginx:1.13")
+	current.Mode.Replicated = &swarm.ReplicatedService{Replicas: &currentReplicas}
+	current.UpdateConfig = &swarm.UpdateConfig{}
+
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: current, PreviousSpec: &previous}, nil, nil
+		},
+	})
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	require.NoError(t, cmd.Execute())
+
+	expected := `~ Service Mode.Replicas: 3 -> 0
+~ UpdateConfig.Parallelism: 2 -> 0
+~ UpdateConfig.Delay: 1s -> 0s
+`
+	assert.Equal(t, expected, cli.OutBuffer().String())
+}
+
+func TestServiceDiffNoPreviousSpec(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"web"})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), "service web has no previous configuration")
+}
+
+func TestServiceDiffSpecFile(t *testing.T) {
+	current := diffTestSpec("nginx:1.13")
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: current}, nil, nil
+		},
+	})
+
+	dir, err := ioutil.TempDir("", "service-diff")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	specFile := filepath.Join(dir, "spec.json")
+	require.NoError(t, ioutil.WriteFile(specFile, []byte(`{"Name": "web", "TaskTemplate": {"ContainerSpec": {"Image": "nginx:1.13"}}}`), 0644))
+
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"--spec", specFile, "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "", cli.OutBuffer().String())
+	assert.Equal(t, "No changes\n", cli.ErrBuffer().String())
+
+	require.NoError(t, ioutil.WriteFile(specFile, []byte(`{"Name": "web", "TaskTemplate": {"ContainerSpec": {"Image": "nginx:1.14"}}}`), 0644))
+	cmd = newDiffCommand(cli)
+	cmd.SetArgs([]string{"--spec", specFile, "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "~ ContainerSpec.Image: nginx:1.13 -> nginx:1.14\n", cli.OutBuffer().String())
+}
+
+func TestServiceDiffSpecFileYAML(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: diffTestSpec("nginx:1.13")}, nil, nil
+		},
+	})
+	filename, cleanup := writeSpecFile(t, "Name: web\nTaskTemplate:\n  ContainerSpec:\n    Image: nginx:1.14\n")
+	defer cleanup()
+
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "~ ContainerSpec.Image: nginx:1.13 -> nginx:1.14\n", cli.OutBuffer().String())
+}
+
+func TestServiceDiffPartialSpecFile(t *testing.T) {
+	// The fields that are not set in the file are kept, as with
+	// `docker service update --spec`
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: diffTestSpec("nginx:1.13", "A=1")}, nil, nil
+		},
+	})
+	filename, cleanup := writeSpecFile(t, "TaskTemplate:\n  ContainerSpec:\n    Image: nginx:1.14\n")
+	defer cleanup()
+
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "web"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "~ ContainerSpec.Image: nginx:1.13 -> nginx:1.14\n", cli.OutBuffer().String())
+}
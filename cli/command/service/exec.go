@@ -0,0 +1,246 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/container"
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type execOptions struct {
+	service     string
+	command     []string
+	slot        int
+	task        string
+	nodeHosts   opts.ListOpts
+	detachKeys  string
+	interactive bool
+	tty         bool
+	user        string
+	privileged  bool
+	env         opts.ListOpts
+
+	// newNodeClient creates a client for the engine of the node the task
+	// runs on, when it isn't the node the CLI is connected to
+	newNodeClient func(host string) (client.APIClient, error)
+}
+
+func newExecCommand(dockerCli *command.DockerCli) *cobra.Command {
+	options := execOptions{
+		nodeHosts:     opts.NewListOpts(validateNodeHost),
+		env:           opts.NewListOpts(opts.ValidateEnv),
+		newNodeClient: dockerCli.NewNodeClient,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "exec [OPTIONS] SERVICE COMMAND [ARG...]",
+		Short: "Run a command in a running task of a service",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.service = args[0]
+			options.command = args[1:]
+			return runServiceExec(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+
+	flags.IntVar(&options.slot, "slot", 0, "Run the command in the task of a slot")
+	flags.StringVar(&options.task, "task", "", "Run the command in a task, given by its ID")
+	flags.Var(&options.nodeHosts, "node-host", "Engine endpoint of a node (format: NODE=HOST)")
+	flags.StringVarP(&options.detachKeys, "detach-keys", "", "", "Override the key sequence for detaching a container")
+	flags.BoolVarP(&options.interactive, "interactive", "i", false, "Keep STDIN open even if not attached")
+	flags.BoolVarP(&options.tty, "tty", "t", false, "Allocate a pseudo-TTY")
+	flags.StringVarP(&options.user, "user", "u", "", "Username or UID (format: <name|uid>[:<group|gid>])")
+	flags.BoolVarP(&options.privileged, "privileged", "", false, "Give extended privileges to the command")
+	flags.VarP(&options.env, "env", "e", "Set environment variables")
+
+	return cmd
+}
+
+func runServiceExec(dockerCli command.Cli, options execOptions) error {
+	if options.slot != 0 && options.task != "" {
+		return errors.New("--slot and --task can't be used together")
+	}
+
+	ctx := context.Background()
+	apiClient := dockerCli.Client()
+
+	service, _, err := apiClient.ServiceInspectWithRaw(ctx, options.service, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	task, err := selectExecTask(ctx, apiClient, service, options)
+	if err != nil {
+		return err
+	}
+
+	nodeCli, err := nodeExecCli(ctx, dockerCli, task.NodeID, options)
+	if err != nil {
+		return err
+	}
+
+	execConfig := container.NewExecConfig(container.ExecOptions{
+		User:        options.user,
+		Privileged:  options.privileged,
+		Tty:         options.tty,
+		Interactive: options.interactive,
+		DetachKeys:  options.detachKeys,
+		Env:         options.env.GetAll(),
+		Command:     options.command,
+	}, dockerCli.ConfigFile())
+
+	return container.ExecInContainer(ctx, nodeCli, task.Status.ContainerStatus.ContainerID, execConfig)
+}
+
+// selectExecTask returns the running task of a service the command runs in:
+// the task with the given ID, or the task of the given slot, or else the
+// running task with the lowest slot.
+func selectExecTask(ctx context.Context, apiClient client.APIClient, service swarm.Service, options execOptions) (swarm.Task, error) {
+	filter := filters.NewArgs()
+	filter.Add("service", service.ID)
+	filter.Add("desired-state", string(swarm.TaskStateRunning))
+
+	tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{Filters: filter})
+	if err != nil {
+		return swarm.Task{}, err
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Slot < tasks[j].Slot })
+
+	if options.task != "" {
+		if tasks, err = matchTaskID(tasks, options.task); err != nil {
+			return swarm.Task{}, err
+		}
+	}
+
+	for _, task := range tasks {
+		switch {
+		case options.task != "":
+			if !strings.HasPrefix(task.ID, options.task) {
+				continue
+			}
+		case options.slot != 0:
+			if task.Slot != options.slot {
+				continue
+			}
+		}
+		if task.Status.State != swarm.TaskStateRunning || task.Status.ContainerStatus.ContainerID == "" {
+			if options.task != "" || options.slot != 0 {
+				return task, errors.Errorf("task %s is not running", task.ID)
+			}
+			continue
+		}
+		return task, nil
+	}
+
+	switch {
+	case options.task != "":
+		return swarm.Task{}, errors.Errorf("no such task in service %s: %s", options.service, options.task)
+	case options.slot != 0:
+		return swarm.Task{}, errors.Errorf("no task in slot %d of service %s", options.slot, options.service)
+	default:
+		return swarm.Task{}, errors.Errorf("service %s has no running task", options.service)
+	}
+}
+
+// matchTaskID returns the task with the given ID, or else the tasks whose ID
+// starts with it. It is an error if more than one task matches.
+func matchTaskID(tasks []swarm.Task, id string) ([]swarm.Task, error) {
+	var matched []swarm.Task
+	for _, task := range tasks {
+		if task.ID == id {
+			return []swarm.Task{task}, nil
+		}
+		if strings.HasPrefix(task.ID, id) {
+			matched = append(matched, task)
+		}
+	}
+	if len(matched) > 1 {
+		ids := make([]string, 0, len(matched))
+		for _, task := range matched {
+			ids = append(ids, task.ID)
+		}
+		return nil, errors.Errorf("task %s is ambiguous, it matches the tasks %s", id, strings.Join(ids, ", "))
+	}
+	return matched, nil
+}
+
+// nodeExecCli returns a Cli that is connected to the engine of a node.
+func nodeExecCli(ctx context.Context, dockerCli command.Cli, nodeID string, options execOptions) (command.Cli, error) {
+	apiClient := dockerCli.Client()
+
+	info, err := apiClient.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.Swarm.NodeID == nodeID {
+		return dockerCli, nil
+	}
+
+	nodeName, err := idresolver.New(apiClient, false).Resolve(ctx, swarm.Node{}, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := nodeEngineHost(dockerCli, options.nodeHosts, nodeID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeClient, err := options.newNodeClient(host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to node %s at %s", nodeName, host)
+	}
+	nodeClient.NegotiateAPIVersion(ctx)
+	return &nodeCli{Cli: dockerCli, client: nodeClient}, nil
+}
+
+// nodeEngineHost returns the endpoint of the engine of a node, looked up by
+// node ID or name, first in the --node-host flags, then in the nodeHosts of
+// the configuration file.
+func nodeEngineHost(dockerCli command.Cli, nodeHosts opts.ListOpts, nodeID, nodeName string) (string, error) {
+	for _, hosts := range []map[string]string{
+		opts.ConvertKVStringsToMap(nodeHosts.GetAll()),
+		dockerCli.ConfigFile().NodeHosts,
+	} {
+		if host, ok := hosts[nodeID]; ok {
+			return host, nil
+		}
+		if host, ok := hosts[nodeName]; ok {
+			return host, nil
+		}
+	}
+	return "", errors.Errorf("no engine endpoint is configured for node %s: use --node-host or set nodeHosts in the configuration file", nodeName)
+}
+
+// nodeCli is a Cli with the client of the engine of another node.
+type nodeCli struct {
+	command.Cli
+	client client.APIClient
+}
+
+func (c *nodeCli) Client() client.APIClient {
+	return c.client
+}
+
+// validateNodeHost validates a --node-host flag.
+func validateNodeHost(val string) (string, error) {
+	parts := strings.SplitN(val, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", errors.Errorf("invalid node host %s: the format is NODE=HOST", val)
+	}
+	return val, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func newExecTask(id string, slot int, nodeID string, state swarm.TaskState) swarm.Task {
+	return swarm.Task{
+		ID:     id,
+		Slot:   slot,
+		NodeID: nodeID,
+		Status: swarm.TaskStatus{
+			State:           state,
+			ContainerStatus: swarm.ContainerStatus{ContainerID: "container-" + id},
+		},
+	}
+}
+
+func TestSelectExecTask(t *testing.T) {
+	apiClient := &fakeClient{
+		taskListFunc: func(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+			assert.Equal(t, []string{"service-id"}, options.Filters.Get("service"))
+			return []swarm.Task{
+				newExecTask("task3", 3, "node1", swarm.TaskStateRunning),
+				newExecTask("task2", 2, "node2", swarm.TaskStateStarting),
+				newExecTask("task1", 1, "node2", swarm.TaskStatePreparing),
+				newExecTask("task4", 4, "node2", swarm.TaskStateRunning),
+			}, nil
+		},
+	}
+	service := newService("service-id", "web")
+
+	testCases := []struct {
+		options     execOptions
+		expectedID  string
+		expectedErr string
+	}{
+		{options: execOptions{}, expectedID: "task3"},
+		{options: execOptions{slot: 4}, expectedID: "task4"},
+		{options: execOptions{task: "task4"}, expectedID: "task4"},
+		{options: execOptions{slot: 2}, expectedErr: "task task2 is not running"},
+		{options: execOptions{service: "web", slot: 5}, expectedErr: "no task in slot 5 of service web"},
+		{options: execOptions{service: "web", task: "foo"}, expectedErr: "no such task in service web: foo"},
+		{options: execOptions{task: "task"}, expectedErr: "task task is ambiguous, it matches the tasks task1, task2, task3, task4"},
+	}
+	for _, tc := range testCases {
+		task, err := selectExecTask(context.Background(), apiClient, service, tc.options)
+		if tc.expectedErr != "" {
+			assert.EqualError(t, err, tc.expectedErr)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedID, task.ID)
+	}
+}
+
+func TestNodeExecCli(t *testing.T) {
+	apiClient := &fakeClient{
+		infoFunc: func(ctx context.Context) (types.Info, error) {
+			return types.Info{Swarm: swarm.Info{NodeID: "manager-id"}}, nil
+		},
+		nodeInspectWithRawFunc: func(ctx context.Context, nodeID string) (swarm.Node, []byte, error) {
+			hostnames := map[string]string{"worker-id": "worker1", "other-id": "worker2"}
+			return swarm.Node{ID: nodeID, Description: swarm.NodeDescription{Hostname: hostnames[nodeID]}}, nil, nil
+		},
+	}
+	cli := test.NewFakeCli(apiClient)
+	cli.ConfigFile().NodeHosts = map[string]string{"worker1": "tcp://10.0.0.2:2376"}
+
+	nodeClient := &fakeClient{}
+	var hosts []string
+	options := execOptions{
+		nodeHosts: opts.NewListOpts(validateNodeHost),
+		newNodeClient: func(host string) (client.APIClient, error) {
+			hosts = append(hosts, host)
+			return nodeClient, nil
+		},
+	}
+
+	nodeCli, err := nodeExecCli(context.Background(), cli, "manager-id", options)
+	require.NoError(t, err)
+	assert.Equal(t, apiClient, nodeCli.Client())
+
+	nodeCli, err = nodeExecCli(context.Background(), cli, "worker-id", options)
+	require.NoError(t, err)
+	assert.Equal(t, nodeClient, nodeCli.Client())
+
+	require.NoError(t, options.nodeHosts.Set("worker-id=tcp://worker1.example.com:2376"))
+	_, err = nodeExecCli(context.Background(), cli, "worker-id", options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tcp://10.0.0.2:2376", "tcp://worker1.example.com:2376"}, hosts)
+
+	_, err = nodeExecCli(context.Background(), cli, "other-id", options)
+	assert.EqualError(t, err, "no engine endpoint is configured for node worker2: use --node-host or set nodeHosts in the configuration file")
+}
+
+func TestValidateNodeHost(t *testing.T) {
+	_, err := validateNodeHost("worker1=tcp://10.0.0.2:2376")
+	assert.NoError(t, err)
+
+	for _, value := range []string{"worker1", "=tcp://10.0.0.2:2376", "worker1="} {
+		_, err := validateNodeHost(value)
+		assert.Error(t, err)
+	}
+}
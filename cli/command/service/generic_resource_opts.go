@@ -0,0 +1,93 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+)
+
+// GenericResource is a concept that a user can use to advertise user-defined
+// resources on a node and thus better place services based on these resources.
+// E.g: NVIDIA GPUs, Intel FPGAs, ...
+// See https://github.com/docker/swarmkit/blob/master/design/generic_resources.md
+
+// ValidateSingleGenericResource validates that a single entry in the
+// generic resource list is valid.
+// i.e 'GPU=2' is valid however 'GPU:2' or 'GPU' isn't
+func ValidateSingleGenericResource(val string) (string, error) {
+	if strings.Count(val, "=") < 1 {
+		return "", errors.Errorf("invalid generic-resource format `%s` expected `name=value`", val)
+	}
+
+	return val, nil
+}
+
+// ParseGenericResources parses an array of Generic resources of the form
+// `kind=value`. Services can only request discrete resources, which is an
+// amount of a resource, e.g. `GPU=2`.
+func ParseGenericResources(value []string) ([]swarm.GenericResource, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	resources := make([]swarm.GenericResource, 0, len(value))
+	for _, res := range value {
+		kind, val, err := splitGenericResource(res)
+		if err != nil {
+			return nil, err
+		}
+		count, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || count <= 0 {
+			return nil, errors.Errorf("invalid generic-resource request `%s`, only positive discrete resources (`kind=count`) can be requested", res)
+		}
+		resources = append(resources, swarm.GenericResource{
+			DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: kind, Value: count},
+		})
+	}
+
+	return resources, nil
+}
+
+func splitGenericResource(res string) (string, string, error) {
+	parts := strings.SplitN(res, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return "", "", errors.Errorf("invalid generic-resource format `%s` expected `name=value`", res)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+func buildGenericResourceMap(genRes []swarm.GenericResource) (map[string]swarm.GenericResource, error) {
+	m := make(map[string]swarm.GenericResource)
+
+	for _, res := range genRes {
+		if res.DiscreteResourceSpec == nil {
+			return nil, errors.Errorf("invalid generic-resource `%+v` for service task", res)
+		}
+
+		_, ok := m[res.DiscreteResourceSpec.Kind]
+		if ok {
+			return nil, errors.Errorf("duplicate generic-resource `%+v` for service task", res.DiscreteResourceSpec.Kind)
+		}
+
+		m[res.DiscreteResourceSpec.Kind] = res
+	}
+
+	return m, nil
+}
+
+func buildGenericResourceList(genRes map[string]swarm.GenericResource) []swarm.GenericResource {
+	l := make([]swarm.GenericResource, 0, len(genRes))
+	for _, res := range genRes {
+		l = append(l, res)
+	}
+	// Sort by kind, so that the requests of a service are stable across
+	// updates.
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].DiscreteResourceSpec.Kind < l[j].DiscreteResourceSpec.Kind
+	})
+
+	return l
+}
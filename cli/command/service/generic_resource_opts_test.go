@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSingleGenericResource(t *testing.T) {
+	incorrect := []string{"foo", "fooBar", "foo-bar"}
+	correct := []string{"foo=bar", "bar=1", "foo=barbar"}
+
+	for _, v := range incorrect {
+		_, err := ValidateSingleGenericResource(v)
+		assert.Error(t, err)
+	}
+
+	for _, v := range correct {
+		_, err := ValidateSingleGenericResource(v)
+		assert.NoError(t, err)
+	}
+}
+
+func TestParseGenericResources(t *testing.T) {
+	resources, err := ParseGenericResources([]string{"GPU=2", "SSD = 1"})
+	require.NoError(t, err)
+	assert.Equal(t, []swarm.GenericResource{
+		{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "GPU", Value: 2}},
+		{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "SSD", Value: 1}},
+	}, resources)
+
+	for _, v := range []string{"GPU=UUID1", "GPU=0", "GPU=-1", "=2", "GPU="} {
+		_, err := ParseGenericResources([]string{v})
+		assert.Error(t, err, v)
+	}
+}
+
+func TestToResourceRequirementsDuplicateGenericResource(t *testing.T) {
+	options := newServiceOptions()
+	require.NoError(t, options.resources.resGenericResources.Set("GPU=1"))
+	require.NoError(t, options.resources.resGenericResources.Set("GPU=2"))
+
+	_, err := options.resources.ToResourceRequirements()
+	testutil.ErrorContains(t, err, "duplicate generic-resource")
+}
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"encoding/json"
 	"io"
 	"io/ioutil"
+	"strings"
+	"sync"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/service/progress"
 	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
@@ -31,3 +35,84 @@ func waitOnService(ctx context.Context, dockerCli command.Cli, serviceID string,
 	}
 	return err
 }
+
+// WaitOnServices waits for several services to converge at once. The progress
+// of each service is prefixed with its name.
+func WaitOnServices(ctx context.Context, dockerCli command.Cli, serviceIDs []string, quiet bool) error {
+	if len(serviceIDs) == 1 {
+		return waitOnService(ctx, dockerCli, serviceIDs[0], quiet)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	var (
+		mu       sync.Mutex
+		encoder  = json.NewEncoder(pipeWriter)
+		copied   sync.WaitGroup
+		waited   sync.WaitGroup
+		errs     = make([]error, len(serviceIDs))
+		writeMsg = func(msg jsonmessage.JSONMessage) {
+			mu.Lock()
+			defer mu.Unlock()
+			encoder.Encode(msg)
+		}
+	)
+	for i, serviceID := range serviceIDs {
+		serviceReader, serviceWriter := io.Pipe()
+		copied.Add(1)
+		waited.Add(1)
+		go func(i int, serviceID string) {
+			defer waited.Done()
+			errs[i] = progress.ServiceProgress(ctx, dockerCli.Client(), serviceID, serviceWriter)
+		}(i, serviceID)
+		go func(serviceID string) {
+			defer copied.Done()
+			prefixProgress(serviceReader, serviceID, writeMsg)
+		}(serviceID)
+	}
+	go func() {
+		copied.Wait()
+		pipeWriter.Close()
+	}()
+
+	var err error
+	if quiet {
+		io.Copy(ioutil.Discard, pipeReader)
+	} else {
+		err = jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil)
+	}
+	if err != nil {
+		return err
+	}
+	waited.Wait()
+
+	var messages []string
+	for i, err := range errs {
+		if err != nil {
+			messages = append(messages, serviceIDs[i]+": "+err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// prefixProgress reads progress messages from r, and writes them with their
+// ID prefixed.
+func prefixProgress(r io.Reader, prefix string, write func(jsonmessage.JSONMessage)) {
+	decoder := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			// Drain the stream, so that the writer is never blocked
+			io.Copy(ioutil.Discard, r)
+			return
+		}
+		if msg.ID == "" {
+			msg.ID = prefix
+		} else {
+			msg.ID = prefix + " " + msg.ID
+		}
+		write(msg)
+	}
+}
@@ -44,6 +44,13 @@ func formatServiceInspect(t *testing.T, format formatter.Format, now time.Time)
 				ContainerSpec: &swarm.ContainerSpec{
 					Image: "foo/bar@sha256:this_is_a_test",
 				},
+				Resources: &swarm.ResourceRequirements{
+					Reservations: &swarm.Resources{
+						GenericResources: []swarm.GenericResource{
+							{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "GPU", Value: 2}},
+						},
+					},
+				},
 				Networks: []swarm.NetworkAttachmentConfig{
 					{
 						Target:  "5vpyomhb6ievnk0i0o60gcnei",
@@ -110,6 +117,9 @@ func TestPrettyPrintWithNoUpdateConfig(t *testing.T) {
 	if !strings.Contains(s, "mynetwork") {
 		t.Fatal("network name not found in inspect output")
 	}
+	if !strings.Contains(s, "  GPU:\t2") {
+		t.Fatal("generic resources not found in inspect output")
+	}
 }
 
 func TestJSONFormatWithNoUpdateConfig(t *testing.T) {
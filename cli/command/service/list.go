@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"io"
 	"sort"
 
 	"vbom.ml/util/sortorder"
@@ -11,6 +12,7 @@ import (
 	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/spf13/cobra"
@@ -21,6 +23,7 @@ type listOptions struct {
 	quiet  bool
 	format string
 	filter opts.FilterOpt
+	watch  bool
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
@@ -40,6 +43,8 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display IDs")
 	flags.StringVar(&options.format, "format", "", "Pretty-print services using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.watch, "watch", false, "Redraw the list whenever services change")
+	flags.SetAnnotation("watch", "version", []string{"1.30"})
 
 	return cmd
 }
@@ -52,6 +57,41 @@ func (n byName) Less(i, j int) bool { return sortorder.NaturalLess(n[i].Spec.Nam
 
 func runList(dockerCli command.Cli, options listOptions) error {
 	ctx := context.Background()
+
+	format := options.format
+	if len(format) == 0 {
+		if len(dockerCli.ConfigFile().ServicesFormat) > 0 && !options.quiet {
+			format = dockerCli.ConfigFile().ServicesFormat
+		} else {
+			format = formatter.TableFormatKey
+		}
+	}
+	servicesFormat := formatter.NewServiceListFormat(format, options.quiet)
+
+	render := func(out io.Writer) error {
+		return writeServiceList(ctx, dockerCli, options, formatter.Context{
+			Output: out,
+			Format: servicesFormat,
+		})
+	}
+
+	if !options.watch {
+		return render(dockerCli.Out())
+	}
+
+	eventFilters := filters.NewArgs()
+	eventFilters.Add("type", events.ServiceEventType)
+	eventC, errC := dockerCli.Client().Events(ctx, types.EventsOptions{Filters: eventFilters})
+
+	watchCtx := formatter.WatchContext{
+		Output: dockerCli.Out(),
+		Format: servicesFormat,
+		Render: render,
+	}
+	return watchCtx.Watch(ctx, eventC, errC)
+}
+
+func writeServiceList(ctx context.Context, dockerCli command.Cli, options listOptions, servicesCtx formatter.Context) error {
 	client := dockerCli.Client()
 
 	serviceFilters := options.filter.Value()
@@ -82,19 +122,6 @@ func runList(dockerCli command.Cli, options listOptions) error {
 		info = GetServicesStatus(services, nodes, tasks)
 	}
 
-	format := options.format
-	if len(format) == 0 {
-		if len(dockerCli.ConfigFile().ServicesFormat) > 0 && !options.quiet {
-			format = dockerCli.ConfigFile().ServicesFormat
-		} else {
-			format = formatter.TableFormatKey
-		}
-	}
-
-	servicesCtx := formatter.Context{
-		Output: dockerCli.Out(),
-		Format: formatter.NewServiceListFormat(format, options.quiet),
-	}
 	return formatter.ServiceListWrite(servicesCtx, services, info)
 }
 
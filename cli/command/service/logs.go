@@ -2,11 +2,15 @@ package service
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"golang.org/x/net/context"
 
@@ -14,6 +18,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/idresolver"
 	"github.com/docker/cli/service/logs"
+	"github.com/docker/cli/templates"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
@@ -33,11 +38,23 @@ type logsOptions struct {
 	tail       string
 	details    bool
 	raw        bool
+	node       string
+	taskSlot   int
+	grep       string
+	invert     bool
+	levels     []string
+	format     string
 
 	target string
 }
 
-func newLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
+// withTimestamps returns whether the logs are fetched with their timestamps,
+// which are needed to show them or to format them.
+func (opts *logsOptions) withTimestamps() bool {
+	return opts.timestamps || opts.format != ""
+}
+
+func newLogsCommand(dockerCli command.Cli) *cobra.Command {
 	var opts logsOptions
 
 	cmd := &cobra.Command{
@@ -58,6 +75,12 @@ func newLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
 	flags.BoolVar(&opts.raw, "raw", false, "Do not neatly format logs")
 	flags.SetAnnotation("raw", "version", []string{"1.30"})
 	flags.BoolVar(&opts.noTaskIDs, "no-task-ids", false, "Do not include task IDs in output")
+	flags.StringVar(&opts.node, "node", "", "Only show logs of tasks on a node")
+	flags.IntVar(&opts.taskSlot, "task-slot", 0, "Only show logs of the tasks of a slot")
+	flags.StringVar(&opts.grep, "grep", "", "Only show log lines that match a regular expression")
+	flags.BoolVarP(&opts.invert, "invert-match", "v", false, "Only show log lines that don't match --grep")
+	flags.StringSliceVar(&opts.levels, "level", []string{}, "Only show JSON log lines with a level")
+	flags.StringVar(&opts.format, "format", "", "Format log lines using a Go template")
 	// options identical to container logs
 	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
 	flags.StringVar(&opts.since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
@@ -68,14 +91,21 @@ func newLogsCommand(dockerCli *command.DockerCli) *cobra.Command {
 	return cmd
 }
 
-func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
+func runLogs(dockerCli command.Cli, opts *logsOptions) error {
 	ctx := context.Background()
 
+	if opts.invert && opts.grep == "" {
+		return errors.New("--invert-match can only be used with --grep")
+	}
+	if opts.raw && (opts.hasFilters() || opts.format != "") {
+		return errors.New("--raw can't be combined with --format or with filters")
+	}
+
 	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Since:      opts.since,
-		Timestamps: opts.timestamps,
+		Timestamps: opts.withTimestamps(),
 		Follow:     opts.follow,
 		Tail:       opts.tail,
 		// get the details if we request it OR if we're not doing raw mode
@@ -85,6 +115,15 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 
 	cli := dockerCli.Client()
 
+	filter, err := newLogFilter(ctx, cli, opts)
+	if err != nil {
+		return err
+	}
+	tmpl, err := makeLogTemplate(opts.format)
+	if err != nil {
+		return err
+	}
+
 	var (
 		maxLength    = 1
 		responseBody io.ReadCloser
@@ -133,6 +172,9 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 	if tty && !opts.raw {
 		return errors.New("tty service logs only supported with --raw")
 	}
+	if tty && (opts.hasFilters() || opts.format != "") {
+		return errors.New("filtering and formatting logs is not supported for tty services")
+	}
 
 	// now get the logs
 	responseBody, err = logfunc(ctx, opts.target, options)
@@ -155,8 +197,8 @@ func runLogs(dockerCli *command.DockerCli, opts *logsOptions) error {
 	if !opts.raw {
 		taskFormatter := newTaskFormatter(cli, opts, maxLength)
 
-		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout}
-		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr}
+		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout, stream: "stdout", filter: filter, tmpl: tmpl}
+		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr, stream: "stderr", filter: filter, tmpl: tmpl}
 	}
 
 	_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
@@ -174,9 +216,20 @@ type taskFormatter struct {
 	padding int
 
 	r *idresolver.IDResolver
-	// cache saves a pre-cooked logContext formatted string based on a
-	// logcontext object, so we don't have to resolve names every time
-	cache map[logContext]string
+	// cache saves a pre-cooked resolved context based on a logcontext
+	// object, so we don't have to resolve names every time
+	cache map[logContext]*taskContext
+}
+
+// taskContext holds the resolved names of the task a log line comes from.
+type taskContext struct {
+	service string
+	node    string
+	taskID  string
+	slot    int
+	// formatted is the name of the task, padded, as it is printed in front
+	// of log lines
+	formatted string
 }
 
 func newTaskFormatter(client client.APIClient, opts *logsOptions, padding int) *taskFormatter {
@@ -185,37 +238,37 @@ func newTaskFormatter(client client.APIClient, opts *logsOptions, padding int) *
 		opts:    opts,
 		padding: padding,
 		r:       idresolver.New(client, opts.noResolve),
-		cache:   make(map[logContext]string),
+		cache:   make(map[logContext]*taskContext),
 	}
 }
 
-func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string, error) {
+func (f *taskFormatter) resolve(ctx context.Context, logCtx logContext) (*taskContext, error) {
 	if cached, ok := f.cache[logCtx]; ok {
 		return cached, nil
 	}
 
 	nodeName, err := f.r.Resolve(ctx, swarm.Node{}, logCtx.nodeID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	serviceName, err := f.r.Resolve(ctx, swarm.Service{}, logCtx.serviceID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	task, _, err := f.client.TaskInspectWithRaw(ctx, logCtx.taskID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	taskID := task.ID
+	if !f.opts.noTrunc {
+		taskID = stringid.TruncateID(task.ID)
+	}
 	taskName := fmt.Sprintf("%s.%d", serviceName, task.Slot)
 	if !f.opts.noTaskIDs {
-		if f.opts.noTrunc {
-			taskName += fmt.Sprintf(".%s", task.ID)
-		} else {
-			taskName += fmt.Sprintf(".%s", stringid.TruncateID(task.ID))
-		}
+		taskName += "." + taskID
 	}
 
 	paddingCount := f.padding - getMaxLength(task.Slot)
@@ -223,16 +276,25 @@ func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string,
 	if paddingCount > 0 {
 		padding = strings.Repeat(" ", paddingCount)
 	}
-	formatted := taskName + "@" + nodeName + padding
-	f.cache[logCtx] = formatted
-	return formatted, nil
+	resolved := &taskContext{
+		service:   serviceName,
+		node:      nodeName,
+		taskID:    taskID,
+		slot:      task.Slot,
+		formatted: taskName + "@" + nodeName + padding,
+	}
+	f.cache[logCtx] = resolved
+	return resolved, nil
 }
 
 type logWriter struct {
-	ctx  context.Context
-	opts *logsOptions
-	f    *taskFormatter
-	w    io.Writer
+	ctx    context.Context
+	opts   *logsOptions
+	f      *taskFormatter
+	w      io.Writer
+	stream string
+	filter *logFilter
+	tmpl   *template.Template
 }
 
 func (lw *logWriter) Write(buf []byte) (int, error) {
@@ -247,7 +309,7 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 	// spaces. if there is a timestamp, details will be 2nd (`index 1)
 	detailsIndex := 0
 	numParts := 2
-	if lw.opts.timestamps {
+	if lw.opts.withTimestamps() {
 		detailsIndex++
 		numParts++
 	}
@@ -270,6 +332,38 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 		return 0, err
 	}
 
+	taskCtx, err := lw.f.resolve(lw.ctx, logCtx)
+	if err != nil {
+		return 0, err
+	}
+	message := parts[detailsIndex+1]
+	line := bytes.TrimSuffix(message, []byte("\n"))
+	if !lw.filter.match(logCtx, taskCtx, line) {
+		return len(buf), nil
+	}
+
+	if lw.tmpl != nil {
+		entry := logEntry{
+			Timestamp: string(parts[0]),
+			Service:   taskCtx.service,
+			Task:      taskCtx.taskID,
+			Slot:      taskCtx.slot,
+			Node:      taskCtx.node,
+			Stream:    lw.stream,
+			Message:   string(line),
+			Details:   details,
+		}
+		var output bytes.Buffer
+		if err := lw.tmpl.Execute(&output, entry); err != nil {
+			return 0, err
+		}
+		output.WriteByte('\n')
+		if _, err := lw.w.Write(output.Bytes()); err != nil {
+			return 0, err
+		}
+		return len(buf), nil
+	}
+
 	output := []byte{}
 	// if we included timestamps, add them to the front
 	if lw.opts.timestamps {
@@ -277,11 +371,7 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 		output = append(output, ' ')
 	}
 	// add the context, nice and formatted
-	formatted, err := lw.f.format(lw.ctx, logCtx)
-	if err != nil {
-		return 0, err
-	}
-	output = append(output, []byte(formatted+"    | ")...)
+	output = append(output, []byte(taskCtx.formatted+"    | ")...)
 	// if the user asked for details, add them to be log message
 	if lw.opts.details {
 		// ugh i hate this it's basically a dupe of api/server/httputils/write_log_stream.go:stringAttrs()
@@ -306,7 +396,7 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 	}
 
 	// add the log message itself, finally
-	output = append(output, parts[detailsIndex+1]...)
+	output = append(output, message...)
 
 	_, err = lw.w.Write(output)
 	if err != nil {
@@ -348,3 +438,117 @@ type logContext struct {
 	serviceID string
 	taskID    string
 }
+
+// logEntry is the context of a log line that is available to --format
+// templates.
+type logEntry struct {
+	Timestamp string
+	Service   string
+	Task      string
+	Slot      int
+	Node      string
+	Stream    string
+	Message   string
+	Details   map[string]string
+}
+
+func makeLogTemplate(format string) (*template.Template, error) {
+	if format == "" {
+		return nil, nil
+	}
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid --format template")
+	}
+	// execute the template for an empty entry, so that a bad template like
+	// "{{.badField}}" is reported before any log line is fetched
+	if err := tmpl.Execute(ioutil.Discard, logEntry{}); err != nil {
+		return nil, errors.Wrap(err, "invalid --format template")
+	}
+	return tmpl, nil
+}
+
+// hasFilters returns whether log lines are filtered.
+func (opts *logsOptions) hasFilters() bool {
+	return opts.node != "" || opts.taskSlot != 0 || opts.grep != "" || len(opts.levels) > 0
+}
+
+// levelKeys are the fields that hold the level of JSON log lines, as they are
+// named by common logging libraries.
+var levelKeys = []string{"level", "lvl", "severity"}
+
+// logFilter selects the log lines that are shown. Filters are applied to
+// each line as the logs are demultiplexed.
+type logFilter struct {
+	nodeID string
+	slot   int
+	grep   *regexp.Regexp
+	invert bool
+	levels []string
+}
+
+func newLogFilter(ctx context.Context, apiClient client.APIClient, opts *logsOptions) (*logFilter, error) {
+	filter := &logFilter{
+		slot:   opts.taskSlot,
+		invert: opts.invert,
+		levels: opts.levels,
+	}
+	if opts.node != "" {
+		node, _, err := apiClient.NodeInspectWithRaw(ctx, opts.node)
+		if err != nil {
+			return nil, err
+		}
+		filter.nodeID = node.ID
+	}
+	if opts.grep != "" {
+		grep, err := regexp.Compile(opts.grep)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid --grep expression")
+		}
+		filter.grep = grep
+	}
+	return filter, nil
+}
+
+// match returns whether a log line is shown.
+func (f *logFilter) match(logCtx logContext, taskCtx *taskContext, message []byte) bool {
+	if f.nodeID != "" && logCtx.nodeID != f.nodeID {
+		return false
+	}
+	if f.slot != 0 && taskCtx.slot != f.slot {
+		return false
+	}
+	if f.grep != nil && f.grep.Match(message) == f.invert {
+		return false
+	}
+	if len(f.levels) > 0 && !f.matchLevel(message) {
+		return false
+	}
+	return true
+}
+
+// matchLevel returns whether a log line is a JSON object with one of the
+// levels of the filter. Lines that aren't JSON objects never match.
+func (f *logFilter) matchLevel(message []byte) bool {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(message, &fields); err != nil {
+		return false
+	}
+	for key, value := range fields {
+		for _, levelKey := range levelKeys {
+			if !strings.EqualFold(key, levelKey) {
+				continue
+			}
+			level, ok := value.(string)
+			if !ok {
+				continue
+			}
+			for _, l := range f.levels {
+				if strings.EqualFold(level, l) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func newTestLogWriter(t *testing.T, opts *logsOptions, out *bytes.Buffer) *logWriter {
+	ctx := context.Background()
+	apiClient := &fakeClient{
+		taskInspectWithRawFunc: func(ctx context.Context, taskID string) (swarm.Task, []byte, error) {
+			slots := map[string]int{"task1": 1, "task2": 2}
+			return swarm.Task{ID: taskID, Slot: slots[taskID]}, nil, nil
+		},
+	}
+	filter, err := newLogFilter(ctx, apiClient, opts)
+	require.NoError(t, err)
+	tmpl, err := makeLogTemplate(opts.format)
+	require.NoError(t, err)
+	return &logWriter{
+		ctx:    ctx,
+		opts:   opts,
+		f:      newTaskFormatter(apiClient, opts, 1),
+		w:      out,
+		stream: "stdout",
+		filter: filter,
+		tmpl:   tmpl,
+	}
+}
+
+func writeLogLines(t *testing.T, lw *logWriter, lines ...string) {
+	for _, line := range lines {
+		_, err := lw.Write([]byte(line))
+		require.NoError(t, err)
+	}
+}
+
+var testLogLines = []string{
+	"com.docker.swarm.node.id=node1,com.docker.swarm.service.id=web,com.docker.swarm.task.id=task1 starting\n",
+	"com.docker.swarm.node.id=node2,com.docker.swarm.service.id=web,com.docker.swarm.task.id=task2 {\"level\":\"error\",\"msg\":\"failed\"}\n",
+	"com.docker.swarm.node.id=node1,com.docker.swarm.service.id=web,com.docker.swarm.task.id=task1 {\"lvl\":\"info\",\"msg\":\"started\"}\n",
+}
+
+func TestLogWriterFilters(t *testing.T) {
+	testCases := []struct {
+		opts     logsOptions
+		expected string
+	}{
+		{
+			opts: logsOptions{noResolve: true, noTaskIDs: true},
+			expected: "web.1@node1    | starting\n" +
+				"web.2@node2    | {\"level\":\"error\",\"msg\":\"failed\"}\n" +
+				"web.1@node1    | {\"lvl\":\"info\",\"msg\":\"started\"}\n",
+		},
+		{
+			opts:     logsOptions{noResolve: true, noTaskIDs: true, taskSlot: 2},
+			expected: "web.2@node2    | {\"level\":\"error\",\"msg\":\"failed\"}\n",
+		},
+		{
+			opts:     logsOptions{noResolve: true, noTaskIDs: true, grep: "^start"},
+			expected: "web.1@node1    | starting\n",
+		},
+		{
+			opts: logsOptions{noResolve: true, noTaskIDs: true, grep: "^start", invert: true},
+			expected: "web.2@node2    | {\"level\":\"error\",\"msg\":\"failed\"}\n" +
+				"web.1@node1    | {\"lvl\":\"info\",\"msg\":\"started\"}\n",
+		},
+		{
+			opts:     logsOptions{noResolve: true, noTaskIDs: true, levels: []string{"INFO"}},
+			expected: "web.1@node1    | {\"lvl\":\"info\",\"msg\":\"started\"}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		out := new(bytes.Buffer)
+		opts := tc.opts
+		writeLogLines(t, newTestLogWriter(t, &opts, out), testLogLines...)
+		assert.Equal(t, tc.expected, out.String())
+	}
+}
+
+func TestLogWriterFormat(t *testing.T) {
+	out := new(bytes.Buffer)
+	opts := &logsOptions{noResolve: true, format: "{{.Timestamp}} {{.Service}} {{.Slot}} {{.Task}} {{.Node}} {{.Stream}}: {{.Message}}"}
+	writeLogLines(t, newTestLogWriter(t, opts, out),
+		"2017-10-18T10:00:00.000000000Z com.docker.swarm.node.id=node1,com.docker.swarm.service.id=web,com.docker.swarm.task.id=task1 starting\n",
+	)
+	assert.Equal(t, "2017-10-18T10:00:00.000000000Z web 1 task1 node1 stdout: starting\n", out.String())
+}
+
+func TestMakeLogTemplateInvalid(t *testing.T) {
+	_, err := makeLogTemplate("{{.Unknown}}")
+	assert.Error(t, err)
+}
+
+func TestNewLogFilterInvalidGrep(t *testing.T) {
+	_, err := newLogFilter(context.Background(), &fakeClient{}, &logsOptions{grep: "("})
+	assert.Error(t, err)
+}
+
+func TestRunLogsInvalidOptions(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+
+	err := runLogs(cli, &logsOptions{target: "web", invert: true})
+	assert.EqualError(t, err, "--invert-match can only be used with --grep")
+
+	err = runLogs(cli, &logsOptions{target: "web", raw: true, grep: "error"})
+	assert.EqualError(t, err, "--raw can't be combined with --format or with filters")
+}
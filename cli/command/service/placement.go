@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// CheckPlacement evaluates the placement constraints and preferences of a
+// service against the nodes of the swarm, as the tasks of a service that no
+// active node can run are pending until a node matches. It prints a warning
+// when no active node satisfies them, or returns an error with strict.
+func CheckPlacement(ctx context.Context, dockerCli command.Cli, spec swarm.ServiceSpec, strict bool) error {
+	placement := spec.TaskTemplate.Placement
+	if placement == nil || (len(placement.Constraints) == 0 && len(placement.Preferences) == 0) {
+		return nil
+	}
+
+	nodes, err := dockerCli.Client().NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	problem := placementProblem(spec.Name, placement, nodes)
+	if problem == "" {
+		return nil
+	}
+	if strict {
+		return errors.New(problem)
+	}
+	fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", problem)
+	return nil
+}
+
+// placementProblem returns why no node can run the tasks of a service: the
+// reason each node is eliminated if no active node satisfies the placement
+// constraints, or the spread preferences over labels that no eligible node
+// has. It returns an empty string if some nodes can run the tasks.
+func placementProblem(service string, placement *swarm.Placement, nodes []swarm.Node) string {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Description.Hostname < nodes[j].Description.Hostname })
+
+	var (
+		eligible   []swarm.Node
+		eliminated []string
+	)
+	for _, node := range nodes {
+		if reason := nodeEliminated(node, placement.Constraints); reason != "" {
+			eliminated = append(eliminated, fmt.Sprintf("  %s: %s", node.Description.Hostname, reason))
+			continue
+		}
+		eligible = append(eligible, node)
+	}
+	if len(eligible) == 0 {
+		return fmt.Sprintf("no active node satisfies the placement constraints of service %s, its tasks will be pending:\n%s", service, strings.Join(eliminated, "\n"))
+	}
+
+	var missing []string
+	for _, preference := range placement.Preferences {
+		if preference.Spread == nil {
+			continue
+		}
+		descriptor := preference.Spread.SpreadDescriptor
+		found := false
+		for _, node := range eligible {
+			if _, ok := nodeAttribute(node, descriptor); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, descriptor)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("no node that satisfies the placement constraints of service %s has the labels of its spread preferences, its tasks won't be spread: %s", service, strings.Join(missing, ", "))
+	}
+	return ""
+}
+
+// nodeEliminated returns why the tasks of a service can't run on a node: the
+// node isn't active, or the first constraint the node doesn't satisfy.
+func nodeEliminated(node swarm.Node, constraints []string) string {
+	if node.Status.State != swarm.NodeStateReady {
+		return fmt.Sprintf("node is %s", node.Status.State)
+	}
+	if node.Spec.Availability != swarm.NodeAvailabilityActive {
+		return fmt.Sprintf("node is %s", availabilityState(node.Spec.Availability))
+	}
+
+	for _, constraint := range constraints {
+		key, operator, expected, ok := parseConstraint(constraint)
+		if !ok {
+			continue
+		}
+		value, found := nodeAttribute(node, key)
+		if !found && !strings.HasPrefix(key, "node.labels.") && !strings.HasPrefix(key, "engine.labels.") {
+			// the constraint is on an attribute the client doesn't know
+			continue
+		}
+		equal := found && strings.EqualFold(value, expected)
+		if equal == (operator == "==") {
+			continue
+		}
+		if !found {
+			return fmt.Sprintf("does not satisfy %s (%s is not set)", constraint, key)
+		}
+		return fmt.Sprintf("does not satisfy %s (%s is %s)", constraint, key, value)
+	}
+	return ""
+}
+
+func availabilityState(availability swarm.NodeAvailability) string {
+	switch availability {
+	case swarm.NodeAvailabilityDrain:
+		return "drained"
+	case swarm.NodeAvailabilityPause:
+		return "paused"
+	default:
+		return string(availability)
+	}
+}
+
+// parseConstraint splits a placement constraint such as node.role==manager in
+// its key, operator and value.
+func parseConstraint(constraint string) (string, string, string, bool) {
+	for _, operator := range []string{"==", "!="} {
+		parts := strings.SplitN(constraint, operator, 2)
+		if len(parts) == 2 {
+			return strings.TrimSpace(parts[0]), operator, strings.TrimSpace(parts[1]), true
+		}
+	}
+	return "", "", "", false
+}
+
+// nodeAttribute returns the value of a node attribute that placement
+// constraints and preferences use, and whether the node has it.
+func nodeAttribute(node swarm.Node, key string) (string, bool) {
+	switch {
+	case key == "node.id":
+		return node.ID, true
+	case key == "node.hostname":
+		return node.Description.Hostname, true
+	case key == "node.role":
+		return string(node.Spec.Role), true
+	case key == "node.platform.os":
+		return node.Description.Platform.OS, true
+	case key == "node.platform.arch":
+		return node.Description.Platform.Architecture, true
+	case strings.HasPrefix(key, "node.labels."):
+		value, ok := node.Spec.Labels[strings.TrimPrefix(key, "node.labels.")]
+		return value, ok
+	case strings.HasPrefix(key, "engine.labels."):
+		value, ok := node.Description.Engine.Labels[strings.TrimPrefix(key, "engine.labels.")]
+		return value, ok
+	}
+	return "", false
+}
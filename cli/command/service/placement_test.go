@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+)
+
+func placementNodes() []swarm.Node {
+	drained := *Node(NodeID("node-3"), Hostname("node-3"), NodeLabels(map[string]string{"zone": "eu"}))
+	drained.Spec.Availability = swarm.NodeAvailabilityDrain
+	down := *Node(NodeID("node-4"), Hostname("node-4"), NodeLabels(map[string]string{"zone": "eu"}))
+	down.Status.State = swarm.NodeStateDown
+	return []swarm.Node{
+		*Node(NodeID("node-2"), Hostname("node-2")),
+		*Node(NodeID("node-1"), Hostname("node-1"), NodeLabels(map[string]string{"zone": "us-east"})),
+		drained,
+		down,
+	}
+}
+
+func TestPlacementProblem(t *testing.T) {
+	testCases := []struct {
+		name      string
+		placement swarm.Placement
+		expected  string
+	}{
+		{
+			name:      "satisfied",
+			placement: swarm.Placement{Constraints: []string{"node.labels.zone == us-east"}},
+		},
+		{
+			name:      "satisfied-not-equal",
+			placement: swarm.Placement{Constraints: []string{"node.labels.zone!=eu"}},
+		},
+		{
+			name:      "case-insensitive",
+			placement: swarm.Placement{Constraints: []string{"node.hostname==NODE-2"}},
+		},
+		{
+			name:      "unknown-attribute",
+			placement: swarm.Placement{Constraints: []string{"node.unknown==foo"}},
+		},
+		{
+			name:      "unsatisfied",
+			placement: swarm.Placement{Constraints: []string{"node.role==worker", "node.labels.zone==eu"}},
+			expected: `no active node satisfies the placement constraints of service web, its tasks will be pending:
+  node-1: does not satisfy node.labels.zone==eu (node.labels.zone is us-east)
+  node-2: does not satisfy node.labels.zone==eu (node.labels.zone is not set)
+  node-3: node is drained
+  node-4: node is down`,
+		},
+		{
+			name:      "unsatisfied-role",
+			placement: swarm.Placement{Constraints: []string{"node.role==manager"}},
+			expected: `no active node satisfies the placement constraints of service web, its tasks will be pending:
+  node-1: does not satisfy node.role==manager (node.role is worker)
+  node-2: does not satisfy node.role==manager (node.role is worker)
+  node-3: node is drained
+  node-4: node is down`,
+		},
+		{
+			name: "spread-over-missing-label",
+			placement: swarm.Placement{
+				Constraints: []string{"node.hostname==node-2"},
+				Preferences: []swarm.PlacementPreference{{Spread: &swarm.SpreadOver{SpreadDescriptor: "node.labels.zone"}}},
+			},
+			expected: "no node that satisfies the placement constraints of service web has the labels of its spread preferences, its tasks won't be spread: node.labels.zone",
+		},
+		{
+			name: "spread-over-label",
+			placement: swarm.Placement{
+				Preferences: []swarm.PlacementPreference{{Spread: &swarm.SpreadOver{SpreadDescriptor: "node.labels.zone"}}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, placementProblem("web", &tc.placement, placementNodes()))
+		})
+	}
+}
+
+func TestCheckPlacement(t *testing.T) {
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			Placement: &swarm.Placement{Constraints: []string{"node.hostname==node-9"}},
+		},
+	}
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+			return []swarm.Node{*Node(NodeID("node-1"), Hostname("node-1"))}, nil
+		},
+	})
+
+	assert.NoError(t, CheckPlacement(context.Background(), cli, spec, false))
+	assert.Equal(t, `WARNING: no active node satisfies the placement constraints of service web, its tasks will be pending:
+  node-1: does not satisfy node.hostname==node-9 (node.hostname is node-1)
+`, cli.ErrBuffer().String())
+
+	err := CheckPlacement(context.Background(), cli, spec, true)
+	assert.EqualError(t, err, `no active node satisfies the placement constraints of service web, its tasks will be pending:
+  node-1: does not satisfy node.hostname==node-9 (node.hostname is node-1)`)
+}
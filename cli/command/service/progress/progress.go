@@ -204,6 +204,173 @@ func ServiceProgress(ctx context.Context, client client.APIClient, serviceID str
 	}
 }
 
+// ErrCanaryInterrupted is returned by CanaryProgress when it is interrupted
+// before the canary tasks are stable.
+var ErrCanaryInterrupted = errors.New("interrupted before the canary tasks are stable")
+
+// CanaryProgress outputs progress information for the canary tasks of an
+// update of a replicated service, which are the first tasks that are updated
+// to the new spec. The version of the task in each slot is shown. It returns
+// once the canary tasks are running and healthy, and have been stable for the
+// monitoring period of the update, or an error if the update is paused or
+// rolled back. It returns ErrCanaryInterrupted if it is interrupted before.
+// nolint: gocyclo
+func CanaryProgress(ctx context.Context, client client.APIClient, serviceID string, canaries uint64, progressWriter io.WriteCloser) error {
+	defer progressWriter.Close()
+
+	progressOut := streamformatter.NewJSONProgressOutput(progressWriter, false)
+
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt)
+	defer signal.Stop(sigint)
+
+	taskFilter := filters.NewArgs()
+	taskFilter.Add("service", serviceID)
+	upToDateFilter := filters.NewArgs()
+	upToDateFilter.Add("service", serviceID)
+	upToDateFilter.Add("_up-to-date", "true")
+
+	var (
+		updater     = &replicatedProgressUpdater{progressOut: progressOut}
+		converged   bool
+		convergedAt time.Time
+		monitor     = 5 * time.Second
+	)
+
+	for {
+		service, _, err := client.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+		if err != nil {
+			return err
+		}
+		if service.Spec.Mode.Replicated == nil || service.Spec.Mode.Replicated.Replicas == nil {
+			return errors.New("canary updates are only supported for replicated services")
+		}
+		if service.Spec.UpdateConfig != nil && service.Spec.UpdateConfig.Monitor != 0 {
+			monitor = service.Spec.UpdateConfig.Monitor
+		}
+
+		if service.UpdateStatus != nil {
+			switch service.UpdateStatus.State {
+			case swarm.UpdateStateCompleted:
+				// All tasks were updated, there are no more tasks
+				// than canaries.
+				return nil
+			case swarm.UpdateStatePaused:
+				return fmt.Errorf("service update paused: %s", service.UpdateStatus.Message)
+			case swarm.UpdateStateRollbackStarted, swarm.UpdateStateRollbackPaused, swarm.UpdateStateRollbackCompleted:
+				return fmt.Errorf("canary tasks failed, service rolled back: %s", service.UpdateStatus.Message)
+			}
+		}
+		if converged && time.Since(convergedAt) >= monitor {
+			progressOut.WriteProgress(progress.Progress{
+				ID:     "verify",
+				Action: "Canary tasks are stable",
+			})
+
+			return nil
+		}
+
+		tasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+		if err != nil {
+			return err
+		}
+		upToDateTasks, err := client.TaskList(ctx, types.TaskListOptions{Filters: upToDateFilter})
+		if err != nil {
+			return err
+		}
+		updater.upToDate = make(map[string]struct{}, len(upToDateTasks))
+		for _, task := range upToDateTasks {
+			updater.upToDate[task.ID] = struct{}{}
+		}
+
+		activeNodes, err := getActiveNodes(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		if _, err := updater.update(service, tasks, activeNodes, false); err != nil {
+			return err
+		}
+		healthy, err := canariesHealthy(ctx, client, updater, tasks, activeNodes)
+		if err != nil {
+			return err
+		}
+		converged = healthy >= minUint64(canaries, *service.Spec.Mode.Replicated.Replicas)
+		if converged {
+			if convergedAt.IsZero() {
+				convergedAt = time.Now()
+			}
+			wait := monitor - time.Since(convergedAt)
+			if wait >= 0 {
+				progressOut.WriteProgress(progress.Progress{
+					ID:     "verify",
+					Action: fmt.Sprintf("Waiting %d seconds to verify that canary tasks are stable...", wait/time.Second+1),
+				})
+			}
+		} else {
+			if !convergedAt.IsZero() {
+				progressOut.WriteProgress(progress.Progress{
+					ID:     "verify",
+					Action: "Detected task failure",
+				})
+			}
+			convergedAt = time.Time{}
+		}
+
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-sigint:
+			if !converged {
+				progress.Message(progressOut, "", "Operation continuing in background.")
+				progress.Messagef(progressOut, "", "Use `docker service ps %s` to check progress.", serviceID)
+			}
+			return ErrCanaryInterrupted
+		}
+	}
+}
+
+// containerInspector inspects the containers of the tasks on the node the
+// client is connected to.
+type containerInspector interface {
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+}
+
+// canariesHealthy returns the number of slots in which an up-to-date task is
+// running and healthy. The engine only reports that a task is running once the
+// health check of its container passes, and fails the task if the container
+// becomes unhealthy. The health of the containers on the node the client is
+// connected to is also checked directly, since the state of a task is only
+// reported periodically. Containers on the other nodes can't be inspected, so
+// only the state of their task is checked.
+func canariesHealthy(ctx context.Context, inspector containerInspector, u *replicatedProgressUpdater, tasks []swarm.Task, activeNodes map[string]struct{}) (uint64, error) {
+	healthy := uint64(0)
+	for _, task := range u.tasksBySlot(tasks, activeNodes) {
+		if terminalState(task.DesiredState) || task.Status.State != swarm.TaskStateRunning || !u.isUpToDate(task) {
+			continue
+		}
+		if containerID := task.Status.ContainerStatus.ContainerID; containerID != "" {
+			container, err := inspector.ContainerInspect(ctx, containerID)
+			switch {
+			case client.IsErrNotFound(err):
+				// the container runs on another node
+			case err != nil:
+				return 0, err
+			case container.State != nil && container.State.Health != nil && container.State.Health.Status != types.Healthy:
+				continue
+			}
+		}
+		healthy++
+	}
+	return healthy, nil
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func getActiveNodes(ctx context.Context, client client.APIClient) (map[string]struct{}, error) {
 	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
 	if err != nil {
@@ -266,6 +433,12 @@ type replicatedProgressUpdater struct {
 	// this also causes progress bars to appear in order
 	slotMap map[int]int
 
+	// upToDate holds the IDs of the tasks that run the current spec of the
+	// service. If it is set, the tasks of the previous spec are tracked as
+	// well, only up-to-date tasks are counted as converged, and the version
+	// of the task in each slot is shown.
+	upToDate map[string]struct{}
+
 	initialized bool
 	done        bool
 }
@@ -311,7 +484,7 @@ func (u *replicatedProgressUpdater) update(service swarm.Service, tasks []swarm.
 			u.slotMap[task.Slot] = mappedSlot
 		}
 
-		if !terminalState(task.DesiredState) && task.Status.State == swarm.TaskStateRunning {
+		if !terminalState(task.DesiredState) && task.Status.State == swarm.TaskStateRunning && u.isUpToDate(task) {
 			running++
 		}
 
@@ -329,6 +502,14 @@ func (u *replicatedProgressUpdater) update(service swarm.Service, tasks []swarm.
 	return running == replicas, nil
 }
 
+func (u *replicatedProgressUpdater) isUpToDate(task swarm.Task) bool {
+	if u.upToDate == nil {
+		return true
+	}
+	_, ok := u.upToDate[task.ID]
+	return ok
+}
+
 func (u *replicatedProgressUpdater) tasksBySlot(tasks []swarm.Task, activeNodes map[string]struct{}) map[int]swarm.Task {
 	// If there are multiple tasks with the same slot number, favor the one
 	// with the *lowest* desired state. This can happen in restart
@@ -375,9 +556,17 @@ func (u *replicatedProgressUpdater) writeTaskProgress(task swarm.Task, mappedSlo
 	}
 
 	if !terminalState(task.DesiredState) && !terminalState(task.Status.State) {
+		action := fmt.Sprintf("%-[1]*s", longestState, task.Status.State)
+		if u.upToDate != nil {
+			if u.isUpToDate(task) {
+				action += " new"
+			} else {
+				action += " old"
+			}
+		}
 		u.progressOut.WriteProgress(progress.Progress{
 			ID:         fmt.Sprintf("%d/%d", mappedSlot, replicas),
-			Action:     fmt.Sprintf("%-[1]*s", longestState, task.Status.State),
+			Action:     action,
 			Current:    stateToProgress(task.Status.State, rollback),
 			Total:      maxProgress,
 			HideCounts: true,
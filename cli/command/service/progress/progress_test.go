@@ -2,12 +2,15 @@ package progress
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
 )
 
 type mockProgress struct {
@@ -210,6 +213,102 @@ func TestReplicatedProgressUpdaterManyReplicas(t *testing.T) {
 	}
 }
 
+func TestReplicatedProgressUpdaterVersions(t *testing.T) {
+	replicas := uint64(2)
+
+	service := swarm.Service{
+		Spec: swarm.ServiceSpec{
+			Mode: swarm.ServiceMode{
+				Replicated: &swarm.ReplicatedService{
+					Replicas: &replicas,
+				},
+			},
+		},
+	}
+
+	p := &mockProgress{}
+	updater := &replicatedProgressUpdater{
+		progressOut: p,
+		upToDate:    map[string]struct{}{"new-1": {}},
+	}
+	activeNodes := map[string]struct{}{"a": {}}
+
+	// Slot 1 runs a task of the new spec, slot 2 still runs the old one
+	tasks := []swarm.Task{
+		{
+			ID:           "old-1",
+			Slot:         1,
+			NodeID:       "a",
+			DesiredState: swarm.TaskStateShutdown,
+			Status:       swarm.TaskStatus{State: swarm.TaskStateShutdown},
+		},
+		{
+			ID:           "new-1",
+			Slot:         1,
+			NodeID:       "a",
+			DesiredState: swarm.TaskStateRunning,
+			Status:       swarm.TaskStatus{State: swarm.TaskStateRunning},
+		},
+		{
+			ID:           "old-2",
+			Slot:         2,
+			NodeID:       "a",
+			DesiredState: swarm.TaskStateRunning,
+			Status:       swarm.TaskStatus{State: swarm.TaskStateRunning},
+		},
+	}
+
+	converged, err := updater.update(service, tasks, activeNodes, false)
+	assert.NoError(t, err)
+	assert.False(t, converged)
+
+	// Slots are mapped to progress bars in the order in which they are
+	// seen, so only the actions are compared.
+	var actions []string
+	for _, bar := range p.p {
+		if bar.Current != 0 {
+			actions = append(actions, bar.Action)
+		}
+	}
+	sort.Strings(actions)
+	assert.Equal(t, []string{"running   new", "running   old"}, actions)
+	assert.Equal(t, progress.Progress{ID: "overall progress", Action: "1 out of 2 tasks"}, p.p[len(p.p)-1])
+
+	inspector := fakeContainerInspector{}
+	healthy, err := canariesHealthy(context.Background(), inspector, updater, tasks, activeNodes)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), healthy)
+
+	// A canary whose container is unhealthy on the local node doesn't count
+	tasks[1].Status.ContainerStatus.ContainerID = "new-1-container"
+	inspector["new-1-container"] = &types.ContainerState{Health: &types.Health{Status: types.Unhealthy}}
+	healthy, err = canariesHealthy(context.Background(), inspector, updater, tasks, activeNodes)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), healthy)
+
+	inspector["new-1-container"].Health.Status = types.Healthy
+	healthy, err = canariesHealthy(context.Background(), inspector, updater, tasks, activeNodes)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), healthy)
+}
+
+// fakeContainerInspector holds the state of the containers on the local node,
+// by ID. The other containers are not found.
+type fakeContainerInspector map[string]*types.ContainerState
+
+func (f fakeContainerInspector) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	state, ok := f[containerID]
+	if !ok {
+		return types.ContainerJSON{}, notFoundError{}
+	}
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: containerID, State: state}}, nil
+}
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string  { return "no such container" }
+func (notFoundError) NotFound() bool { return true }
+
 func TestGlobalProgressUpdaterOneNode(t *testing.T) {
 	service := swarm.Service{
 		Spec: swarm.ServiceSpec{
@@ -2,6 +2,7 @@ package service
 
 import (
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -11,6 +12,7 @@ import (
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -24,6 +26,8 @@ type psOptions struct {
 	noTrunc   bool
 	format    string
 	filter    opts.FilterOpt
+	tree      bool
+	since     string
 }
 
 func newPsCommand(dockerCli command.Cli) *cobra.Command {
@@ -44,6 +48,8 @@ func newPsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noResolve, "no-resolve", false, "Do not map IDs to Names")
 	flags.StringVar(&options.format, "format", "", "Pretty-print tasks using a Go template")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.BoolVar(&options.tree, "tree", false, "Group tasks by slot, or by node for global services, and show their history")
+	flags.StringVar(&options.since, "since", "", "Only show tasks that changed state since a timestamp (e.g. 2013-01-02T13:23:37) or relative (e.g. 42m for 42 minutes)")
 
 	return cmd
 }
@@ -52,6 +58,10 @@ func runPS(dockerCli command.Cli, options psOptions) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 
+	if options.tree && (options.quiet || options.format != "") {
+		return errors.New("--tree can't be combined with --quiet or --format")
+	}
+
 	filter, notfound, err := createFilter(ctx, client, options)
 	if err != nil {
 		return err
@@ -65,6 +75,24 @@ func runPS(dockerCli command.Cli, options psOptions) error {
 		return err
 	}
 
+	if options.since != "" {
+		since, err := parseSince(options.since)
+		if err != nil {
+			return err
+		}
+		tasks = task.FilterSince(tasks, since)
+	}
+
+	if options.tree {
+		if err := task.PrintTree(ctx, dockerCli, tasks, idresolver.New(client, options.noResolve), !options.noTrunc); err != nil {
+			return err
+		}
+		if len(notfound) != 0 {
+			return errors.New(strings.Join(notfound, "\n"))
+		}
+		return nil
+	}
+
 	format := options.format
 	if len(format) == 0 {
 		format = task.DefaultFormat(dockerCli.ConfigFile(), options.quiet)
@@ -78,6 +106,20 @@ func runPS(dockerCli command.Cli, options psOptions) error {
 	return nil
 }
 
+// parseSince parses a timestamp, or a duration relative to now, as accepted
+// by `docker logs --since`.
+func parseSince(value string) (time.Time, error) {
+	ts, err := timetypes.GetTimestamp(value, time.Now())
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
+
 func createFilter(ctx context.Context, client client.APIClient, options psOptions) (filters.Args, []string, error) {
 	filter := options.filter.Value()
 
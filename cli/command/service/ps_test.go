@@ -2,6 +2,7 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/opts"
@@ -90,6 +91,31 @@ func TestRunPSWarnsOnNotFound(t *testing.T) {
 	assert.EqualError(t, err, "no such service: bar")
 }
 
+func TestRunPSTreeWithQuiet(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	options := psOptions{
+		services: []string{"foo"},
+		filter:   opts.NewFilterOpt(),
+		quiet:    true,
+		tree:     true,
+	}
+	err := runPS(cli, options)
+	assert.EqualError(t, err, "--tree can't be combined with --quiet or --format")
+}
+
+func TestParseSince(t *testing.T) {
+	since, err := parseSince("2017-01-02T13:23:37Z")
+	require.NoError(t, err)
+	assert.True(t, since.Equal(time.Date(2017, 1, 2, 13, 23, 37, 0, time.UTC)))
+
+	since, err = parseSince("10m")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-10*time.Minute), since, time.Minute)
+
+	_, err = parseSince("not a time")
+	assert.Error(t, err)
+}
+
 func TestUpdateNodeFilter(t *testing.T) {
 	selfNodeID := "foofoo"
 	filter := filters.NewArgs()
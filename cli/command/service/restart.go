@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type restartOptions struct {
+	filter opts.FilterOpt
+	detach bool
+	quiet  bool
+
+	services []string
+}
+
+func newRestartCommand(dockerCli command.Cli) *cobra.Command {
+	options := restartOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "restart [OPTIONS] SERVICE [SERVICE...]",
+		Short: "Restart the tasks of one or more services",
+		Args: func(cmd *cobra.Command, args []string) error {
+			// Services can be selected by filter instead
+			if options.filter.Value().Len() > 0 {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.services = args
+			return runRestart(dockerCli, options)
+		},
+		Tags: map[string]string{"version": "1.25"},
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&options.filter, "filter", "f", "Restart the services that match the conditions provided, like 'docker service ls'")
+	flags.BoolVarP(&options.quiet, flagQuiet, "q", false, "Suppress progress output")
+	addDetachFlag(flags, &options.detach)
+
+	return cmd
+}
+
+func runRestart(dockerCli command.Cli, options restartOptions) error {
+	apiClient := dockerCli.Client()
+	ctx := context.Background()
+
+	services, err := selectServices(ctx, dockerCli, options.services, options.filter)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Fprintln(dockerCli.Err(), "No services match the given filters")
+		return nil
+	}
+
+	var (
+		errs      []string
+		restarted []string
+	)
+	for _, serviceID := range services {
+		if err := restartService(ctx, dockerCli, serviceID); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		fmt.Fprintf(dockerCli.Out(), "%s\n", serviceID)
+		restarted = append(restarted, serviceID)
+	}
+
+	if !options.detach && len(restarted) > 0 && versions.GreaterThanOrEqualTo(apiClient.ClientVersion(), "1.29") {
+		if err := WaitOnServices(ctx, dockerCli, restarted, options.quiet); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// selectServices returns the given services, followed by the names of the
+// services that match the filter.
+func selectServices(ctx context.Context, dockerCli command.Cli, names []string, filter opts.FilterOpt) ([]string, error) {
+	if filter.Value().Len() == 0 {
+		return names, nil
+	}
+
+	services, err := dockerCli.Client().ServiceList(ctx, types.ServiceListOptions{Filters: filter.Value()})
+	if err != nil {
+		return nil, err
+	}
+
+	selected := append([]string{}, names...)
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+	for _, service := range services {
+		if seen[service.Spec.Name] || seen[service.ID] {
+			continue
+		}
+		seen[service.Spec.Name] = true
+		selected = append(selected, service.Spec.Name)
+	}
+	return selected, nil
+}
+
+// restartService replaces all tasks of a service, following its update
+// config, without changing its spec.
+func restartService(ctx context.Context, dockerCli command.Cli, serviceID string) error {
+	apiClient := dockerCli.Client()
+
+	service, _, err := apiClient.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+
+	// The tasks would be replaced with the delay of the canary update, which
+	// only ends when it is promoted
+	if _, ok := service.Spec.Labels[canaryLabel]; ok {
+		return errors.Errorf("service %s has a canary update in progress, promote it with `docker service promote` or revert it with `docker service rollback` first", serviceID)
+	}
+
+	spec := service.Spec
+	spec.TaskTemplate.ForceUpdate++
+
+	updateOpts := types.ServiceUpdateOptions{RegistryAuthFrom: types.RegistryAuthFromSpec}
+	response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, spec, updateOpts)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range response.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestServiceRestart(t *testing.T) {
+	updated := map[string]uint64{}
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			service := newService("id-"+serviceID, serviceID)
+			service.Spec.TaskTemplate.ForceUpdate = 2
+			return service, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			assert.Equal(t, types.RegistryAuthFromSpec, options.RegistryAuthFrom)
+			updated[service.Name] = service.TaskTemplate.ForceUpdate
+			return types.ServiceUpdateResponse{}, nil
+		},
+		serviceListFunc: func(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+			assert.Equal(t, []string{"tier=web"}, options.Filters.Get("label"))
+			return []swarm.Service{newService("id-web", "web"), newService("id-api", "api")}, nil
+		},
+	})
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--filter", "label=tier=web", "--detach", "web", "db"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, map[string]uint64{"web": 3, "db": 3, "api": 3}, updated)
+	assert.Equal(t, "web\ndb\napi\n", cli.OutBuffer().String())
+}
+
+func TestServiceRestartErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			if serviceID == "missing" {
+				return swarm.Service{}, nil, errors.Errorf("service %s not found", serviceID)
+			}
+			return newService("id-"+serviceID, serviceID), nil, nil
+		},
+	})
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--detach", "missing", "web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.EqualError(t, cmd.Execute(), "service missing not found")
+	assert.Equal(t, "web\n", cli.OutBuffer().String())
+}
+
+func TestServiceRestartCanary(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			service := newService("id-"+serviceID, serviceID)
+			service.Spec.Labels = map[string]string{canaryLabel: "{}"}
+			return service, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			t.Fatal("the service must not be updated")
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--detach", "web"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.EqualError(t, cmd.Execute(), "service web has a canary update in progress, promote it with `docker service promote` or revert it with `docker service rollback` first")
+}
+
+func TestServiceRestartNoMatch(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newRestartCommand(cli)
+	cmd.SetArgs([]string{"--filter", "label=tier=none"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "No services match the given filters\n", cli.ErrBuffer().String())
+}
+
+func TestPrefixProgress(t *testing.T) {
+	input := `{"id":"overall progress","status":"1 out of 2 tasks"}
+{"status":"Operation continuing in background."}
+`
+	var messages []jsonmessage.JSONMessage
+	prefixProgress(strings.NewReader(input), "web", func(msg jsonmessage.JSONMessage) {
+		messages = append(messages, msg)
+	})
+	assert.Equal(t, []jsonmessage.JSONMessage{
+		{ID: "web overall progress", Status: "1 out of 2 tasks"},
+		{ID: "web", Status: "Operation continuing in background."},
+	}, messages)
+}
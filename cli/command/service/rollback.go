@@ -45,6 +45,15 @@ func runRollback(dockerCli command.Cli, options *serviceOptions, serviceID strin
 		Rollback: "previous",
 	}
 
+	previous, err := canaryRollbackSpec(service)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		spec = previous
+		updateOpts = types.ServiceUpdateOptions{RegistryAuthFrom: types.RegistryAuthFromSpec}
+	}
+
 	response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, *spec, updateOpts)
 	if err != nil {
 		return err
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// RotateOptions are the options of the rotation of a secret or a config.
+type RotateOptions struct {
+	File   string
+	Name   string
+	Labels []string
+	Remove bool
+	Detach bool
+	Quiet  bool
+}
+
+// Rotation accesses the kind of object that is rotated, secrets or configs.
+type Rotation struct {
+	// Kind is the kind of the object, as it is named in messages
+	Kind string
+	// Inspect returns the ID, the name and the labels of the object
+	Inspect func(ctx context.Context) (id, name string, labels map[string]string, err error)
+	// Create creates the new version of the object and returns its ID
+	Create func(ctx context.Context, name string, labels map[string]string, data []byte) (string, error)
+	// Remove removes a version of the object
+	Remove func(ctx context.Context, id string) error
+	// Rotate changes the references to the object in a service spec to the
+	// new version, with the same target, uid, gid and mode. It returns
+	// whether the spec references the object.
+	Rotate func(spec *swarm.ServiceSpec, oldID, newID, newName string) bool
+}
+
+// RunRotate replaces a secret or a config with a new version in all the
+// services that use it. If a service can't be updated, the services that were
+// already updated are rolled back, so that they all keep using the same
+// version.
+func RunRotate(dockerCli command.Cli, rotation Rotation, options RotateOptions) error {
+	client := dockerCli.Client()
+	ctx := context.Background()
+
+	if options.Remove && options.Detach {
+		return errors.Errorf("--rm can't be used with --detach, as the %s is only removed once the services converge", rotation.Kind)
+	}
+
+	oldID, oldName, oldLabels, err := rotation.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = dockerCli.In()
+	if options.File != "-" {
+		file, err := system.OpenSequential(options.File)
+		if err != nil {
+			return err
+		}
+		in = file
+		defer file.Close()
+	}
+
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return errors.Errorf("Error reading content from %q: %v", options.File, err)
+	}
+
+	name := options.Name
+	if name == "" {
+		name = nextVersionName(oldName)
+	}
+	labels := make(map[string]string)
+	for k, v := range oldLabels {
+		labels[k] = v
+	}
+	for k, v := range opts.ConvertKVStringsToMap(options.Labels) {
+		labels[k] = v
+	}
+
+	newID, err := rotation.Create(ctx, name, labels, data)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), newID)
+
+	services, err := client.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var updated []swarm.Service
+	for _, s := range services {
+		if !rotation.Rotate(&s.Spec, oldID, newID, name) {
+			continue
+		}
+		updateOpts := types.ServiceUpdateOptions{RegistryAuthFrom: types.RegistryAuthFromSpec}
+		response, err := client.ServiceUpdate(ctx, s.ID, s.Version, s.Spec, updateOpts)
+		if err != nil {
+			errs := []string{fmt.Sprintf("%s: %v", s.Spec.Name, err)}
+			errs = append(errs, rollbackRotation(ctx, dockerCli, rotation, updated, newID, name)...)
+			return errors.New(strings.Join(errs, "\n"))
+		}
+		for _, warning := range response.Warnings {
+			fmt.Fprintln(dockerCli.Err(), warning)
+		}
+		fmt.Fprintf(dockerCli.Err(), "Updated service %s\n", s.Spec.Name)
+		updated = append(updated, s)
+	}
+
+	if !options.Detach && len(updated) > 0 && versions.GreaterThanOrEqualTo(client.ClientVersion(), "1.29") {
+		serviceIDs := make([]string, 0, len(updated))
+		for _, s := range updated {
+			serviceIDs = append(serviceIDs, s.ID)
+		}
+		if err := WaitOnServices(ctx, dockerCli, serviceIDs, options.Quiet); err != nil {
+			return err
+		}
+	}
+
+	if options.Remove {
+		return rotation.Remove(ctx, oldID)
+	}
+	return nil
+}
+
+// rollbackRotation rolls back the services that were updated to use the new
+// version of a secret or a config, and removes it once none of them uses it.
+// It returns the errors, which name the services that still use the new
+// version.
+func rollbackRotation(ctx context.Context, dockerCli command.Cli, rotation Rotation, updated []swarm.Service, newID, newName string) []string {
+	var errs []string
+	for _, s := range updated {
+		err := rollbackService(ctx, dockerCli, s.ID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: failed to roll back, the service uses %s %s: %v", s.Spec.Name, rotation.Kind, newName, err))
+			continue
+		}
+		fmt.Fprintf(dockerCli.Err(), "Rolled back service %s\n", s.Spec.Name)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if err := rotation.Remove(ctx, newID); err != nil {
+		return []string{fmt.Sprintf("failed to remove %s %s: %v", rotation.Kind, newName, err)}
+	}
+	return nil
+}
+
+// rollbackService rolls a service back to its previous spec.
+func rollbackService(ctx context.Context, dockerCli command.Cli, serviceID string) error {
+	client := dockerCli.Client()
+
+	service, _, err := client.ServiceInspectWithRaw(ctx, serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return err
+	}
+	response, err := client.ServiceUpdate(ctx, service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{Rollback: "previous"})
+	if err != nil {
+		return err
+	}
+	for _, warning := range response.Warnings {
+		fmt.Fprintln(dockerCli.Err(), warning)
+	}
+	return nil
+}
+
+var versionSuffix = regexp.MustCompile(`^(.*)_v([0-9]+)$`)
+
+// nextVersionName returns the name of the next version of a secret or a
+// config: the name with a _v2 suffix, or with the version in its suffix
+// incremented.
+func nextVersionName(name string) string {
+	if m := versionSuffix.FindStringSubmatch(name); m != nil {
+		if version, err := strconv.Atoi(m[2]); err == nil {
+			return fmt.Sprintf("%s_v%d", m[1], version+1)
+		}
+	}
+	return name + "_v2"
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextVersionName(t *testing.T) {
+	assert.Equal(t, "password_v2", nextVersionName("password"))
+	assert.Equal(t, "password_v2", nextVersionName("password_v1"))
+	assert.Equal(t, "password_v10", nextVersionName("password_v9"))
+	assert.Equal(t, "password_vx_v2", nextVersionName("password_vx"))
+}
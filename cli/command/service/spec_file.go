@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// readSpecFile reads a full or partial service spec from a JSON or YAML
+// file. The fields are kept as they are written in the file, so that fields
+// that are set to a zero value can be told apart from fields that are not set.
+func readSpecFile(filename string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err == nil {
+		return fields, nil
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, errors.Wrapf(err, "invalid service spec in %s", filename)
+	}
+	fields, ok := convertYAML(value).(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("invalid service spec in %s: expected a mapping", filename)
+	}
+	return fields, nil
+}
+
+// convertYAML converts the mappings read by the yaml package, which have keys
+// of any type, to mappings that can be encoded as JSON.
+func convertYAML(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			m[fmt.Sprint(k)] = convertYAML(v)
+		}
+		return m
+	case []interface{}:
+		for i, v := range value {
+			value[i] = convertYAML(v)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// mergeSpecFields merges the fields of overlay into base. Mappings are merged
+// key by key, all other values replace the value in base. Keys are matched
+// regardless of case, as they are when a spec is decoded.
+func mergeSpecFields(base, overlay map[string]interface{}) {
+	for key, value := range overlay {
+		baseKey := specFieldKey(base, key)
+		baseMap, baseIsMap := base[baseKey].(map[string]interface{})
+		overlayMap, overlayIsMap := value.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			mergeSpecFields(baseMap, overlayMap)
+			continue
+		}
+		base[baseKey] = value
+	}
+}
+
+// overrideSpecField sets the field of base at the given path to its value in
+// overlay, or removes it if overlay doesn't set it. Mappings are merged key by
+// key.
+func overrideSpecField(base, overlay map[string]interface{}, path []string) {
+	baseKey := specFieldKey(base, path[0])
+	value, ok := overlay[path[0]]
+	if len(path) > 1 {
+		overlayMap, _ := value.(map[string]interface{})
+		baseMap, baseIsMap := base[baseKey].(map[string]interface{})
+		if !baseIsMap {
+			if overlayMap == nil {
+				return
+			}
+			baseMap = make(map[string]interface{})
+			base[baseKey] = baseMap
+		}
+		overrideSpecField(baseMap, overlayMap, path[1:])
+		return
+	}
+
+	if !ok {
+		delete(base, baseKey)
+		return
+	}
+	baseMap, baseIsMap := base[baseKey].(map[string]interface{})
+	overlayMap, overlayIsMap := value.(map[string]interface{})
+	if baseIsMap && overlayIsMap {
+		mergeSpecFields(baseMap, overlayMap)
+		return
+	}
+	base[baseKey] = value
+}
+
+// specFieldKey returns the key of a field in fields, matched regardless of
+// case. The key itself is returned if fields has no such field.
+func specFieldKey(fields map[string]interface{}, key string) string {
+	for k := range fields {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
+
+// specToFields converts a service spec to the fields that are merged with a
+// spec file.
+func specToFields(spec swarm.ServiceSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// specFromFields converts merged fields back to a service spec.
+func specFromFields(fields map[string]interface{}) (swarm.ServiceSpec, error) {
+	var spec swarm.ServiceSpec
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return spec, err
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return spec, errors.Wrap(err, "invalid service spec")
+	}
+	return spec, nil
+}
+
+// validateServiceSpec checks a service spec that was read from a file before
+// it is sent to the daemon, so that mistakes are reported before anything is
+// changed.
+func validateServiceSpec(spec swarm.ServiceSpec) error {
+	if spec.TaskTemplate.ContainerSpec == nil || spec.TaskTemplate.ContainerSpec.Image == "" {
+		return errors.New("invalid service spec: no image specified")
+	}
+	if spec.TaskTemplate.Runtime != "" && spec.TaskTemplate.Runtime != swarm.RuntimeContainer {
+		return errors.Errorf("invalid service spec: unsupported runtime %s", spec.TaskTemplate.Runtime)
+	}
+	mode := spec.Mode
+	if mode.Global != nil && mode.Replicated != nil {
+		return errors.New("invalid service spec: a service can't be both global and replicated")
+	}
+	if endpoint := spec.EndpointSpec; endpoint != nil {
+		switch endpoint.Mode {
+		case "", swarm.ResolutionModeVIP:
+		case swarm.ResolutionModeDNSRR:
+			for _, port := range endpoint.Ports {
+				if port.PublishMode != swarm.PortConfigPublishModeHost {
+					return errors.New("invalid service spec: ports can only be published in host mode with endpoint mode dnsrr")
+				}
+			}
+		default:
+			return errors.Errorf("invalid service spec: unknown endpoint mode %s", endpoint.Mode)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func writeSpecFile(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "service-spec")
+	require.NoError(t, err)
+	filename := filepath.Join(dir, "spec.yml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+	return filename, func() { os.RemoveAll(dir) }
+}
+
+func TestReadSpecFile(t *testing.T) {
+	for _, content := range []string{
+		`{"Name": "web", "Labels": {"a": "1"}, "TaskTemplate": {"ContainerSpec": {"Image": "nginx"}}}`,
+		"Name: web\nLabels:\n  a: \"1\"\nTaskTemplate:\n  ContainerSpec:\n    Image: nginx\n",
+	} {
+		filename, cleanup := writeSpecFile(t, content)
+		defer cleanup()
+
+		fields, err := readSpecFile(filename)
+		require.NoError(t, err)
+		spec, err := specFromFields(fields)
+		require.NoError(t, err)
+		assert.Equal(t, "web", spec.Name)
+		assert.Equal(t, map[string]string{"a": "1"}, spec.Labels)
+		assert.Equal(t, "nginx", spec.TaskTemplate.ContainerSpec.Image)
+	}
+}
+
+func TestReadSpecFileInvalid(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, "- web\n- nginx\n")
+	defer cleanup()
+
+	_, err := readSpecFile(filename)
+	testutil.ErrorContains(t, err, "expected a mapping")
+}
+
+func TestMergeSpecFields(t *testing.T) {
+	base := map[string]interface{}{
+		"Name":   "web",
+		"Labels": map[string]interface{}{"a": "1", "b": "2"},
+		"Mode":   map[string]interface{}{"Replicated": map[string]interface{}{"Replicas": float64(2)}},
+	}
+	overlay := map[string]interface{}{
+		"labels": map[string]interface{}{"b": "3"},
+		"Mode":   map[string]interface{}{"Replicated": map[string]interface{}{"Replicas": float64(0)}},
+		"Env":    []interface{}{},
+	}
+
+	mergeSpecFields(base, overlay)
+	assert.Equal(t, map[string]interface{}{
+		"Name":   "web",
+		"Labels": map[string]interface{}{"a": "1", "b": "3"},
+		"Mode":   map[string]interface{}{"Replicated": map[string]interface{}{"Replicas": float64(0)}},
+		"Env":    []interface{}{},
+	}, base)
+}
+
+func TestOverrideSpecField(t *testing.T) {
+	base := map[string]interface{}{
+		"labels": map[string]interface{}{"a": "1"},
+		"TaskTemplate": map[string]interface{}{
+			"ContainerSpec": map[string]interface{}{"Image": "nginx", "TTY": true},
+		},
+	}
+	overlay := map[string]interface{}{
+		"Labels": map[string]interface{}{"b": "2"},
+		"TaskTemplate": map[string]interface{}{
+			"ContainerSpec": map[string]interface{}{"Image": "redis"},
+			"Resources":     map[string]interface{}{"Limits": map[string]interface{}{"MemoryBytes": float64(1024)}},
+		},
+	}
+
+	overrideSpecField(base, overlay, []string{"Labels"})
+	overrideSpecField(base, overlay, []string{"TaskTemplate", "ContainerSpec", "TTY"})
+	overrideSpecField(base, overlay, []string{"TaskTemplate", "Resources", "Limits", "MemoryBytes"})
+	overrideSpecField(base, overlay, []string{"TaskTemplate", "Placement", "Constraints"})
+	assert.Equal(t, map[string]interface{}{
+		"labels": map[string]interface{}{"a": "1", "b": "2"},
+		"TaskTemplate": map[string]interface{}{
+			"ContainerSpec": map[string]interface{}{"Image": "nginx"},
+			"Resources":     map[string]interface{}{"Limits": map[string]interface{}{"MemoryBytes": float64(1024)}},
+		},
+	}, base)
+}
+
+func TestValidateServiceSpec(t *testing.T) {
+	testCases := []struct {
+		spec          swarm.ServiceSpec
+		expectedError string
+	}{
+		{
+			spec:          swarm.ServiceSpec{},
+			expectedError: "no image specified",
+		},
+		{
+			spec: swarm.ServiceSpec{
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx"}},
+				Mode:         swarm.ServiceMode{Global: &swarm.GlobalService{}, Replicated: &swarm.ReplicatedService{}},
+			},
+			expectedError: "can't be both global and replicated",
+		},
+		{
+			spec: swarm.ServiceSpec{
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "nginx"}},
+				EndpointSpec: &swarm.EndpointSpec{
+					Mode:  swarm.ResolutionModeDNSRR,
+					Ports: []swarm.PortConfig{{TargetPort: 80}},
+				},
+			},
+			expectedError: "only be published in host mode",
+		},
+	}
+	for _, tc := range testCases {
+		testutil.ErrorContains(t, validateServiceSpec(tc.spec), tc.expectedError)
+	}
+}
+
+func TestCreateWithSpecFile(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, `
+Name: web
+Labels:
+  team: frontend
+TaskTemplate:
+  ContainerSpec:
+    Image: nginx:alpine
+    Env: [A=1]
+Mode:
+  Global: {}
+`)
+	defer cleanup()
+
+	var created swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceCreateFunc: func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			created = service
+			return types.ServiceCreateResponse{ID: "id"}, nil
+		},
+	})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "--label", "env=prod", "--env", "B=2"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, "web", created.Name)
+	assert.Equal(t, map[string]string{"team": "frontend", "env": "prod"}, created.Labels)
+	assert.Equal(t, "nginx:alpine", created.TaskTemplate.ContainerSpec.Image)
+	// Lists set by a flag replace the list in the file
+	assert.Equal(t, []string{"B=2"}, created.TaskTemplate.ContainerSpec.Env)
+	assert.NotNil(t, created.Mode.Global)
+	assert.Nil(t, created.Mode.Replicated)
+	assert.Equal(t, "id\n", cli.OutBuffer().String())
+	assert.Contains(t, cli.ErrBuffer().String(), "+ ContainerSpec.Image: nginx:alpine\n")
+}
+
+func TestCreateWithSpecFileModeFlag(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, `{"TaskTemplate": {"ContainerSpec": {"Image": "nginx"}}, "Mode": {"Global": {}}}`)
+	defer cleanup()
+
+	var created swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceCreateFunc: func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			created = service
+			return types.ServiceCreateResponse{ID: "id"}, nil
+		},
+	})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "--replicas", "3"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Nil(t, created.Mode.Global)
+	require.NotNil(t, created.Mode.Replicated)
+	assert.Equal(t, uint64(3), *created.Mode.Replicated.Replicas)
+}
+
+func TestCreateWithSpecFileZeroFlags(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, `
+Name: web
+TaskTemplate:
+  ContainerSpec:
+    Image: nginx:alpine
+    TTY: true
+    ReadOnly: true
+    StopGracePeriod: 10000000000
+    User: nginx
+Mode:
+  Replicated:
+    Replicas: 3
+`)
+	defer cleanup()
+
+	var created swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceCreateFunc: func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			created = service
+			return types.ServiceCreateResponse{ID: "id"}, nil
+		},
+	})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "--tty=false", "--read-only=false", "--stop-grace-period", "0s", "--replicas", "0"})
+	require.NoError(t, cmd.Execute())
+
+	// Flags that are set override the file even with a zero value
+	containerSpec := created.TaskTemplate.ContainerSpec
+	assert.False(t, containerSpec.TTY)
+	assert.False(t, containerSpec.ReadOnly)
+	require.NotNil(t, containerSpec.StopGracePeriod)
+	assert.Equal(t, time.Duration(0), *containerSpec.StopGracePeriod)
+	assert.Equal(t, uint64(0), *created.Mode.Replicated.Replicas)
+	// Flags that are not set don't
+	assert.Equal(t, "nginx", containerSpec.User)
+}
+
+func TestCreateWithSpecFileUpdateConfigFlag(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, `
+TaskTemplate:
+  ContainerSpec:
+    Image: nginx
+  RestartPolicy:
+    Condition: on-failure
+    MaxAttempts: 3
+UpdateConfig:
+  Parallelism: 3
+  FailureAction: rollback
+`)
+	defer cleanup()
+
+	var created swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceCreateFunc: func(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+			created = service
+			return types.ServiceCreateResponse{ID: "id"}, nil
+		},
+	})
+	cmd := newCreateCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "--update-delay", "10s", "--restart-window", "1m"})
+	require.NoError(t, cmd.Execute())
+
+	// Only the fields set by a flag replace the fields in the file
+	require.NotNil(t, created.UpdateConfig)
+	assert.Equal(t, uint64(3), created.UpdateConfig.Parallelism)
+	assert.Equal(t, "rollback", created.UpdateConfig.FailureAction)
+	assert.Equal(t, 10*time.Second, created.UpdateConfig.Delay)
+
+	require.NotNil(t, created.TaskTemplate.RestartPolicy)
+	assert.Equal(t, swarm.RestartPolicyConditionOnFailure, created.TaskTemplate.RestartPolicy.Condition)
+	assert.Equal(t, uint64(3), *created.TaskTemplate.RestartPolicy.MaxAttempts)
+	assert.Equal(t, time.Minute, *created.TaskTemplate.RestartPolicy.Window)
+}
+
+func TestCreateWithSpecFileWithoutImage(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, "Name: web\n")
+	defer cleanup()
+
+	cmd := newCreateCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--spec", filename})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), "no image specified")
+}
+
+func TestUpdateWithSpecFile(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, `
+Labels:
+  env: prod
+TaskTemplate:
+  ContainerSpec:
+    Image: nginx:1.13
+`)
+	defer cleanup()
+
+	replicas := uint64(2)
+	current := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web", Labels: map[string]string{"team": "frontend"}},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.12", Env: []string{"A=1"}},
+		},
+		Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+	}
+	var updated swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: current}, nil, nil
+		},
+		serviceUpdateFunc: func(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
+			updated = service
+			return types.ServiceUpdateResponse{}, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "--env-add", "B=2", "web"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, map[string]string{"team": "frontend", "env": "prod"}, updated.Labels)
+	assert.Equal(t, "nginx:1.13", updated.TaskTemplate.ContainerSpec.Image)
+	// --env-add doesn't keep the order of the variables
+	sort.Strings(updated.TaskTemplate.ContainerSpec.Env)
+	assert.Equal(t, []string{"A=1", "B=2"}, updated.TaskTemplate.ContainerSpec.Env)
+	assert.Equal(t, uint64(2), *updated.Mode.Replicated.Replicas)
+
+	expected := `+ Labels: env=prod
+~ ContainerSpec.Image: nginx:1.12 -> nginx:1.13
++ ContainerSpec.Env: B=2
+`
+	assert.Equal(t, expected, cli.ErrBuffer().String())
+	assert.Equal(t, "web\n", cli.OutBuffer().String())
+}
+
+func TestUpdateWithSpecFileName(t *testing.T) {
+	filename, cleanup := writeSpecFile(t, "Name: api\n")
+	defer cleanup()
+
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{ID: "id", Spec: diffTestSpec("nginx")}, nil, nil
+		},
+	})
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--spec", filename, "web"})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), "the name of service web can't be changed to api")
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/container"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// statsRefreshInterval is how often the running tasks of the services are
+// listed again when streaming their statistics.
+const statsRefreshInterval = 5 * time.Second
+
+type statsOptions struct {
+	services  []string
+	noStream  bool
+	format    string
+	nodeHosts opts.ListOpts
+
+	// newNodeClient creates a client for the engine of a node tasks run on,
+	// when it isn't the node the CLI is connected to
+	newNodeClient func(host string) (client.APIClient, error)
+}
+
+func newStatsCommand(dockerCli *command.DockerCli) *cobra.Command {
+	options := statsOptions{
+		nodeHosts:     opts.NewListOpts(validateNodeHost),
+		newNodeClient: dockerCli.NewNodeClient,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "stats [OPTIONS] [SERVICE...]",
+		Short: "Display a live stream of the resource usage statistics of services and their tasks",
+		Args:  cli.RequiresMinArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.services = args
+			return runStats(dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&options.noStream, "no-stream", false, "Disable streaming stats and only pull the first result")
+	flags.StringVar(&options.format, "format", "", "Pretty-print stats using a Go template")
+	flags.Var(&options.nodeHosts, "node-host", "Engine endpoint of a node (format: NODE=HOST)")
+	return cmd
+}
+
+// runStats displays a live stream of the resource usage statistics of the
+// running tasks of services, and of their sum for each service. The
+// statistics of a task are collected from the engine of the node it runs on.
+func runStats(dockerCli command.Cli, options statsOptions) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	info, err := dockerCli.Client().Info(ctx)
+	if err != nil {
+		return err
+	}
+	s := newServiceStats(dockerCli, options, info.Swarm.NodeID)
+	if err := s.refresh(ctx); err != nil {
+		return err
+	}
+	lastRefresh := time.Now()
+
+	// before printing, make sure each task gets its first statistics
+	s.waitFirst.Wait()
+
+	format := options.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	statsCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: formatter.NewServiceStatsFormat(format),
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if !options.noStream {
+			fmt.Fprint(dockerCli.Out(), "\033[2J")
+			fmt.Fprint(dockerCli.Out(), "\033[H")
+		}
+		if err := formatter.ServiceStatsWrite(statsCtx, s.entries()); err != nil {
+			return err
+		}
+		if options.noStream {
+			return nil
+		}
+
+		<-ticker.C
+		if time.Since(lastRefresh) >= statsRefreshInterval {
+			if err := s.refresh(ctx); err != nil {
+				return err
+			}
+			lastRefresh = time.Now()
+		}
+	}
+}
+
+// taskStats are the statistics of the container of a running task.
+type taskStats struct {
+	serviceID string
+	name      string
+	slot      int
+	node      string
+	osType    string
+	stats     *formatter.ContainerStats
+	cancel    func()
+}
+
+// serviceStats collects the statistics of the running tasks of services, on
+// the engines of the nodes they run on.
+type serviceStats struct {
+	dockerCli   command.Cli
+	options     statsOptions
+	localNodeID string
+	waitFirst   sync.WaitGroup
+
+	// nodeClients and nodeErrors are the clients of the engines of the
+	// nodes, or the errors connecting to them, by node ID
+	nodeClients map[string]client.APIClient
+	nodeErrors  map[string]error
+
+	mu       sync.Mutex
+	services []swarm.Service
+	tasks    map[string]*taskStats
+}
+
+func newServiceStats(dockerCli command.Cli, options statsOptions, localNodeID string) *serviceStats {
+	return &serviceStats{
+		dockerCli:   dockerCli,
+		options:     options,
+		localNodeID: localNodeID,
+		nodeClients: make(map[string]client.APIClient),
+		nodeErrors:  make(map[string]error),
+		tasks:       make(map[string]*taskStats),
+	}
+}
+
+// refresh lists the running tasks of the services, starts collecting the
+// statistics of the new tasks and stops collecting those of the tasks that
+// aren't running anymore.
+func (s *serviceStats) refresh(ctx context.Context) error {
+	apiClient := s.dockerCli.Client()
+
+	services, err := s.listServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := apiClient.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	nodesByID := make(map[string]swarm.Node)
+	for _, node := range nodes {
+		nodesByID[node.ID] = node
+	}
+
+	running := make(map[string]bool)
+	for _, service := range services {
+		filter := filters.NewArgs()
+		filter.Add("service", service.ID)
+		filter.Add("desired-state", string(swarm.TaskStateRunning))
+		tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{Filters: filter})
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if task.ServiceID != service.ID || task.Status.State != swarm.TaskStateRunning || task.Status.ContainerStatus.ContainerID == "" {
+				continue
+			}
+			running[task.ID] = true
+			if _, ok := s.tasks[task.ID]; !ok {
+				s.add(ctx, service, task, nodesByID[task.NodeID])
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, task := range s.tasks {
+		if !running[id] {
+			task.cancel()
+			delete(s.tasks, id)
+		}
+	}
+	s.services = services
+	return nil
+}
+
+// listServices returns the services given as arguments, or else all the
+// services, sorted by name.
+func (s *serviceStats) listServices(ctx context.Context) ([]swarm.Service, error) {
+	apiClient := s.dockerCli.Client()
+
+	if len(s.options.services) == 0 {
+		services, err := apiClient.ServiceList(ctx, types.ServiceListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(services, func(i, j int) bool { return services[i].Spec.Name < services[j].Spec.Name })
+		return services, nil
+	}
+
+	var (
+		services []swarm.Service
+		errs     []string
+	)
+	for _, name := range s.options.services {
+		service, _, err := apiClient.ServiceInspectWithRaw(ctx, name, types.ServiceInspectOptions{})
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		services = append(services, service)
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+	return services, nil
+}
+
+// add starts collecting the statistics of a running task, on the engine of
+// the node it runs on. The statistics are computed for the OS of the node.
+func (s *serviceStats) add(ctx context.Context, service swarm.Service, task swarm.Task, node swarm.Node) {
+	nodeName := node.Description.Hostname
+	name := fmt.Sprintf("%s.%d", service.Spec.Name, task.Slot)
+	if task.Slot == 0 {
+		name = fmt.Sprintf("%s.%s", service.Spec.Name, task.NodeID)
+	}
+	taskCtx, cancel := context.WithCancel(ctx)
+	ts := &taskStats{
+		serviceID: service.ID,
+		name:      name,
+		slot:      task.Slot,
+		node:      nodeName,
+		osType:    node.Description.Platform.OS,
+		stats:     formatter.NewContainerStats(task.Status.ContainerStatus.ContainerID),
+		cancel:    cancel,
+	}
+	s.mu.Lock()
+	s.tasks[task.ID] = ts
+	s.mu.Unlock()
+
+	nodeClient, err := s.nodeClient(ctx, task.NodeID, nodeName)
+	if err != nil {
+		ts.stats.SetError(err)
+		return
+	}
+	s.waitFirst.Add(1)
+	go container.CollectStats(taskCtx, ts.stats, nodeClient, ts.osType, !s.options.noStream, &s.waitFirst)
+}
+
+// nodeClient returns the client of the engine of a node, connecting to it
+// the first time. A warning is printed the first time the engine can't be
+// reached.
+func (s *serviceStats) nodeClient(ctx context.Context, nodeID, nodeName string) (client.APIClient, error) {
+	if nodeID == s.localNodeID {
+		return s.dockerCli.Client(), nil
+	}
+	if nodeClient, ok := s.nodeClients[nodeID]; ok {
+		return nodeClient, nil
+	}
+	if err, ok := s.nodeErrors[nodeID]; ok {
+		return nil, err
+	}
+
+	host, err := nodeEngineHost(s.dockerCli, s.options.nodeHosts, nodeID, nodeName)
+	if err == nil {
+		var nodeClient client.APIClient
+		if nodeClient, err = s.options.newNodeClient(host); err == nil {
+			nodeClient.NegotiateAPIVersion(ctx)
+			s.nodeClients[nodeID] = nodeClient
+			return nodeClient, nil
+		}
+		err = errors.Wrapf(err, "failed to connect to node %s at %s", nodeName, host)
+	}
+	s.nodeErrors[nodeID] = err
+	fmt.Fprintf(s.dockerCli.Err(), "WARNING: the statistics of the tasks on node %s are not available: %v\n", nodeName, err)
+	return nil, err
+}
+
+// entries returns the statistics of each service followed by those of its
+// tasks.
+func (s *serviceStats) entries() []formatter.ServiceStatsEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []formatter.ServiceStatsEntry
+	for _, service := range s.services {
+		var tasks []*taskStats
+		for _, task := range s.tasks {
+			if task.serviceID == service.ID {
+				tasks = append(tasks, task)
+			}
+		}
+		sort.Slice(tasks, func(i, j int) bool {
+			if tasks[i].slot != tasks[j].slot {
+				return tasks[i].slot < tasks[j].slot
+			}
+			return tasks[i].node < tasks[j].node
+		})
+
+		var (
+			stats       []formatter.StatsEntry
+			taskEntries []formatter.ServiceStatsEntry
+		)
+		for _, task := range tasks {
+			entry := task.stats.GetStatistics()
+			stats = append(stats, entry)
+			taskEntries = append(taskEntries, formatter.ServiceStatsEntry{Name: task.name, Node: task.node, OSType: task.osType, Stats: entry})
+		}
+		entries = append(entries, formatter.ServiceStatsEntry{Name: service.Spec.Name, Stats: sumStats(stats)})
+		entries = append(entries, taskEntries...)
+	}
+	return entries
+}
+
+// sumStats returns the sum of the statistics of the tasks of a service. It is
+// invalid if no task has valid statistics. The memory limits are not summed,
+// as the tasks can run on different nodes.
+func sumStats(stats []formatter.StatsEntry) formatter.StatsEntry {
+	sum := formatter.StatsEntry{IsInvalid: true}
+	for _, s := range stats {
+		if s.IsInvalid {
+			continue
+		}
+		sum.IsInvalid = false
+		sum.CPUPercentage += s.CPUPercentage
+		sum.Memory += s.Memory
+		sum.NetworkRx += s.NetworkRx
+		sum.NetworkTx += s.NetworkTx
+		sum.BlockRead += s.BlockRead
+		sum.BlockWrite += s.BlockWrite
+		sum.PidsCurrent += s.PidsCurrent
+	}
+	return sum
+}
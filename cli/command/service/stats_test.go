@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/gotestyourself/gotestyourself/golden"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+)
+
+// containerStats returns the statistics of a container that uses cpu% of
+// the CPUs, memory bytes of memory and received network bytes.
+func containerStats(t *testing.T, cpu uint64, memory uint64, network uint64) types.ContainerStats {
+	stats := types.StatsJSON{
+		Stats: types.Stats{
+			PreCPUStats: types.CPUStats{CPUUsage: types.CPUUsage{TotalUsage: 100}, SystemUsage: 1000},
+			CPUStats:    types.CPUStats{CPUUsage: types.CPUUsage{TotalUsage: 100 + 10*cpu}, SystemUsage: 2000, OnlineCPUs: 1},
+			MemoryStats: types.MemoryStats{Usage: memory, Limit: 1024 * 1024 * 1024},
+		},
+		Networks: map[string]types.NetworkStats{"eth0": {RxBytes: network, TxBytes: network / 10}},
+	}
+	data, err := json.Marshal(stats)
+	require.NoError(t, err)
+	return types.ContainerStats{Body: ioutil.NopCloser(bytes.NewReader(data)), OSType: "linux"}
+}
+
+// windowsContainerStats returns the statistics of a container on Windows that
+// uses cpu% of the CPUs, memory bytes of memory and received network bytes.
+func windowsContainerStats(t *testing.T, cpu uint64, memory uint64, network uint64) types.ContainerStats {
+	read := time.Date(2017, 6, 1, 0, 0, 1, 0, time.UTC)
+	stats := types.StatsJSON{
+		Stats: types.Stats{
+			Read:        read,
+			PreRead:     read.Add(-time.Second),
+			NumProcs:    1,
+			PreCPUStats: types.CPUStats{CPUUsage: types.CPUUsage{TotalUsage: 100}},
+			CPUStats:    types.CPUStats{CPUUsage: types.CPUUsage{TotalUsage: 100 + cpu*100000}},
+			MemoryStats: types.MemoryStats{PrivateWorkingSet: memory},
+		},
+		Networks: map[string]types.NetworkStats{"eth0": {RxBytes: network, TxBytes: network / 10}},
+	}
+	data, err := json.Marshal(stats)
+	require.NoError(t, err)
+	return types.ContainerStats{Body: ioutil.NopCloser(bytes.NewReader(data)), OSType: "windows"}
+}
+
+func statsTask(id string, serviceID string, slot int, nodeID string) swarm.Task {
+	return swarm.Task{
+		ID:        id,
+		ServiceID: serviceID,
+		Slot:      slot,
+		NodeID:    nodeID,
+		Status: swarm.TaskStatus{
+			State:           swarm.TaskStateRunning,
+			ContainerStatus: swarm.ContainerStatus{ContainerID: "container-" + id},
+		},
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	apiClient := &fakeClient{
+		infoFunc: func(ctx context.Context) (types.Info, error) {
+			return types.Info{Swarm: swarm.Info{NodeID: "node-1"}}, nil
+		},
+		serviceListFunc: func(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				*Service(ServiceID("web-id"), ServiceName("web")),
+				*Service(ServiceID("db-id"), ServiceName("db")),
+			}, nil
+		},
+		nodeListFunc: func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+			return []swarm.Node{
+				*Node(NodeID("node-1"), Hostname("node-1")),
+				*Node(NodeID("node-2"), Hostname("node-2"), func(node *swarm.Node) { node.Description.Platform.OS = "windows" }),
+				*Node(NodeID("node-3"), Hostname("node-3")),
+			}, nil
+		},
+		taskListFunc: func(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+			switch options.Filters.Get("service")[0] {
+			case "web-id":
+				return []swarm.Task{
+					statsTask("web-2", "web-id", 2, "node-2"),
+					statsTask("web-1", "web-id", 1, "node-1"),
+					statsTask("web-3", "web-id", 3, "node-3"),
+				}, nil
+			default:
+				return []swarm.Task{statsTask("db-1", "db-id", 1, "node-1")}, nil
+			}
+		},
+		containerStatsFunc: func(ctx context.Context, container string, stream bool) (types.ContainerStats, error) {
+			assert.False(t, stream)
+			if container == "container-db-1" {
+				return containerStats(t, 50, 200*1024*1024, 2000), nil
+			}
+			return containerStats(t, 10, 10*1024*1024, 1000), nil
+		},
+	}
+	nodeClient := &fakeClient{
+		containerStatsFunc: func(ctx context.Context, container string, stream bool) (types.ContainerStats, error) {
+			assert.Equal(t, "container-web-2", container)
+			return windowsContainerStats(t, 20, 30*1024*1024, 3000), nil
+		},
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	options := statsOptions{
+		noStream:  true,
+		nodeHosts: opts.NewListOpts(validateNodeHost),
+		newNodeClient: func(host string) (client.APIClient, error) {
+			assert.Equal(t, "tcp://10.0.0.2:2376", host)
+			return nodeClient, nil
+		},
+	}
+	require.NoError(t, options.nodeHosts.Set("node-2=tcp://10.0.0.2:2376"))
+
+	require.NoError(t, runStats(cli, options))
+	golden.Assert(t, cli.OutBuffer().String(), "service-stats.golden")
+	assert.Equal(t, "WARNING: the statistics of the tasks on node node-3 are not available: no engine endpoint is configured for node node-3: use --node-host or set nodeHosts in the configuration file\n", cli.ErrBuffer().String())
+}
+
+func TestRunStatsNoSuchService(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{}, nil, errors.Errorf("service %s not found", serviceID)
+		},
+	})
+	err := runStats(cli, statsOptions{services: []string{"foo", "bar"}, noStream: true})
+	assert.EqualError(t, err, "service foo not found\nservice bar not found")
+}
+
+func TestSumStats(t *testing.T) {
+	sum := sumStats([]formatter.StatsEntry{
+		{CPUPercentage: 10, Memory: 100, MemoryLimit: 1000, NetworkRx: 1, NetworkTx: 2, PidsCurrent: 3},
+		{CPUPercentage: 20, Memory: 300, MemoryLimit: 1000, NetworkRx: 4, NetworkTx: 5, PidsCurrent: 6},
+		{CPUPercentage: 50, IsInvalid: true},
+	})
+	assert.Equal(t, formatter.StatsEntry{
+		CPUPercentage: 30,
+		Memory:        400,
+		NetworkRx:     5,
+		NetworkTx:     7,
+		PidsCurrent:   9,
+	}, sum)
+
+	assert.True(t, sumStats([]formatter.StatsEntry{{IsInvalid: true}}).IsInvalid)
+	assert.True(t, sumStats(nil).IsInvalid)
+}
@@ -41,6 +41,11 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation(flagRollback, "version", []string{"1.25"})
 	flags.Bool("force", false, "Force update even if no changes require it")
 	flags.SetAnnotation("force", "version", []string{"1.25"})
+	flags.Uint64(flagCanary, 0, "Update this number of tasks first, and wait for them to be running before updating the others")
+	flags.SetAnnotation(flagCanary, "version", []string{"1.29"})
+	flags.Bool(flagCanaryPause, false, "Pause the update once the canary tasks are running, until it is promoted with `docker service promote`")
+	flags.SetAnnotation(flagCanaryPause, "version", []string{"1.29"})
+	flags.StringVar(&options.specFile, flagSpec, "", "Merge a full or partial service spec from a JSON or YAML file")
 	addServiceFlags(flags, options, nil)
 
 	flags.Var(newListOptsVar(), flagEnvRemove, "Remove an environment variable")
@@ -94,6 +99,10 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation(flagDNSSearchAdd, "version", []string{"1.25"})
 	flags.Var(&options.hosts, flagHostAdd, "Add or update a custom host-to-IP mapping (host:ip)")
 	flags.SetAnnotation(flagHostAdd, "version", []string{"1.25"})
+	flags.Var(&options.resources.resGenericResources, flagGenericResourcesAdd, "Add or update a user defined resource reservation (kind=count)")
+	flags.SetAnnotation(flagGenericResourcesAdd, "version", []string{"1.32"})
+	flags.Var(newListOptsVar(), flagGenericResourcesRemove, "Remove a user defined resource reservation by its kind")
+	flags.SetAnnotation(flagGenericResourcesRemove, "version", []string{"1.32"})
 
 	return cmd
 }
@@ -150,6 +159,30 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 		} else {
 			serverSideRollback = true
 		}
+
+		previous, err := canaryRollbackSpec(service)
+		if err != nil {
+			return err
+		}
+		if previous != nil {
+			clientSideRollback, serverSideRollback = false, false
+			spec = previous
+		}
+	}
+
+	canaries, err := flags.GetUint64(flagCanary)
+	if err != nil {
+		return err
+	}
+	canaryPause, err := flags.GetBool(flagCanaryPause)
+	if err != nil {
+		return err
+	}
+	if canaryPause && canaries == 0 {
+		return errors.Errorf("--%s can only be used with --%s", flagCanaryPause, flagCanary)
+	}
+	if _, ok := service.Spec.Labels[canaryLabel]; ok && !rollback {
+		return errors.Errorf("service %s has a canary update in progress, promote it with `docker service promote` or revert it with `docker service rollback` first", serviceID)
 	}
 
 	updateOpts := types.ServiceUpdateOptions{}
@@ -157,12 +190,27 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 		updateOpts.Rollback = "previous"
 	}
 
+	if options.specFile != "" {
+		merged, err := mergeUpdateSpec(options.specFile, service.Spec)
+		if err != nil {
+			return err
+		}
+		spec = &merged
+	}
+
 	err = updateService(ctx, apiClient, flags, spec)
 	if err != nil {
 		return err
 	}
 
-	if flags.Changed("image") {
+	if !serverSideRollback {
+		if err := CheckPlacement(ctx, dockerCli, *spec, options.strict); err != nil {
+			return err
+		}
+	}
+
+	imageChanged := flags.Changed("image") || spec.TaskTemplate.ContainerSpec.Image != service.Spec.TaskTemplate.ContainerSpec.Image
+	if imageChanged {
 		if err := resolveServiceImageDigestContentTrust(dockerCli, spec); err != nil {
 			return err
 		}
@@ -185,6 +233,16 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 
 	spec.TaskTemplate.ContainerSpec.Configs = updatedConfigs
 
+	if options.specFile != "" {
+		writeSpecDiff(ctx, dockerCli, service.Spec, *spec)
+	}
+
+	if canaries > 0 {
+		if err := startCanary(spec, canaries); err != nil {
+			return err
+		}
+	}
+
 	// only send auth if flag was set
 	sendAuth, err := flags.GetBool(flagRegistryAuth)
 	if err != nil {
@@ -216,6 +274,10 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 
 	fmt.Fprintf(dockerCli.Out(), "%s\n", serviceID)
 
+	if canaries > 0 {
+		return waitOnCanaries(ctx, dockerCli, serviceID, canaries, canaryPause, options)
+	}
+
 	if options.detach || versions.LessThan(apiClient.ClientVersion(), "1.29") {
 		return nil
 	}
@@ -223,6 +285,30 @@ func runUpdate(dockerCli command.Cli, flags *pflag.FlagSet, options *serviceOpti
 	return waitOnService(ctx, dockerCli, serviceID, options.quiet)
 }
 
+// mergeUpdateSpec merges a full or partial spec in a file into the current
+// spec of a service. Fields that are set in the file replace the current
+// values, mappings such as labels are merged key by key.
+func mergeUpdateSpec(filename string, current swarm.ServiceSpec) (swarm.ServiceSpec, error) {
+	fields, err := readSpecFile(filename)
+	if err != nil {
+		return current, err
+	}
+	currentFields, err := specToFields(current)
+	if err != nil {
+		return current, err
+	}
+	mergeSpecFields(currentFields, fields)
+
+	spec, err := specFromFields(currentFields)
+	if err != nil {
+		return current, err
+	}
+	if spec.Name != current.Name {
+		return current, errors.Errorf("invalid service spec: the name of service %s can't be changed to %s", current.Name, spec.Name)
+	}
+	return spec, validateServiceSpec(spec)
+}
+
 // nolint: gocyclo
 func updateService(ctx context.Context, apiClient client.NetworkAPIClient, flags *pflag.FlagSet, spec *swarm.ServiceSpec) error {
 	updateString := func(flag string, field *string) {
@@ -298,10 +384,23 @@ func updateService(ctx context.Context, apiClient client.NetworkAPIClient, flags
 		updateInt64Value(flagLimitMemory, &task.Resources.Limits.MemoryBytes)
 	}
 	if flags.Changed(flagReserveCPU) || flags.Changed(flagReserveMemory) {
-		taskResources().Reservations = &swarm.Resources{}
+		// Keep the generic resources, which are updated separately
+		var generic []swarm.GenericResource
+		if task.Resources != nil && task.Resources.Reservations != nil {
+			generic = task.Resources.Reservations.GenericResources
+		}
+		taskResources().Reservations = &swarm.Resources{GenericResources: generic}
 		updateInt64Value(flagReserveCPU, &task.Resources.Reservations.NanoCPUs)
 		updateInt64Value(flagReserveMemory, &task.Resources.Reservations.MemoryBytes)
 	}
+	if anyChanged(flags, flagGenericResourcesAdd, flagGenericResourcesRemove) {
+		if taskResources().Reservations == nil {
+			task.Resources.Reservations = &swarm.Resources{}
+		}
+		if err := updateGenericResources(flags, &task.Resources.Reservations.GenericResources); err != nil {
+			return err
+		}
+	}
 
 	updateDurationOpt(flagStopGracePeriod, &cspec.StopGracePeriod)
 
@@ -459,6 +558,34 @@ func anyChanged(flags *pflag.FlagSet, fields ...string) bool {
 	return false
 }
 
+func updateGenericResources(flags *pflag.FlagSet, field *[]swarm.GenericResource) error {
+	resources, err := buildGenericResourceMap(*field)
+	if err != nil {
+		return err
+	}
+
+	if flags.Changed(flagGenericResourcesRemove) {
+		toRemove := flags.Lookup(flagGenericResourcesRemove).Value.(*opts.ListOpts).GetAll()
+		for _, kind := range toRemove {
+			delete(resources, kind)
+		}
+	}
+
+	if flags.Changed(flagGenericResourcesAdd) {
+		values := flags.Lookup(flagGenericResourcesAdd).Value.(*opts.ListOpts).GetAll()
+		toAdd, err := ParseGenericResources(values)
+		if err != nil {
+			return err
+		}
+		for _, res := range toAdd {
+			resources[res.DiscreteResourceSpec.Kind] = res
+		}
+	}
+
+	*field = buildGenericResourceList(resources)
+	return nil
+}
+
 func updatePlacementConstraints(flags *pflag.FlagSet, placement *swarm.Placement) {
 	if flags.Changed(flagConstraintAdd) {
 		values := flags.Lookup(flagConstraintAdd).Value.(*opts.ListOpts).GetAll()
@@ -506,3 +506,39 @@ func TestUpdateStopSignal(t *testing.T) {
 	updateService(nil, nil, flags, spec)
 	assert.Equal(t, "SIGWINCH", cspec.StopSignal)
 }
+
+func TestUpdateGenericResources(t *testing.T) {
+	spec := &swarm.ServiceSpec{
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{},
+			Resources: &swarm.ResourceRequirements{
+				Reservations: &swarm.Resources{
+					GenericResources: []swarm.GenericResource{
+						{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "GPU", Value: 1}},
+						{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "SSD", Value: 1}},
+					},
+				},
+			},
+		},
+	}
+
+	flags := newUpdateCommand(nil).Flags()
+	flags.Set("generic-resource-add", "GPU=2")
+	flags.Set("generic-resource-add", "FPGA=1")
+	flags.Set("generic-resource-rm", "SSD")
+	flags.Set("reserve-memory", "1GB")
+	require.NoError(t, updateService(nil, nil, flags, spec))
+
+	reservations := spec.TaskTemplate.Resources.Reservations
+	assert.Equal(t, int64(1<<30), reservations.MemoryBytes)
+	assert.Equal(t, []swarm.GenericResource{
+		{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "FPGA", Value: 1}},
+		{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "GPU", Value: 2}},
+	}, reservations.GenericResources)
+
+	// Updating the reserved memory keeps the generic resources
+	flags = newUpdateCommand(nil).Flags()
+	flags.Set("reserve-memory", "2GB")
+	require.NoError(t, updateService(nil, nil, flags, spec))
+	assert.Len(t, spec.TaskTemplate.Resources.Reservations.GenericResources, 2)
+}
@@ -34,6 +34,9 @@ type fakeClient struct {
 	nodeListFunc       func(options types.NodeListOptions) ([]swarm.Node, error)
 	taskListFunc       func(options types.TaskListOptions) ([]swarm.Task, error)
 	nodeInspectWithRaw func(ref string) (swarm.Node, []byte, error)
+	networkCreateFunc  func(name string, options types.NetworkCreate) (types.NetworkCreateResponse, error)
+
+	serviceInspectWithRawFunc func(serviceID string) (swarm.Service, []byte, error)
 
 	serviceUpdateFunc func(serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error)
 
@@ -54,6 +57,10 @@ func (cli *fakeClient) ClientVersion() string {
 	return cli.version
 }
 
+func (cli *fakeClient) Info(ctx context.Context) (types.Info, error) {
+	return types.Info{Swarm: swarm.Info{ControlAvailable: true}}, nil
+}
+
 func (cli *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
 	if cli.serviceListFunc != nil {
 		return cli.serviceListFunc(options)
@@ -128,6 +135,13 @@ func (cli *fakeClient) NodeList(ctx context.Context, options types.NodeListOptio
 	return []swarm.Node{}, nil
 }
 
+func (cli *fakeClient) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	if cli.networkCreateFunc != nil {
+		return cli.networkCreateFunc(name, options)
+	}
+	return types.NetworkCreateResponse{}, nil
+}
+
 func (cli *fakeClient) NodeInspectWithRaw(ctx context.Context, ref string) (swarm.Node, []byte, error) {
 	if cli.nodeInspectWithRaw != nil {
 		return cli.nodeInspectWithRaw(ref)
@@ -135,6 +149,13 @@ func (cli *fakeClient) NodeInspectWithRaw(ctx context.Context, ref string) (swar
 	return swarm.Node{}, nil, nil
 }
 
+func (cli *fakeClient) ServiceInspectWithRaw(ctx context.Context, serviceID string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+	if cli.serviceInspectWithRawFunc != nil {
+		return cli.serviceInspectWithRawFunc(serviceID)
+	}
+	return cli.Client.ServiceInspectWithRaw(ctx, serviceID, options)
+}
+
 func (cli *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (types.ServiceUpdateResponse, error) {
 	if cli.serviceUpdateFunc != nil {
 		return cli.serviceUpdateFunc(serviceID, version, service, options)
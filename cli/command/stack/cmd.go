@@ -18,6 +18,7 @@ func NewStackCommand(dockerCli *command.DockerCli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		newDeployCommand(dockerCli),
+		newExportCommand(dockerCli),
 		newListCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		newServicesCommand(dockerCli),
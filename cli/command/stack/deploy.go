@@ -2,9 +2,12 @@ package stack
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/service"
 	"github.com/docker/cli/cli/compose/convert"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/versions"
@@ -27,6 +30,7 @@ type deployOptions struct {
 	resolveImage     string
 	sendRegistryAuth bool
 	prune            bool
+	strict           bool
 }
 
 func newDeployCommand(dockerCli command.Cli) *cobra.Command {
@@ -52,6 +56,7 @@ func newDeployCommand(dockerCli command.Cli) *cobra.Command {
 	flags.StringVar(&opts.resolveImage, "resolve-image", resolveImageAlways,
 		`Query the registry to resolve image digest and supported platforms ("`+resolveImageAlways+`"|"`+resolveImageChanged+`"|"`+resolveImageNever+`")`)
 	flags.SetAnnotation("resolve-image", "version", []string{"1.30"})
+	flags.BoolVar(&opts.strict, "strict", false, "Fail if no active node satisfies the placement constraints and preferences of a service")
 	return cmd
 }
 
@@ -121,3 +126,24 @@ func pruneServices(ctx context.Context, dockerCli command.Cli, namespace convert
 	}
 	return removeServices(ctx, dockerCli, pruneServices)
 }
+
+// checkPlacement checks the placement constraints and preferences of every
+// service of a stack, before any of them is deployed.
+func checkPlacement(ctx context.Context, dockerCli command.Cli, services map[string]swarm.ServiceSpec, strict bool) error {
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, name := range names {
+		if err := service.CheckPlacement(ctx, dockerCli, services[name], strict); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
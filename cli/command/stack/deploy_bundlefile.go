@@ -84,6 +84,9 @@ func deployBundle(ctx context.Context, dockerCli command.Cli, opts deployOptions
 		services[internalName] = serviceSpec
 	}
 
+	if err := checkPlacement(ctx, dockerCli, services, opts.strict); err != nil {
+		return err
+	}
 	if err := createNetworks(ctx, dockerCli, namespace, networks); err != nil {
 		return err
 	}
@@ -69,6 +69,15 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts deployOption
 	if err := validateExternalNetworks(ctx, dockerCli.Client(), externalNetworks); err != nil {
 		return err
 	}
+
+	placementServices, err := convertPlacementServices(dockerCli, namespace, config)
+	if err != nil {
+		return err
+	}
+	if err := checkPlacement(ctx, dockerCli, placementServices, opts.strict); err != nil {
+		return err
+	}
+
 	if err := createNetworks(ctx, dockerCli, namespace, networks); err != nil {
 		return err
 	}
@@ -96,6 +105,22 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts deployOption
 	return deployServices(ctx, dockerCli, services, namespace, opts.sendRegistryAuth, opts.resolveImage)
 }
 
+// convertPlacementServices converts the services of a Compose file to check
+// their placement before anything is created. The references to secrets and
+// configs are left out, as they can only be resolved once the secrets and
+// configs of the stack are created.
+func convertPlacementServices(dockerCli command.Cli, namespace convert.Namespace, config *composetypes.Config) (map[string]swarm.ServiceSpec, error) {
+	services := make(map[string]swarm.ServiceSpec)
+	for _, service := range config.Services {
+		spec, err := convert.Service(dockerCli.Client().ClientVersion(), namespace, service, config.Networks, config.Volumes, nil, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %s", service.Name)
+		}
+		services[service.Name] = spec
+	}
+	return services, nil
+}
+
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
 	serviceNetworks := map[string]struct{}{}
 	for _, serviceConfig := range serviceConfigs {
@@ -6,14 +6,18 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/network"
 	"github.com/docker/cli/internal/test/testutil"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/gotestyourself/gotestyourself/fs"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
 )
 
 func TestGetConfigDetails(t *testing.T) {
@@ -103,3 +107,32 @@ func TestValidateExternalNetworks(t *testing.T) {
 		}
 	}
 }
+
+func TestDeployComposeStrictPlacement(t *testing.T) {
+	content := `
+version: "3.0"
+services:
+  db:
+    image: postgres
+    networks: [backend]
+    deploy:
+      placement:
+        constraints: [node.labels.zone == eu-west]
+networks:
+  backend: {}
+`
+	file := fs.NewFile(t, "test-deploy-strict-placement", fs.WithContent(content))
+	defer file.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{
+		nodeListFunc: func(options types.NodeListOptions) ([]swarm.Node, error) {
+			return []swarm.Node{*Node(NodeLabels(map[string]string{"zone": "us-east"}))}, nil
+		},
+		networkCreateFunc: func(name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+			t.Fatalf("network %s should not be created", name)
+			return types.NetworkCreateResponse{}, nil
+		},
+	})
+	opts := deployOptions{composefile: file.Path(), namespace: "app", strict: true}
+	testutil.ErrorContains(t, deployCompose(context.Background(), cli, opts), "no active node satisfies the placement constraints of service app_db")
+}
@@ -0,0 +1,122 @@
+package stack
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compose/convert"
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type exportOptions struct {
+	namespace string
+	services  []string
+	output    string
+}
+
+func newExportCommand(dockerCli command.Cli) *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS] STACK",
+		Short: "Export the services of a stack to a Compose file",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.namespace = args[0]
+			return runStackExport(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	return cmd
+}
+
+// NewServiceExportCommand returns a command for `docker service export`. It
+// is part of the stack package, as the conversion to a Compose file is shared
+// with `docker stack export`.
+func NewServiceExportCommand(dockerCli command.Cli) *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS] SERVICE [SERVICE...]",
+		Short: "Export one or more services to a Compose file",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.services = args
+			return runServiceExport(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	return cmd
+}
+
+func runStackExport(dockerCli command.Cli, opts exportOptions) error {
+	ctx := context.Background()
+
+	services, err := getServices(ctx, dockerCli.Client(), opts.namespace)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return errors.Errorf("nothing found in stack: %s", opts.namespace)
+	}
+	return writeComposeFile(ctx, dockerCli, convert.NewNamespace(opts.namespace), services, opts.output)
+}
+
+func runServiceExport(dockerCli command.Cli, opts exportOptions) error {
+	ctx := context.Background()
+	client := dockerCli.Client()
+
+	var services []swarm.Service
+	for _, name := range opts.services {
+		service, _, err := client.ServiceInspectWithRaw(ctx, name, types.ServiceInspectOptions{})
+		if err != nil {
+			return err
+		}
+		services = append(services, service)
+	}
+	return writeComposeFile(ctx, dockerCli, convert.NewNamespace(""), services, opts.output)
+}
+
+// writeComposeFile converts the services to a Compose file, which is written
+// to output, or to STDOUT if it is empty. The fields that can't be exported
+// are reported on STDERR.
+func writeComposeFile(ctx context.Context, dockerCli command.Cli, namespace convert.Namespace, services []swarm.Service, output string) error {
+	networks, err := dockerCli.Client().NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return err
+	}
+	networkNames := make(map[string]string, len(networks))
+	for _, network := range networks {
+		networkNames[network.ID] = network.Name
+	}
+
+	specs := make([]swarm.ServiceSpec, 0, len(services))
+	for _, service := range services {
+		specs = append(specs, service.Spec)
+	}
+	config, warnings := convert.ServiceSpecs(namespace, specs, networkNames)
+	for _, warning := range warnings {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", warning)
+	}
+
+	data, err := loader.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err := dockerCli.Out().Write(data)
+		return err
+	}
+	return ioutil.WriteFile(output, data, 0644)
+}
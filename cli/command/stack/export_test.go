@@ -0,0 +1,149 @@
+package stack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackExportErrors(t *testing.T) {
+	testCases := []struct {
+		args            []string
+		serviceListFunc func(options types.ServiceListOptions) ([]swarm.Service, error)
+		networkListFunc func(options types.NetworkListOptions) ([]types.NetworkResource, error)
+		expectedError   string
+	}{
+		{
+			expectedError: "requires exactly 1 argument",
+		},
+		{
+			args:          []string{"foo"},
+			expectedError: "nothing found in stack: foo",
+		},
+		{
+			args: []string{"foo"},
+			serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+				return nil, errors.Errorf("error getting services")
+			},
+			expectedError: "error getting services",
+		},
+		{
+			args: []string{"foo"},
+			serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+				return []swarm.Service{serviceFromName("foo_web")}, nil
+			},
+			networkListFunc: func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+				return nil, errors.Errorf("error getting networks")
+			},
+			expectedError: "error getting networks",
+		},
+	}
+
+	for _, tc := range testCases {
+		cmd := newExportCommand(test.NewFakeCli(&fakeClient{
+			serviceListFunc: tc.serviceListFunc,
+			networkListFunc: tc.networkListFunc,
+		}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		testutil.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestStackExport(t *testing.T) {
+	replicas := uint64(2)
+	cli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{{
+				ID: "ID-foo_web",
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{
+						Name:   "foo_web",
+						Labels: map[string]string{"com.docker.stack.namespace": "foo"},
+					},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{Image: "nginx:alpine"},
+						Networks:      []swarm.NetworkAttachmentConfig{{Target: "ID-foo_back"}},
+					},
+					Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+				},
+			}}, nil
+		},
+		networkListFunc: func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+			return []types.NetworkResource{networkFromName("foo_back")}, nil
+		},
+	})
+	cmd := newExportCommand(cli)
+	cmd.SetArgs([]string{"foo"})
+	require.NoError(t, cmd.Execute())
+
+	expected := `version: "3.5"
+services:
+  web:
+    deploy:
+      replicas: 2
+    image: nginx:alpine
+    networks:
+      back: null
+networks:
+  back: null
+`
+	assert.Equal(t, expected, cli.OutBuffer().String())
+}
+
+func TestServiceExportToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "service-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cli := test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			return swarm.Service{
+				ID: "ID-" + serviceID,
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: serviceID},
+					TaskTemplate: swarm.TaskSpec{
+						ContainerSpec: &swarm.ContainerSpec{Image: "redis"},
+					},
+				},
+			}, nil, nil
+		},
+		networkListFunc: func(options types.NetworkListOptions) ([]types.NetworkResource, error) {
+			return nil, nil
+		},
+	})
+	output := filepath.Join(dir, "docker-compose.yml")
+	cmd := NewServiceExportCommand(cli)
+	cmd.SetArgs([]string{"--output", output, "redis"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "", cli.OutBuffer().String())
+
+	data, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+	expected := `version: "3.5"
+services:
+  redis:
+    image: redis
+`
+	assert.Equal(t, expected, string(data))
+}
+
+func TestServiceExportErrors(t *testing.T) {
+	cmd := NewServiceExportCommand(test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(serviceID string) (swarm.Service, []byte, error) {
+			return swarm.Service{}, nil, errors.Errorf("no such service: %s", serviceID)
+		},
+	}))
+	cmd.SetArgs([]string{"foo"})
+	cmd.SetOutput(ioutil.Discard)
+	testutil.ErrorContains(t, cmd.Execute(), "no such service: foo")
+}
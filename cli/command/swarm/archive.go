@@ -0,0 +1,136 @@
+package swarm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+)
+
+// archiveVersion is the version of the format of swarm archives. It is
+// increased when the format changes in a way older versions can't read.
+const archiveVersion = 1
+
+// swarmArchive holds the cluster objects of a swarm, as they are written to an
+// archive by `docker swarm export`. Objects reference each other by name, as
+// their IDs are not kept when they are imported.
+type swarmArchive struct {
+	Manifest archiveManifest
+	Swarm    swarm.Spec
+	Networks []types.NetworkCreateRequest
+	Configs  []swarm.ConfigSpec
+	// Secrets only hold the metadata of secrets, without their data
+	Secrets  []swarm.SecretSpec
+	Nodes    []archiveNode
+	Services []swarm.ServiceSpec
+}
+
+type archiveManifest struct {
+	Version int
+	Created time.Time
+	SwarmID string
+}
+
+// archiveNode holds the labels of a node, which are restored on the node with
+// the same hostname.
+type archiveNode struct {
+	ID       string
+	Hostname string
+	Role     swarm.NodeRole
+	Labels   map[string]string `json:",omitempty"`
+}
+
+// archiveFiles returns the files of an archive and the values they hold, in
+// the order they are written.
+func (a *swarmArchive) archiveFiles() []struct {
+	name  string
+	value interface{}
+} {
+	return []struct {
+		name  string
+		value interface{}
+	}{
+		{"manifest.json", &a.Manifest},
+		{"swarm.json", &a.Swarm},
+		{"networks.json", &a.Networks},
+		{"configs.json", &a.Configs},
+		{"secrets.json", &a.Secrets},
+		{"nodes.json", &a.Nodes},
+		{"services.json", &a.Services},
+	}
+}
+
+// writeArchive writes a swarm archive as a gzipped tar of JSON files.
+func writeArchive(w io.Writer, a *swarmArchive) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, file := range a.archiveFiles() {
+		data, err := json.MarshalIndent(file.value, "", "    ")
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: a.Manifest.Created,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readArchive reads a swarm archive that was written by writeArchive.
+func readArchive(r io.Reader) (*swarmArchive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid swarm archive")
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid swarm archive")
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid swarm archive")
+		}
+		files[header.Name] = data
+	}
+
+	a := &swarmArchive{}
+	for _, file := range a.archiveFiles() {
+		data, ok := files[file.name]
+		if !ok {
+			return nil, errors.Errorf("invalid swarm archive: %s is missing", file.name)
+		}
+		if err := json.NewDecoder(bytes.NewReader(data)).Decode(file.value); err != nil {
+			return nil, errors.Wrapf(err, "invalid swarm archive: %s", file.name)
+		}
+		if file.name == "manifest.json" && a.Manifest.Version > archiveVersion {
+			return nil, errors.Errorf("unsupported swarm archive version %d: this client supports version %d", a.Manifest.Version, archiveVersion)
+		}
+	}
+	return a, nil
+}
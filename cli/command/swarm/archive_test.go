@@ -0,0 +1,45 @@
+package swarm
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	archive := &swarmArchive{
+		Manifest: archiveManifest{
+			Version: archiveVersion,
+			Created: time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC),
+			SwarmID: "swarm-id",
+		},
+		Swarm:    swarm.Spec{Annotations: swarm.Annotations{Name: "default"}},
+		Networks: []types.NetworkCreateRequest{{Name: "backend", NetworkCreate: types.NetworkCreate{Driver: "overlay"}}},
+		Configs:  []swarm.ConfigSpec{{Annotations: swarm.Annotations{Name: "nginx.conf"}, Data: []byte("server {}")}},
+		Secrets:  []swarm.SecretSpec{{Annotations: swarm.Annotations{Name: "db_password"}}},
+		Nodes:    []archiveNode{{ID: "node-id", Hostname: "node-1", Role: swarm.NodeRoleWorker, Labels: map[string]string{"zone": "a"}}},
+		Services: []swarm.ServiceSpec{{Annotations: swarm.Annotations{Name: "web"}}},
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeArchive(buf, archive))
+	read, err := readArchive(buf)
+	require.NoError(t, err)
+	assert.Equal(t, archive, read)
+}
+
+func TestReadArchiveErrors(t *testing.T) {
+	_, err := readArchive(bytes.NewBufferString("not an archive"))
+	testutil.ErrorContains(t, err, "invalid swarm archive")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeArchive(buf, &swarmArchive{Manifest: archiveManifest{Version: archiveVersion + 1}}))
+	_, err = readArchive(buf)
+	testutil.ErrorContains(t, err, "unsupported swarm archive version 2")
+}
@@ -1,15 +1,23 @@
 package swarm
 
 import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/swarm/progress"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -24,6 +32,9 @@ type caOptions struct {
 	rotate     bool
 	detach     bool
 	quiet      bool
+	status     bool
+	waitAll    bool
+	timeout    time.Duration
 }
 
 func newCACommand(dockerCli command.Cli) *cobra.Command {
@@ -47,6 +58,9 @@ func newCACommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.detach, "detach", "d", false, "Exit immediately instead of waiting for the root rotation to converge")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress progress output")
+	flags.BoolVar(&opts.status, "status", false, "List the TLS certificate issuer and root rotation state of every node")
+	flags.BoolVar(&opts.waitAll, "wait-all-nodes", false, "Fail if the root rotation doesn't converge on all the nodes within the timeout")
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Minute, "Time to wait for the root rotation to converge with --wait-all-nodes")
 	return cmd
 }
 
@@ -54,11 +68,38 @@ func runCA(dockerCli command.Cli, flags *pflag.FlagSet, opts caOptions) error {
 	client := dockerCli.Client()
 	ctx := context.Background()
 
+	if opts.status {
+		for _, f := range []string{flagCACert, flagCAKey, flagCertExpiry, flagExternalCA} {
+			if flags.Changed(f) {
+				return errors.Errorf("--status can't be used with --%s", f)
+			}
+		}
+	}
+	switch {
+	case opts.status && opts.rotate:
+		return errors.New("--status can't be used with --rotate")
+	case opts.waitAll && !opts.rotate:
+		return errors.New("--wait-all-nodes requires the --rotate flag")
+	case opts.waitAll && opts.detach:
+		return errors.New("--wait-all-nodes can't be used with --detach")
+	case flags.Changed("timeout") && !opts.waitAll:
+		return errors.New("--timeout requires the --wait-all-nodes flag")
+	}
+
 	swarmInspect, err := client.SwarmInspect(ctx)
 	if err != nil {
 		return err
 	}
 
+	if opts.status {
+		nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+		if err != nil {
+			return err
+		}
+		displayRotationStatus(dockerCli.Out(), swarmInspect, nodes)
+		return nil
+	}
+
 	if !opts.rotate {
 		for _, f := range []string{flagCACert, flagCAKey, flagCertExpiry, flagExternalCA} {
 			if flags.Changed(f) {
@@ -99,6 +140,12 @@ func updateSwarmSpec(spec *swarm.Spec, flags *pflag.FlagSet, opts caOptions) {
 
 func attach(ctx context.Context, dockerCli command.Cli, opts caOptions) error {
 	client := dockerCli.Client()
+	if opts.waitAll {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
 	errChan := make(chan error, 1)
 	pipeReader, pipeWriter := io.Pipe()
 
@@ -106,18 +153,28 @@ func attach(ctx context.Context, dockerCli command.Cli, opts caOptions) error {
 		errChan <- progress.RootRotationProgress(ctx, client, pipeWriter)
 	}()
 
+	var err error
 	if opts.quiet {
 		go io.Copy(ioutil.Discard, pipeReader)
-		return <-errChan
+	} else {
+		err = jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil)
 	}
-
-	err := jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil)
 	if err == nil {
 		err = <-errChan
 	}
-	if err != nil {
+	switch {
+	case err == progress.ErrInterrupted && opts.waitAll:
+		return laggingNodesError(client, "interrupted before the root rotation converged on all the nodes")
+	case err == progress.ErrInterrupted:
+		// The rotation continues in the background
+	case err != nil && opts.waitAll && ctx.Err() == context.DeadlineExceeded:
+		return laggingNodesError(client, fmt.Sprintf("timed out after %s waiting for the root rotation to converge on all the nodes", opts.timeout))
+	case err != nil:
 		return err
 	}
+	if opts.quiet {
+		return nil
+	}
 
 	swarmInspect, err := client.SwarmInspect(ctx)
 	if err != nil {
@@ -133,3 +190,92 @@ func displayTrustRoot(out io.Writer, info swarm.Swarm) error {
 	fmt.Fprintln(out, strings.TrimSpace(info.ClusterInfo.TLSInfo.TrustRoot))
 	return nil
 }
+
+// Root rotation states of a node.
+const (
+	rotationDone        = "rotated"
+	rotationPendingCert = "pending certificate"
+	rotationPendingRoot = "pending trust root"
+)
+
+// nodeRotationState returns the state of the root rotation on a node: a node
+// first gets a TLS certificate issued by the new root CA, and then trusts the
+// new root CA once every node has a new certificate.
+func nodeRotationState(desired swarm.TLSInfo, node swarm.Node) string {
+	tlsInfo := node.Description.TLSInfo
+	if !bytes.Equal(tlsInfo.CertIssuerPublicKey, desired.CertIssuerPublicKey) ||
+		!bytes.Equal(tlsInfo.CertIssuerSubject, desired.CertIssuerSubject) {
+		return rotationPendingCert
+	}
+	if tlsInfo.TrustRoot != desired.TrustRoot {
+		return rotationPendingRoot
+	}
+	return rotationDone
+}
+
+// displayRotationStatus prints the issuer of the TLS certificate of every
+// node, the expiry of the root CA it trusts, and its root rotation state. The
+// API doesn't report the expiry of the TLS certificate of a node, so the
+// expiry that is shown is the one of the root CA certificate.
+func displayRotationStatus(out io.Writer, info swarm.Swarm, nodes []swarm.Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Description.Hostname < nodes[j].Description.Hostname })
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tHOSTNAME\tSTATUS\tCERTIFICATE ISSUER\tTRUST ROOT EXPIRES\tROTATION")
+	for _, node := range nodes {
+		tlsInfo := node.Description.TLSInfo
+		expires := "unknown"
+		if expiry, err := certificatesExpiry([]byte(tlsInfo.TrustRoot)); err == nil {
+			expires = expiry.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			node.ID,
+			node.Description.Hostname,
+			command.PrettyPrint(node.Status.State),
+			issuerName(tlsInfo.CertIssuerSubject),
+			expires,
+			nodeRotationState(info.ClusterInfo.TLSInfo, node),
+		)
+	}
+	w.Flush()
+	if info.ClusterInfo.RootRotationInProgress {
+		fmt.Fprintln(out, "\nA root rotation is in progress.")
+	}
+}
+
+// issuerName returns the distinguished name of the issuer of a certificate,
+// from its raw subject.
+func issuerName(subject []byte) string {
+	var rdns pkix.RDNSequence
+	if rest, err := asn1.Unmarshal(subject, &rdns); err != nil || len(rest) > 0 {
+		return "unknown"
+	}
+	var name pkix.Name
+	name.FillFromRDNSequence(&rdns)
+	return name.String()
+}
+
+// laggingNodesError returns an error with the given message that lists the
+// nodes on which the root rotation didn't converge.
+func laggingNodesError(client client.APIClient, message string) error {
+	ctx := context.Background()
+	info, err := client.SwarmInspect(ctx)
+	if err != nil {
+		return err
+	}
+	nodes, err := client.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var lagging []string
+	for _, node := range nodes {
+		state := nodeRotationState(info.ClusterInfo.TLSInfo, node)
+		if state == rotationDone {
+			continue
+		}
+		lagging = append(lagging, fmt.Sprintf("  %s (%s): %s, node is %s", node.Description.Hostname, node.ID, state, node.Status.State))
+	}
+	sort.Strings(lagging)
+	return errors.Errorf("%s; lagging nodes:\n%s", message, strings.Join(lagging, "\n"))
+}
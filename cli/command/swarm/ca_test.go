@@ -2,6 +2,8 @@ package swarm
 
 import (
 	"bytes"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -10,6 +12,7 @@ import (
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/testutil"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/gotestyourself/gotestyourself/golden"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +35,20 @@ func swarmSpecWithFullCAConfig() *swarm.Spec {
 	}
 }
 
+// testCACert is a root CA certificate, for the flags that read one
+const testCACert = `
+-----BEGIN CERTIFICATE-----
+MIIBajCCARCgAwIBAgIUe0+jYWhxN8fFOByC7yveIYgvx1kwCgYIKoZIzj0EAwIw
+EzERMA8GA1UEAxMIc3dhcm0tY2EwHhcNMTcwNjI3MTUxNDAwWhcNMzcwNjIyMTUx
+NDAwWjATMREwDwYDVQQDEwhzd2FybS1jYTBZMBMGByqGSM49AgEGCCqGSM49AwEH
+A0IABGgbOZLd7b4b262+6m4ignIecbAZKim6djNiIS1Kl5IHciXYn7gnSpsayjn7
+GQABpgkdPeM9TEQowmtR1qSnORujQjBAMA4GA1UdDwEB/wQEAwIBBjAPBgNVHRMB
+Af8EBTADAQH/MB0GA1UdDgQWBBQ6Rtcn823/fxRZyheRDFpDzuBMpTAKBggqhkjO
+PQQDAgNIADBFAiEAqD3Kb2rgsy6NoTk+zEgcUi/aGBCsvQDG3vML1PXN8j0CIBjj
+4nDj+GmHXcnKa8wXx70Z8OZEpRQIiKDDLmcXuslp
+-----END CERTIFICATE-----
+`
+
 func TestDisplayTrustRootNoRoot(t *testing.T) {
 	buffer := new(bytes.Buffer)
 	err := displayTrustRoot(buffer, swarm.Swarm{})
@@ -43,18 +60,7 @@ func TestDisplayTrustRootInvalidFlags(t *testing.T) {
 	tmpfile, err := ioutil.TempFile("", "pemfile")
 	assert.NoError(t, err)
 	defer os.Remove(tmpfile.Name())
-	tmpfile.Write([]byte(`
------BEGIN CERTIFICATE-----
-MIIBajCCARCgAwIBAgIUe0+jYWhxN8fFOByC7yveIYgvx1kwCgYIKoZIzj0EAwIw
-EzERMA8GA1UEAxMIc3dhcm0tY2EwHhcNMTcwNjI3MTUxNDAwWhcNMzcwNjIyMTUx
-NDAwWjATMREwDwYDVQQDEwhzd2FybS1jYTBZMBMGByqGSM49AgEGCCqGSM49AwEH
-A0IABGgbOZLd7b4b262+6m4ignIecbAZKim6djNiIS1Kl5IHciXYn7gnSpsayjn7
-GQABpgkdPeM9TEQowmtR1qSnORujQjBAMA4GA1UdDwEB/wQEAwIBBjAPBgNVHRMB
-Af8EBTADAQH/MB0GA1UdDgQWBBQ6Rtcn823/fxRZyheRDFpDzuBMpTAKBggqhkjO
-PQQDAgNIADBFAiEAqD3Kb2rgsy6NoTk+zEgcUi/aGBCsvQDG3vML1PXN8j0CIBjj
-4nDj+GmHXcnKa8wXx70Z8OZEpRQIiKDDLmcXuslp
------END CERTIFICATE-----
-`))
+	tmpfile.Write([]byte(testCACert))
 	tmpfile.Close()
 
 	errorTestCases := [][]string{
@@ -101,6 +107,31 @@ PQQDAgNIADBFAiEAqD3Kb2rgsy6NoTk+zEgcUi/aGBCsvQDG3vML1PXN8j0CIBjj
 	}
 }
 
+func TestRunCAStatusInvalidFlags(t *testing.T) {
+	// we need an actual PEMfile to test
+	tmpfile, err := ioutil.TempFile("", "pemfile")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+	tmpfile.Write([]byte(testCACert))
+	tmpfile.Close()
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"--ca-cert=" + tmpfile.Name()}, expectedError: "--status can't be used with --ca-cert"},
+		{args: []string{"--ca-key=" + tmpfile.Name()}, expectedError: "--status can't be used with --ca-key"},
+		{args: []string{"--cert-expiry=2160h0m0s"}, expectedError: "--status can't be used with --cert-expiry"},
+		{args: []string{"--external-ca=protocol=cfssl,url=https://some.com/https/url"}, expectedError: "--status can't be used with --external-ca"},
+	}
+	for _, tc := range testCases {
+		cmd := newCACommand(test.NewFakeCli(&fakeClient{}))
+		require.NoError(t, cmd.Flags().Parse(append(tc.args, "--status")))
+		cmd.SetOutput(ioutil.Discard)
+		assert.EqualError(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
 func TestDisplayTrustRoot(t *testing.T) {
 	buffer := new(bytes.Buffer)
 	trustRoot := "trustme"
@@ -153,3 +184,74 @@ func TestUpdateSwarmSpecFullFlags(t *testing.T) {
 	expected.CAConfig.NodeCertExpiry = 3 * time.Minute
 	assert.Equal(t, expected, spec)
 }
+
+func rootTLSInfo(t *testing.T, notAfter time.Time) swarm.TLSInfo {
+	trustRoot := rootCA(t, notAfter)
+	block, _ := pem.Decode([]byte(trustRoot))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return swarm.TLSInfo{
+		TrustRoot:           trustRoot,
+		CertIssuerSubject:   cert.RawSubject,
+		CertIssuerPublicKey: cert.RawSubjectPublicKeyInfo,
+	}
+}
+
+func rotationNodes(oldRoot, newRoot swarm.TLSInfo) []swarm.Node {
+	pendingRoot := newRoot
+	pendingRoot.TrustRoot = oldRoot.TrustRoot
+	return []swarm.Node{
+		{ID: "node-3-id", Description: swarm.NodeDescription{Hostname: "node-3", TLSInfo: oldRoot}, Status: swarm.NodeStatus{State: swarm.NodeStateDown}},
+		{ID: "node-1-id", Description: swarm.NodeDescription{Hostname: "node-1", TLSInfo: newRoot}, Status: swarm.NodeStatus{State: swarm.NodeStateReady}},
+		{ID: "node-2-id", Description: swarm.NodeDescription{Hostname: "node-2", TLSInfo: pendingRoot}, Status: swarm.NodeStatus{State: swarm.NodeStateReady}},
+	}
+}
+
+func TestDisplayRotationStatus(t *testing.T) {
+	oldRoot := rootTLSInfo(t, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	newRoot := rootTLSInfo(t, time.Date(2037, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	buffer := new(bytes.Buffer)
+	displayRotationStatus(buffer, swarm.Swarm{
+		ClusterInfo: swarm.ClusterInfo{TLSInfo: newRoot, RootRotationInProgress: true},
+	}, rotationNodes(oldRoot, newRoot))
+	golden.Assert(t, buffer.String(), "ca-rotation-status.golden")
+}
+
+func TestRunCAWaitAllNodesTimeout(t *testing.T) {
+	oldRoot := rootTLSInfo(t, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	newRoot := rootTLSInfo(t, time.Date(2037, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	cmd := newCACommand(
+		test.NewFakeCli(&fakeClient{
+			swarmInspectFunc: func() (swarm.Swarm, error) {
+				return swarm.Swarm{ClusterInfo: swarm.ClusterInfo{TLSInfo: newRoot, RootRotationInProgress: true}}, nil
+			},
+			nodeListFunc: func() ([]swarm.Node, error) {
+				return rotationNodes(oldRoot, newRoot), nil
+			},
+		}))
+	cmd.SetArgs([]string{"--rotate", "--wait-all-nodes", "--timeout", "50ms", "--quiet"})
+	cmd.SetOutput(ioutil.Discard)
+	assert.EqualError(t, cmd.Execute(), `timed out after 50ms waiting for the root rotation to converge on all the nodes; lagging nodes:
+  node-2 (node-2-id): pending trust root, node is ready
+  node-3 (node-3-id): pending certificate, node is down`)
+}
+
+func TestRunCAFlagErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"--status", "--rotate"}, expectedError: "--status can't be used with --rotate"},
+		{args: []string{"--wait-all-nodes"}, expectedError: "--wait-all-nodes requires the --rotate flag"},
+		{args: []string{"--rotate", "--wait-all-nodes", "--detach"}, expectedError: "--wait-all-nodes can't be used with --detach"},
+		{args: []string{"--rotate", "--timeout", "1m"}, expectedError: "--timeout requires the --wait-all-nodes flag"},
+	}
+	for _, tc := range testCases {
+		cmd := newCACommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetArgs(tc.args)
+		cmd.SetOutput(ioutil.Discard)
+		assert.EqualError(t, cmd.Execute(), tc.expectedError)
+	}
+}
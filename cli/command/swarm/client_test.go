@@ -14,10 +14,21 @@ type fakeClient struct {
 	swarmInspectFunc      func() (swarm.Swarm, error)
 	nodeInspectFunc       func() (swarm.Node, []byte, error)
 	swarmGetUnlockKeyFunc func() (types.SwarmUnlockKeyResponse, error)
-	swarmJoinFunc         func() error
+	swarmJoinFunc         func(req swarm.JoinRequest) error
 	swarmLeaveFunc        func() error
 	swarmUpdateFunc       func(swarm swarm.Spec, flags swarm.UpdateFlags) error
 	swarmUnlockFunc       func(req swarm.UnlockRequest) error
+	networkListFunc       func() ([]types.NetworkResource, error)
+	networkCreateFunc     func(name string, options types.NetworkCreate) error
+	configListFunc        func() ([]swarm.Config, error)
+	configCreateFunc      func(config swarm.ConfigSpec) error
+	secretListFunc        func() ([]swarm.Secret, error)
+	secretCreateFunc      func(secret swarm.SecretSpec) error
+	nodeListFunc          func() ([]swarm.Node, error)
+	nodeUpdateFunc        func(nodeID string, node swarm.NodeSpec) error
+	serviceListFunc       func() ([]swarm.Service, error)
+	serviceCreateFunc     func(service swarm.ServiceSpec) error
+	taskListFunc          func() ([]swarm.Task, error)
 }
 
 func (cli *fakeClient) Info(ctx context.Context) (types.Info, error) {
@@ -57,7 +68,7 @@ func (cli *fakeClient) SwarmGetUnlockKey(ctx context.Context) (types.SwarmUnlock
 
 func (cli *fakeClient) SwarmJoin(ctx context.Context, req swarm.JoinRequest) error {
 	if cli.swarmJoinFunc != nil {
-		return cli.swarmJoinFunc()
+		return cli.swarmJoinFunc(req)
 	}
 	return nil
 }
@@ -82,3 +93,80 @@ func (cli *fakeClient) SwarmUnlock(ctx context.Context, req swarm.UnlockRequest)
 	}
 	return nil
 }
+
+func (cli *fakeClient) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	if cli.networkListFunc != nil {
+		return cli.networkListFunc()
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	if cli.networkCreateFunc != nil {
+		return types.NetworkCreateResponse{}, cli.networkCreateFunc(name, options)
+	}
+	return types.NetworkCreateResponse{}, nil
+}
+
+func (cli *fakeClient) ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error) {
+	if cli.configListFunc != nil {
+		return cli.configListFunc()
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ConfigCreate(ctx context.Context, config swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+	if cli.configCreateFunc != nil {
+		return types.ConfigCreateResponse{}, cli.configCreateFunc(config)
+	}
+	return types.ConfigCreateResponse{}, nil
+}
+
+func (cli *fakeClient) SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error) {
+	if cli.secretListFunc != nil {
+		return cli.secretListFunc()
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) SecretCreate(ctx context.Context, secret swarm.SecretSpec) (types.SecretCreateResponse, error) {
+	if cli.secretCreateFunc != nil {
+		return types.SecretCreateResponse{}, cli.secretCreateFunc(secret)
+	}
+	return types.SecretCreateResponse{}, nil
+}
+
+func (cli *fakeClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
+	if cli.nodeListFunc != nil {
+		return cli.nodeListFunc()
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) NodeUpdate(ctx context.Context, nodeID string, version swarm.Version, node swarm.NodeSpec) error {
+	if cli.nodeUpdateFunc != nil {
+		return cli.nodeUpdateFunc(nodeID, node)
+	}
+	return nil
+}
+
+func (cli *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+	if cli.serviceListFunc != nil {
+		return cli.serviceListFunc()
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ServiceCreate(ctx context.Context, service swarm.ServiceSpec, options types.ServiceCreateOptions) (types.ServiceCreateResponse, error) {
+	if cli.serviceCreateFunc != nil {
+		return types.ServiceCreateResponse{}, cli.serviceCreateFunc(service)
+	}
+	return types.ServiceCreateResponse{}, nil
+}
+
+func (cli *fakeClient) TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+	if cli.taskListFunc != nil {
+		return cli.taskListFunc()
+	}
+	return nil, nil
+}
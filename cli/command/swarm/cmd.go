@@ -26,6 +26,9 @@ func NewSwarmCommand(dockerCli command.Cli) *cobra.Command {
 		newLeaveCommand(dockerCli),
 		newUnlockCommand(dockerCli),
 		newCACommand(dockerCli),
+		newDoctorCommand(dockerCli),
+		newExportCommand(dockerCli),
+		newImportCommand(dockerCli),
 	)
 	return cmd
 }
@@ -0,0 +1,490 @@
+package swarm
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// caExpiryWarning is how long before the root CA certificate expires the
+// doctor warns about it.
+const caExpiryWarning = 30 * 24 * time.Hour
+
+// checkStatus is the result of a check of the doctor.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "pass"
+	checkWarn checkStatus = "warn"
+	checkFail checkStatus = "fail"
+)
+
+// doctorCheck is a check of the doctor, or a problem it found.
+type doctorCheck struct {
+	Name    string
+	Status  checkStatus
+	Message string
+	Hint    string `json:",omitempty"`
+}
+
+// doctorReport holds the results of the checks of the doctor. Its status is
+// the worst status of its checks.
+type doctorReport struct {
+	Status checkStatus
+	Checks []doctorCheck
+}
+
+type doctorOptions struct {
+	format string
+}
+
+func newDoctorCommand(dockerCli command.Cli) *cobra.Command {
+	var opts doctorOptions
+
+	cmd := &cobra.Command{
+		Use:   "doctor [OPTIONS]",
+		Short: "Check the health of the swarm",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "Format the output using the given Go template")
+	return cmd
+}
+
+func runDoctor(dockerCli command.Cli, opts doctorOptions) error {
+	deadlines, err := loadJoinTokenDeadlines()
+	if err != nil {
+		return err
+	}
+	report, err := diagnose(context.Background(), dockerCli.Client(), deadlines, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if opts.format == "" {
+		printReport(dockerCli.Out(), report)
+	} else {
+		tmpl, err := templates.Parse(opts.format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64,
+				Status: "Template parsing error: " + err.Error()}
+		}
+		if err := tmpl.Execute(dockerCli.Out(), report); err != nil {
+			return err
+		}
+		dockerCli.Out().Write([]byte{'\n'})
+	}
+
+	if report.Status == checkFail {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// swarmState holds the objects of a swarm that the doctor checks.
+type swarmState struct {
+	swarm    swarm.Swarm
+	nodes    []swarm.Node
+	services []swarm.Service
+	tasks    []swarm.Task
+	networks []types.NetworkResource
+
+	// joinTokenDeadlines are the deadlines to rotate the join tokens that
+	// are recorded locally
+	joinTokenDeadlines []joinTokenDeadline
+}
+
+// diagnose reads the state of the swarm and runs every check on it.
+func diagnose(ctx context.Context, apiClient client.APIClient, deadlines []joinTokenDeadline, now time.Time) (doctorReport, error) {
+	var (
+		state = swarmState{joinTokenDeadlines: deadlines}
+		err   error
+	)
+	if state.swarm, err = apiClient.SwarmInspect(ctx); err != nil {
+		return doctorReport{}, err
+	}
+	if state.nodes, err = apiClient.NodeList(ctx, types.NodeListOptions{}); err != nil {
+		return doctorReport{}, err
+	}
+	if state.services, err = apiClient.ServiceList(ctx, types.ServiceListOptions{}); err != nil {
+		return doctorReport{}, err
+	}
+	filter := filters.NewArgs(filters.Arg("desired-state", string(swarm.TaskStateRunning)))
+	if state.tasks, err = apiClient.TaskList(ctx, types.TaskListOptions{Filters: filter}); err != nil {
+		return doctorReport{}, err
+	}
+	if state.networks, err = apiClient.NetworkList(ctx, types.NetworkListOptions{}); err != nil {
+		return doctorReport{}, err
+	}
+
+	report := doctorReport{Status: checkPass}
+	for _, check := range []func(swarmState, time.Time) []doctorCheck{
+		checkManagers,
+		checkCA,
+		checkJoinTokens,
+		checkNodes,
+		checkServices,
+		checkPendingTasks,
+		checkNetworks,
+	} {
+		for _, result := range check(state, now) {
+			report.Checks = append(report.Checks, result)
+			if result.Status == checkFail || (result.Status == checkWarn && report.Status == checkPass) {
+				report.Status = result.Status
+			}
+		}
+	}
+	return report, nil
+}
+
+func checkManagers(state swarmState, now time.Time) []doctorCheck {
+	const name = "managers"
+
+	var (
+		managers    int
+		unreachable []string
+	)
+	for _, node := range state.nodes {
+		if node.ManagerStatus == nil {
+			continue
+		}
+		managers++
+		if node.ManagerStatus.Reachability != swarm.ReachabilityReachable {
+			unreachable = append(unreachable, node.Description.Hostname)
+		}
+	}
+	reachable := managers - len(unreachable)
+	quorum := managers/2 + 1
+
+	var checks []doctorCheck
+	switch {
+	case reachable < quorum:
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkFail,
+			Message: fmt.Sprintf("%d of %d managers are reachable, which is less than the quorum of %d; unreachable: %s", reachable, managers, quorum, strings.Join(unreachable, ", ")),
+			Hint:    "Restart the unreachable managers, or recover the swarm with `docker swarm init --force-new-cluster` on a reachable manager",
+		})
+	case len(unreachable) > 0:
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkWarn,
+			Message: fmt.Sprintf("%d of %d managers are reachable; unreachable: %s", reachable, managers, strings.Join(unreachable, ", ")),
+			Hint:    "Restart the unreachable managers, or demote them with `docker node demote` and remove them with `docker node rm`",
+		})
+	default:
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d of %d managers are reachable", reachable, managers),
+		})
+	}
+	if managers%2 == 0 {
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkWarn,
+			Message: fmt.Sprintf("the swarm has an even number of managers (%d), which tolerates as many failures as %d managers", managers, managers-1),
+			Hint:    "Promote a worker with `docker node promote`, or demote a manager with `docker node demote`",
+		})
+	}
+	return checks
+}
+
+func checkCA(state swarmState, now time.Time) []doctorCheck {
+	const name = "ca"
+
+	trustRoot := state.swarm.ClusterInfo.TLSInfo.TrustRoot
+	expiry, err := certificatesExpiry([]byte(trustRoot))
+	if err != nil {
+		return []doctorCheck{{
+			Name:    name,
+			Status:  checkFail,
+			Message: fmt.Sprintf("the root CA certificate can't be read: %v", err),
+		}}
+	}
+
+	var checks []doctorCheck
+	hint := "Rotate the root CA with `docker swarm ca --rotate`"
+	switch {
+	case !expiry.After(now):
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkFail,
+			Message: fmt.Sprintf("the root CA certificate expired on %s", expiry.UTC().Format(time.RFC3339)),
+			Hint:    hint,
+		})
+	case expiry.Sub(now) < caExpiryWarning:
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkWarn,
+			Message: fmt.Sprintf("the root CA certificate expires on %s", expiry.UTC().Format(time.RFC3339)),
+			Hint:    hint,
+		})
+	default:
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkPass,
+			Message: fmt.Sprintf("the root CA certificate expires on %s", expiry.UTC().Format(time.RFC3339)),
+		})
+	}
+
+	// The nodes only need to trust the new root CA while it is rotated. Nodes
+	// that don't report the root CA they trust, such as nodes that never
+	// came up, are not reported.
+	var outdated []string
+	for _, node := range state.nodes {
+		nodeTrustRoot := node.Description.TLSInfo.TrustRoot
+		if state.swarm.ClusterInfo.RootRotationInProgress && nodeTrustRoot != "" && nodeTrustRoot != trustRoot {
+			outdated = append(outdated, node.Description.Hostname)
+		}
+	}
+	if len(outdated) > 0 {
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkWarn,
+			Message: fmt.Sprintf("the root CA is being rotated, these nodes don't trust the new root CA yet: %s", strings.Join(outdated, ", ")),
+			Hint:    "The rotation completes once every node is reachable; nodes that are down can be removed with `docker node rm`",
+		})
+	}
+	return checks
+}
+
+// checkJoinTokens checks the deadlines to rotate the join tokens set by
+// `docker swarm join-token --rotate-after`. Tokens without a deadline aren't
+// reported.
+func checkJoinTokens(state swarmState, now time.Time) []doctorCheck {
+	const name = "tokens"
+
+	var checks []doctorCheck
+	for _, role := range []string{"worker", "manager"} {
+		token := state.swarm.JoinTokens.Worker
+		if role == "manager" {
+			token = state.swarm.JoinTokens.Manager
+		}
+		deadline, ok := findJoinTokenDeadline(state.joinTokenDeadlines, state.swarm.ID, role, token)
+		if !ok {
+			continue
+		}
+		if deadline.Deadline.After(now) {
+			checks = append(checks, doctorCheck{
+				Name:    name,
+				Status:  checkPass,
+				Message: fmt.Sprintf("the %s join token has to be rotated by %s", role, deadline.Deadline.UTC().Format(time.RFC3339)),
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkWarn,
+			Message: fmt.Sprintf("the %s join token had to be rotated by %s", role, deadline.Deadline.UTC().Format(time.RFC3339)),
+			Hint:    fmt.Sprintf("Rotate the join token with `docker swarm join-token --rotate %s`", role),
+		})
+	}
+	return checks
+}
+
+// certificatesExpiry returns the earliest expiry of the PEM encoded
+// certificates.
+func certificatesExpiry(data []byte) (time.Time, error) {
+	var expiry time.Time
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+	if expiry.IsZero() {
+		return time.Time{}, errors.New("no certificate found")
+	}
+	return expiry, nil
+}
+
+func checkNodes(state swarmState, now time.Time) []doctorCheck {
+	const name = "nodes"
+
+	var checks []doctorCheck
+	for _, node := range state.nodes {
+		hostname := node.Description.Hostname
+		if node.Status.State != swarm.NodeStateReady {
+			checks = append(checks, doctorCheck{
+				Name:    name,
+				Status:  checkFail,
+				Message: fmt.Sprintf("node %s is %s", hostname, node.Status.State),
+				Hint:    "Check the Docker daemon on the node, or remove the node with `docker node rm`",
+			})
+		}
+		if node.Spec.Availability == swarm.NodeAvailabilityDrain {
+			checks = append(checks, doctorCheck{
+				Name:    name,
+				Status:  checkWarn,
+				Message: fmt.Sprintf("node %s is drained", hostname),
+				Hint:    fmt.Sprintf("Run `docker node undrain %s` once its maintenance is done", hostname),
+			})
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d nodes are ready and active", len(state.nodes)),
+		})
+	}
+	return checks
+}
+
+func checkServices(state swarmState, now time.Time) []doctorCheck {
+	const name = "services"
+
+	desired := make(map[string]uint64)
+	running := make(map[string]uint64)
+	for _, task := range state.tasks {
+		desired[task.ServiceID]++
+		if task.Status.State == swarm.TaskStateRunning {
+			running[task.ServiceID]++
+		}
+	}
+
+	var checks []doctorCheck
+	for _, service := range sortedServices(state.services) {
+		want := desired[service.ID]
+		if replicated := service.Spec.Mode.Replicated; replicated != nil && replicated.Replicas != nil {
+			want = *replicated.Replicas
+		}
+		if running[service.ID] < want {
+			checks = append(checks, doctorCheck{
+				Name:    name,
+				Status:  checkFail,
+				Message: fmt.Sprintf("service %s has %d of %d tasks running", service.Spec.Name, running[service.ID], want),
+				Hint:    fmt.Sprintf("Check the tasks of the service with `docker service ps %s`", service.Spec.Name),
+			})
+		}
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkPass,
+			Message: fmt.Sprintf("%d services have all their tasks running", len(state.services)),
+		})
+	}
+	return checks
+}
+
+func checkPendingTasks(state swarmState, now time.Time) []doctorCheck {
+	const name = "tasks"
+
+	pending := make(map[string][]swarm.Task)
+	for _, task := range state.tasks {
+		if task.Status.State == swarm.TaskStatePending {
+			pending[task.ServiceID] = append(pending[task.ServiceID], task)
+		}
+	}
+
+	var checks []doctorCheck
+	for _, service := range sortedServices(state.services) {
+		tasks := pending[service.ID]
+		if len(tasks) == 0 {
+			continue
+		}
+		message := fmt.Sprintf("%d of the tasks of service %s are pending", len(tasks), service.Spec.Name)
+		if err := tasks[0].Status.Err; err != "" {
+			message += ": " + err
+		}
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkFail,
+			Message: message,
+			Hint:    "Check the placement constraints and resource reservations of the service, and the availability of the nodes",
+		})
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{
+			Name:    name,
+			Status:  checkPass,
+			Message: "no tasks are pending",
+		})
+	}
+	return checks
+}
+
+func checkNetworks(state swarmState, now time.Time) []doctorCheck {
+	const name = "networks"
+
+	used := make(map[string]bool)
+	for _, service := range state.services {
+		for _, networks := range [][]swarm.NetworkAttachmentConfig{service.Spec.Networks, service.Spec.TaskTemplate.Networks} {
+			for _, network := range networks {
+				used[network.Target] = true
+			}
+		}
+	}
+
+	var orphaned []string
+	for _, network := range state.networks {
+		if network.Scope != "swarm" || network.Ingress || used[network.ID] || used[network.Name] {
+			continue
+		}
+		orphaned = append(orphaned, network.Name)
+	}
+	if len(orphaned) == 0 {
+		return []doctorCheck{{
+			Name:    name,
+			Status:  checkPass,
+			Message: "every swarm network is used by a service",
+		}}
+	}
+	sort.Strings(orphaned)
+	return []doctorCheck{{
+		Name:    name,
+		Status:  checkWarn,
+		Message: fmt.Sprintf("these swarm networks are not used by any service: %s", strings.Join(orphaned, ", ")),
+		Hint:    "Remove the networks that are not needed with `docker network rm`",
+	}}
+}
+
+func sortedServices(services []swarm.Service) []swarm.Service {
+	sorted := append([]swarm.Service(nil), services...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Spec.Name < sorted[j].Spec.Name })
+	return sorted
+}
+
+func printReport(out io.Writer, report doctorReport) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	counts := make(map[checkStatus]int)
+	for _, check := range report.Checks {
+		counts[check.Status]++
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(string(check.Status)), check.Name, check.Message)
+		if check.Hint != "" {
+			fmt.Fprintf(w, "\t\t%s\n", check.Hint)
+		}
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n", counts[checkPass], counts[checkWarn], counts[checkFail])
+}
@@ -0,0 +1,246 @@
+package swarm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/gotestyourself/gotestyourself/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func rootCA(t *testing.T, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "swarm-ca"},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func doctorNode(hostname string, trustRoot string, manager bool) swarm.Node {
+	node := swarm.Node{
+		ID:          hostname + "-id",
+		Description: swarm.NodeDescription{Hostname: hostname, TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot}},
+		Status:      swarm.NodeStatus{State: swarm.NodeStateReady},
+		Spec:        swarm.NodeSpec{Availability: swarm.NodeAvailabilityActive},
+	}
+	if manager {
+		node.ManagerStatus = &swarm.ManagerStatus{Reachability: swarm.ReachabilityReachable}
+	}
+	return node
+}
+
+func replicatedService(id, name string, replicas uint64, networks ...string) swarm.Service {
+	service := swarm.Service{ID: id, Spec: swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: name},
+		Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+	}}
+	for _, network := range networks {
+		service.Spec.TaskTemplate.Networks = append(service.Spec.TaskTemplate.Networks, swarm.NetworkAttachmentConfig{Target: network})
+	}
+	return service
+}
+
+func doctorTask(serviceID string, state swarm.TaskState, err string) swarm.Task {
+	return swarm.Task{ServiceID: serviceID, Status: swarm.TaskStatus{State: state, Err: err}}
+}
+
+var doctorNow = time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func healthyClient(t *testing.T) *fakeClient {
+	trustRoot := rootCA(t, doctorNow.Add(365*24*time.Hour))
+	return &fakeClient{
+		swarmInspectFunc: func() (swarm.Swarm, error) {
+			return swarm.Swarm{ClusterInfo: swarm.ClusterInfo{TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot}}}, nil
+		},
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				doctorNode("manager-1", trustRoot, true),
+				doctorNode("manager-2", trustRoot, true),
+				doctorNode("manager-3", trustRoot, true),
+				doctorNode("worker-1", trustRoot, false),
+			}, nil
+		},
+		serviceListFunc: func() ([]swarm.Service, error) {
+			return []swarm.Service{replicatedService("web-id", "web", 2, "frontend-id")}, nil
+		},
+		taskListFunc: func() ([]swarm.Task, error) {
+			return []swarm.Task{
+				doctorTask("web-id", swarm.TaskStateRunning, ""),
+				doctorTask("web-id", swarm.TaskStateRunning, ""),
+			}, nil
+		},
+		networkListFunc: func() ([]types.NetworkResource, error) {
+			return []types.NetworkResource{
+				{ID: "frontend-id", Name: "frontend", Scope: "swarm"},
+				{ID: "ingress-id", Name: "ingress", Scope: "swarm", Ingress: true},
+				{ID: "bridge-id", Name: "bridge", Scope: "local"},
+			}, nil
+		},
+	}
+}
+
+func TestDiagnoseHealthySwarm(t *testing.T) {
+	report, err := diagnose(context.Background(), healthyClient(t), nil, doctorNow)
+	require.NoError(t, err)
+	assert.Equal(t, checkPass, report.Status)
+	for _, check := range report.Checks {
+		assert.Equal(t, checkPass, check.Status, check.Message)
+	}
+}
+
+func TestSwarmDoctor(t *testing.T) {
+	trustRoot := rootCA(t, doctorNow.Add(10*24*time.Hour))
+	client := healthyClient(t)
+	client.swarmInspectFunc = func() (swarm.Swarm, error) {
+		return swarm.Swarm{ClusterInfo: swarm.ClusterInfo{TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot}}}, nil
+	}
+	client.nodeListFunc = func() ([]swarm.Node, error) {
+		unreachable := doctorNode("manager-2", trustRoot, true)
+		unreachable.ManagerStatus.Reachability = swarm.ReachabilityUnreachable
+		unreachable.Status.State = swarm.NodeStateDown
+		drained := doctorNode("worker-1", trustRoot, false)
+		drained.Spec.Availability = swarm.NodeAvailabilityDrain
+		return []swarm.Node{
+			doctorNode("manager-1", trustRoot, true),
+			unreachable,
+			doctorNode("manager-3", trustRoot, true),
+			drained,
+		}, nil
+	}
+	client.serviceListFunc = func() ([]swarm.Service, error) {
+		return []swarm.Service{
+			replicatedService("web-id", "web", 2, "frontend-id"),
+			replicatedService("db-id", "db", 1),
+		}, nil
+	}
+	client.taskListFunc = func() ([]swarm.Task, error) {
+		return []swarm.Task{
+			doctorTask("web-id", swarm.TaskStateRunning, ""),
+			doctorTask("web-id", swarm.TaskStateRunning, ""),
+			doctorTask("db-id", swarm.TaskStatePending, "no suitable node (scheduling constraints not satisfied on 3 nodes)"),
+		}, nil
+	}
+	client.networkListFunc = func() ([]types.NetworkResource, error) {
+		return []types.NetworkResource{
+			{ID: "frontend-id", Name: "frontend", Scope: "swarm"},
+			{ID: "backend-id", Name: "backend", Scope: "swarm"},
+		}, nil
+	}
+
+	report, err := diagnose(context.Background(), client, nil, doctorNow)
+	require.NoError(t, err)
+	assert.Equal(t, checkFail, report.Status)
+
+	cli := test.NewFakeCli(client)
+	printReport(cli.Out(), report)
+	golden.Assert(t, cli.OutBuffer().String(), "doctor.golden")
+}
+
+func TestSwarmDoctorExpiredCA(t *testing.T) {
+	trustRoot := rootCA(t, doctorNow.Add(-time.Hour))
+	checks := checkCA(swarmState{swarm: swarm.Swarm{ClusterInfo: swarm.ClusterInfo{TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot}}}}, doctorNow)
+	require.Len(t, checks, 1)
+	assert.Equal(t, checkFail, checks[0].Status)
+	assert.Equal(t, "the root CA certificate expired on 2017-05-31T23:00:00Z", checks[0].Message)
+}
+
+func TestSwarmDoctorCARotation(t *testing.T) {
+	trustRoot := rootCA(t, doctorNow.Add(365*24*time.Hour))
+	oldTrustRoot := rootCA(t, doctorNow.Add(30*24*time.Hour))
+	state := swarmState{
+		swarm: swarm.Swarm{ClusterInfo: swarm.ClusterInfo{TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot}}},
+		nodes: []swarm.Node{
+			doctorNode("manager-1", trustRoot, true),
+			doctorNode("worker-1", oldTrustRoot, false),
+			doctorNode("worker-2", "", false),
+		},
+	}
+
+	// The trust roots of the nodes are only checked during a rotation
+	checks := checkCA(state, doctorNow)
+	require.Len(t, checks, 1)
+	assert.Equal(t, checkPass, checks[0].Status)
+
+	state.swarm.ClusterInfo.RootRotationInProgress = true
+	checks = checkCA(state, doctorNow)
+	require.Len(t, checks, 2)
+	assert.Equal(t, checkWarn, checks[1].Status)
+	assert.Equal(t, "the root CA is being rotated, these nodes don't trust the new root CA yet: worker-1", checks[1].Message)
+}
+
+func TestSwarmDoctorJSON(t *testing.T) {
+	client := healthyClient(t)
+	client.taskListFunc = func() ([]swarm.Task, error) {
+		return []swarm.Task{doctorTask("web-id", swarm.TaskStateRunning, "")}, nil
+	}
+	fakeCli := test.NewFakeCli(client)
+	cmd := newDoctorCommand(fakeCli)
+	cmd.SetArgs([]string{})
+	cmd.SetOutput(ioutil.Discard)
+	cmd.Flags().Set("format", "{{json .}}")
+	assert.Equal(t, cli.StatusError{StatusCode: 1}, cmd.Execute())
+
+	var report doctorReport
+	require.NoError(t, json.Unmarshal(fakeCli.OutBuffer().Bytes(), &report))
+	assert.Equal(t, checkFail, report.Status)
+	assert.Contains(t, report.Checks, doctorCheck{
+		Name:    "services",
+		Status:  checkFail,
+		Message: "service web has 1 of 2 tasks running",
+		Hint:    "Check the tasks of the service with `docker service ps web`",
+	})
+}
+
+func TestSwarmDoctorJoinTokens(t *testing.T) {
+	state := swarmState{
+		swarm: swarm.Swarm{
+			ClusterInfo: swarm.ClusterInfo{ID: "swarm-id"},
+			JoinTokens:  swarm.JoinTokens{Worker: "worker-token", Manager: "manager-token"},
+		},
+		joinTokenDeadlines: []joinTokenDeadline{
+			{SwarmID: "swarm-id", Role: "worker", TokenDigest: joinTokenDigest("worker-token"), Deadline: doctorNow.Add(-time.Hour)},
+			{SwarmID: "swarm-id", Role: "manager", TokenDigest: joinTokenDigest("manager-token"), Deadline: doctorNow.Add(time.Hour)},
+			{SwarmID: "swarm-id", Role: "manager", TokenDigest: joinTokenDigest("rotated-token"), Deadline: doctorNow.Add(-time.Hour)},
+			{SwarmID: "other-swarm-id", Role: "worker", TokenDigest: joinTokenDigest("worker-token"), Deadline: doctorNow.Add(-time.Hour)},
+		},
+	}
+	checks := checkJoinTokens(state, doctorNow)
+	assert.Equal(t, []doctorCheck{
+		{
+			Name:    "tokens",
+			Status:  checkWarn,
+			Message: "the worker join token had to be rotated by 2017-05-31T23:00:00Z",
+			Hint:    "Rotate the join token with `docker swarm join-token --rotate worker`",
+		},
+		{
+			Name:    "tokens",
+			Status:  checkPass,
+			Message: "the manager join token has to be rotated by 2017-06-01T01:00:00Z",
+		},
+	}, checks)
+
+	assert.Empty(t, checkJoinTokens(swarmState{swarm: state.swarm}, doctorNow))
+}
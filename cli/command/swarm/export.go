@@ -0,0 +1,187 @@
+package swarm
+
+import (
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type exportOptions struct {
+	output string
+}
+
+func newExportCommand(dockerCli command.Cli) *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS]",
+		Short: "Export the services, networks, configs, secrets and node labels of the swarm to an archive",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(dockerCli, opts)
+		},
+		Tags: map[string]string{"version": "1.30"},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	return cmd
+}
+
+func runExport(dockerCli command.Cli, opts exportOptions) error {
+	if opts.output == "" && dockerCli.Out().IsTerminal() {
+		return errors.New("cowardly refusing to export to a terminal. Use the -o flag or redirect")
+	}
+
+	archive, err := exportSwarm(context.Background(), dockerCli.Client())
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "" {
+		return writeArchive(dockerCli.Out(), archive)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(writeArchive(pipeWriter, archive))
+	}()
+	if err := command.CopyToFile(opts.output, pipeReader); err != nil {
+		return err
+	}
+	return os.Chmod(opts.output, 0600)
+}
+
+// exportSwarm reads the cluster objects of a swarm. The values of secrets
+// can't be read, so only their metadata is exported.
+func exportSwarm(ctx context.Context, apiClient client.APIClient) (*swarmArchive, error) {
+	sw, err := apiClient.SwarmInspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archive := &swarmArchive{
+		Manifest: archiveManifest{
+			Version: archiveVersion,
+			Created: time.Now().UTC(),
+			SwarmID: sw.ID,
+		},
+		Swarm: sw.Spec,
+	}
+
+	networks, err := apiClient.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	networkNames := make(map[string]string)
+	for _, network := range networks {
+		if network.Scope != "swarm" || network.Ingress {
+			continue
+		}
+		networkNames[network.ID] = network.Name
+		ipam := network.IPAM
+		archive.Networks = append(archive.Networks, types.NetworkCreateRequest{
+			Name: network.Name,
+			NetworkCreate: types.NetworkCreate{
+				Driver:     network.Driver,
+				Scope:      network.Scope,
+				EnableIPv6: network.EnableIPv6,
+				IPAM:       &ipam,
+				Internal:   network.Internal,
+				Attachable: network.Attachable,
+				Options:    network.Options,
+				Labels:     network.Labels,
+			},
+		})
+	}
+	sort.Slice(archive.Networks, func(i, j int) bool { return archive.Networks[i].Name < archive.Networks[j].Name })
+
+	configs, err := apiClient.ConfigList(ctx, types.ConfigListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		archive.Configs = append(archive.Configs, config.Spec)
+	}
+	sort.Slice(archive.Configs, func(i, j int) bool { return archive.Configs[i].Name < archive.Configs[j].Name })
+
+	secrets, err := apiClient.SecretList(ctx, types.SecretListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		spec := secret.Spec
+		spec.Data = nil
+		archive.Secrets = append(archive.Secrets, spec)
+	}
+	sort.Slice(archive.Secrets, func(i, j int) bool { return archive.Secrets[i].Name < archive.Secrets[j].Name })
+
+	nodes, err := apiClient.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		archive.Nodes = append(archive.Nodes, archiveNode{
+			ID:       node.ID,
+			Hostname: node.Description.Hostname,
+			Role:     node.Spec.Role,
+			Labels:   node.Spec.Labels,
+		})
+	}
+	sort.Slice(archive.Nodes, func(i, j int) bool { return archive.Nodes[i].Hostname < archive.Nodes[j].Hostname })
+
+	services, err := apiClient.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		archive.Services = append(archive.Services, portableServiceSpec(service.Spec, networkNames))
+	}
+	sort.Slice(archive.Services, func(i, j int) bool { return archive.Services[i].Name < archive.Services[j].Name })
+
+	return archive, nil
+}
+
+// portableServiceSpec changes the references of a service spec to other
+// objects so that they use names instead of IDs.
+func portableServiceSpec(spec swarm.ServiceSpec, networkNames map[string]string) swarm.ServiceSpec {
+	portableNetworks := func(networks []swarm.NetworkAttachmentConfig) []swarm.NetworkAttachmentConfig {
+		var portable []swarm.NetworkAttachmentConfig
+		for _, network := range networks {
+			if name, ok := networkNames[network.Target]; ok {
+				network.Target = name
+			}
+			portable = append(portable, network)
+		}
+		return portable
+	}
+	spec.Networks = portableNetworks(spec.Networks)
+	spec.TaskTemplate.Networks = portableNetworks(spec.TaskTemplate.Networks)
+
+	if containerSpec := spec.TaskTemplate.ContainerSpec; containerSpec != nil {
+		portable := *containerSpec
+		portable.Secrets = nil
+		for _, secret := range containerSpec.Secrets {
+			ref := *secret
+			ref.SecretID = ""
+			portable.Secrets = append(portable.Secrets, &ref)
+		}
+		portable.Configs = nil
+		for _, config := range containerSpec.Configs {
+			ref := *config
+			ref.ConfigID = ""
+			portable.Configs = append(portable.Configs, &ref)
+		}
+		spec.TaskTemplate.ContainerSpec = &portable
+	}
+	return spec
+}
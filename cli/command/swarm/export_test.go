@@ -0,0 +1,98 @@
+package swarm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func exportClient() *fakeClient {
+	return &fakeClient{
+		swarmInspectFunc: func() (swarm.Swarm, error) {
+			return swarm.Swarm{ClusterInfo: swarm.ClusterInfo{ID: "swarm-id"}}, nil
+		},
+		networkListFunc: func() ([]types.NetworkResource, error) {
+			return []types.NetworkResource{
+				{ID: "backend-id", Name: "backend", Scope: "swarm", Driver: "overlay"},
+				{ID: "ingress-id", Name: "ingress", Scope: "swarm", Driver: "overlay", Ingress: true},
+				{ID: "bridge-id", Name: "bridge", Scope: "local", Driver: "bridge"},
+			}, nil
+		},
+		secretListFunc: func() ([]swarm.Secret, error) {
+			return []swarm.Secret{{ID: "secret-id", Spec: swarm.SecretSpec{
+				Annotations: swarm.Annotations{Name: "db_password"},
+				Data:        []byte("password"),
+			}}}, nil
+		},
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{{
+				ID:          "node-id",
+				Description: swarm.NodeDescription{Hostname: "node-1"},
+				Spec: swarm.NodeSpec{
+					Annotations: swarm.Annotations{Labels: map[string]string{"zone": "a"}},
+					Role:        swarm.NodeRoleWorker,
+				},
+			}}, nil
+		},
+		serviceListFunc: func() ([]swarm.Service, error) {
+			return []swarm.Service{{ID: "web-id", Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "web"},
+				TaskTemplate: swarm.TaskSpec{
+					ContainerSpec: &swarm.ContainerSpec{
+						Image:   "nginx",
+						Secrets: []*swarm.SecretReference{{SecretID: "secret-id", SecretName: "db_password"}},
+					},
+					Networks: []swarm.NetworkAttachmentConfig{{Target: "backend-id"}},
+				},
+			}}}, nil
+		},
+	}
+}
+
+func TestExportSwarm(t *testing.T) {
+	archive, err := exportSwarm(context.Background(), exportClient())
+	require.NoError(t, err)
+
+	assert.Equal(t, "swarm-id", archive.Manifest.SwarmID)
+	require.Len(t, archive.Networks, 1)
+	assert.Equal(t, "backend", archive.Networks[0].Name)
+	require.Len(t, archive.Secrets, 1)
+	assert.Nil(t, archive.Secrets[0].Data)
+	assert.Equal(t, []archiveNode{{ID: "node-id", Hostname: "node-1", Role: swarm.NodeRoleWorker, Labels: map[string]string{"zone": "a"}}}, archive.Nodes)
+
+	require.Len(t, archive.Services, 1)
+	taskTemplate := archive.Services[0].TaskTemplate
+	assert.Equal(t, "backend", taskTemplate.Networks[0].Target)
+	assert.Equal(t, &swarm.SecretReference{SecretName: "db_password"}, taskTemplate.ContainerSpec.Secrets[0])
+}
+
+func TestSwarmExportToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "swarm.tar.gz")
+
+	cmd := newExportCommand(test.NewFakeCli(exportClient()))
+	cmd.SetArgs([]string{})
+	cmd.Flags().Set("output", output)
+	require.NoError(t, cmd.Execute())
+
+	file, err := os.Open(output)
+	require.NoError(t, err)
+	defer file.Close()
+	info, err := file.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	archive, err := readArchive(file)
+	require.NoError(t, err)
+	assert.Equal(t, "web", archive.Services[0].Name)
+}
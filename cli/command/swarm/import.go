@@ -0,0 +1,428 @@
+package swarm
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+type importOptions struct {
+	input     string
+	dryRun    bool
+	secrets   opts.ListOpts
+	labelMaps opts.ListOpts
+}
+
+func newImportCommand(dockerCli command.Cli) *cobra.Command {
+	options := importOptions{
+		secrets:   opts.NewListOpts(validateSecretFile),
+		labelMaps: opts.NewListOpts(validateLabelMap),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import [OPTIONS]",
+		Short: "Import services, networks, configs, secrets and node labels from an archive",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(dockerCli, options)
+		},
+		Tags: map[string]string{"version": "1.30"},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&options.input, "input", "i", "", "Read from an archive file, instead of STDIN")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the changes without making them")
+	flags.Var(&options.secrets, "secret", "Read the value of a secret from a file (format: NAME=FILE)")
+	flags.Var(&options.labelMaps, "map-label", "Change the value of a node label, in node labels and placement constraints (format: KEY=OLD:NEW)")
+	return cmd
+}
+
+// importStep is a change made to the swarm when an archive is imported.
+type importStep struct {
+	description string
+	run         func(ctx context.Context) error
+}
+
+func runImport(dockerCli command.Cli, options importOptions) error {
+	ctx := context.Background()
+
+	var in io.Reader = dockerCli.In()
+	if options.input != "" {
+		file, err := system.OpenSequential(options.input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	archive, err := readArchive(in)
+	if err != nil {
+		return err
+	}
+
+	steps, err := planImport(ctx, dockerCli, archive, options)
+	if err != nil {
+		return err
+	}
+
+	if options.dryRun {
+		for _, step := range steps {
+			fmt.Fprintf(dockerCli.Out(), "Would %s\n", step.description)
+		}
+		return nil
+	}
+	for _, step := range steps {
+		fmt.Fprintf(dockerCli.Out(), "%s%s\n", strings.ToUpper(step.description[:1]), step.description[1:])
+		if err := step.run(ctx); err != nil {
+			return errors.Wrapf(err, "failed to %s", step.description)
+		}
+	}
+	return nil
+}
+
+// importSwarmSpec returns the spec of the target swarm with the settings of
+// an archive applied. Only the settings that don't depend on the swarm are
+// imported: the encryption, the root CA and its rotation, and the external
+// CAs of the target swarm are kept.
+func importSwarmSpec(current, archived swarm.Spec) swarm.Spec {
+	spec := current
+	spec.Orchestration = archived.Orchestration
+	spec.Raft.SnapshotInterval = archived.Raft.SnapshotInterval
+	spec.Raft.KeepOldSnapshots = archived.Raft.KeepOldSnapshots
+	spec.Raft.LogEntriesForSlowFollowers = archived.Raft.LogEntriesForSlowFollowers
+	spec.Dispatcher = archived.Dispatcher
+	spec.TaskDefaults = archived.TaskDefaults
+	spec.CAConfig.NodeCertExpiry = archived.CAConfig.NodeCertExpiry
+	return spec
+}
+
+// planImport returns the steps that import an archive, in dependency order:
+// the swarm settings, then networks, configs and secrets, then node labels and
+// finally services. Objects that already exist are not changed.
+func planImport(ctx context.Context, dockerCli command.Cli, archive *swarmArchive, options importOptions) ([]importStep, error) {
+	apiClient := dockerCli.Client()
+	labelMap := parseLabelMaps(options.labelMaps.GetAll())
+	secretFiles := opts.ConvertKVStringsToMap(options.secrets.GetAll())
+
+	skip := func(kind, name string) {
+		fmt.Fprintf(dockerCli.Err(), "Skipping %s %s: it already exists\n", kind, name)
+	}
+
+	steps := []importStep{{
+		description: "update the swarm settings",
+		run: func(ctx context.Context) error {
+			current, err := apiClient.SwarmInspect(ctx)
+			if err != nil {
+				return err
+			}
+			spec := importSwarmSpec(current.Spec, archive.Swarm)
+			return apiClient.SwarmUpdate(ctx, current.Version, spec, swarm.UpdateFlags{})
+		},
+	}}
+
+	networks, err := apiClient.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existingNetworks := make(map[string]bool)
+	for _, network := range networks {
+		existingNetworks[network.Name] = true
+	}
+	for _, network := range archive.Networks {
+		network := network
+		if existingNetworks[network.Name] {
+			skip("network", network.Name)
+			continue
+		}
+		steps = append(steps, importStep{
+			description: "create network " + network.Name,
+			run: func(ctx context.Context) error {
+				network.CheckDuplicate = true
+				_, err := apiClient.NetworkCreate(ctx, network.Name, network.NetworkCreate)
+				return err
+			},
+		})
+	}
+
+	configs, err := apiClient.ConfigList(ctx, types.ConfigListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existingConfigs := make(map[string]bool)
+	for _, config := range configs {
+		existingConfigs[config.Spec.Name] = true
+	}
+	for _, config := range archive.Configs {
+		config := config
+		if existingConfigs[config.Name] {
+			skip("config", config.Name)
+			continue
+		}
+		steps = append(steps, importStep{
+			description: "create config " + config.Name,
+			run: func(ctx context.Context) error {
+				_, err := apiClient.ConfigCreate(ctx, config)
+				return err
+			},
+		})
+	}
+
+	secrets, err := apiClient.SecretList(ctx, types.SecretListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existingSecrets := make(map[string]bool)
+	for _, secret := range secrets {
+		existingSecrets[secret.Spec.Name] = true
+	}
+	var missing []string
+	for _, secret := range archive.Secrets {
+		secret := secret
+		if existingSecrets[secret.Name] {
+			skip("secret", secret.Name)
+			continue
+		}
+		file, ok := secretFiles[secret.Name]
+		if !ok {
+			missing = append(missing, secret.Name)
+			continue
+		}
+		steps = append(steps, importStep{
+			description: fmt.Sprintf("create secret %s from %s", secret.Name, file),
+			run: func(ctx context.Context) error {
+				data, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				secret.Data = data
+				_, err = apiClient.SecretCreate(ctx, secret)
+				return err
+			},
+		})
+	}
+	if len(missing) > 0 {
+		return nil, errors.Errorf("the values of secrets are not exported, set the value of these secrets with --secret NAME=FILE: %s", strings.Join(missing, ", "))
+	}
+
+	nodes, err := apiClient.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodesByHostname := make(map[string]swarm.Node)
+	for _, node := range nodes {
+		nodesByHostname[node.Description.Hostname] = node
+	}
+	for _, archived := range archive.Nodes {
+		if len(archived.Labels) == 0 {
+			continue
+		}
+		node, ok := nodesByHostname[archived.Hostname]
+		if !ok {
+			fmt.Fprintf(dockerCli.Err(), "Skipping the labels of node %s: there is no node with this hostname\n", archived.Hostname)
+			continue
+		}
+		labels := remapLabels(archived.Labels, labelMap)
+		steps = append(steps, importStep{
+			description: fmt.Sprintf("set labels %s on node %s", formatLabels(labels), archived.Hostname),
+			run: func(ctx context.Context) error {
+				current, _, err := apiClient.NodeInspectWithRaw(ctx, node.ID)
+				if err != nil {
+					return err
+				}
+				if current.Spec.Labels == nil {
+					current.Spec.Labels = make(map[string]string)
+				}
+				for k, v := range labels {
+					current.Spec.Labels[k] = v
+				}
+				return apiClient.NodeUpdate(ctx, current.ID, current.Version, current.Spec)
+			},
+		})
+	}
+
+	services, err := apiClient.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existingServices := make(map[string]bool)
+	for _, service := range services {
+		existingServices[service.Spec.Name] = true
+	}
+	nodeHostnames := make(map[string]string)
+	for _, node := range archive.Nodes {
+		nodeHostnames[node.ID] = node.Hostname
+	}
+	for _, spec := range archive.Services {
+		spec := spec
+		if existingServices[spec.Name] {
+			skip("service", spec.Name)
+			continue
+		}
+		if placement := spec.TaskTemplate.Placement; placement != nil {
+			remapped := *placement
+			remapped.Constraints = remapConstraints(placement.Constraints, nodeHostnames, labelMap)
+			spec.TaskTemplate.Placement = &remapped
+		}
+		steps = append(steps, importStep{
+			description: "create service " + spec.Name,
+			run: func(ctx context.Context) error {
+				if err := resolveReferences(ctx, apiClient, &spec); err != nil {
+					return err
+				}
+				response, err := apiClient.ServiceCreate(ctx, spec, types.ServiceCreateOptions{})
+				if err != nil {
+					return err
+				}
+				for _, warning := range response.Warnings {
+					fmt.Fprintln(dockerCli.Err(), warning)
+				}
+				return nil
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+// resolveReferences sets the IDs of the secrets and configs of a service spec
+// from their names, once they are created.
+func resolveReferences(ctx context.Context, apiClient client.APIClient, spec *swarm.ServiceSpec) error {
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if containerSpec == nil {
+		return nil
+	}
+	for _, ref := range containerSpec.Secrets {
+		secrets, err := apiClient.SecretList(ctx, types.SecretListOptions{Filters: filters.NewArgs(filters.Arg("name", ref.SecretName))})
+		if err != nil {
+			return err
+		}
+		id, err := exactName(ref.SecretName, "secret", len(secrets), func(i int) (string, string) { return secrets[i].ID, secrets[i].Spec.Name })
+		if err != nil {
+			return err
+		}
+		ref.SecretID = id
+	}
+	for _, ref := range containerSpec.Configs {
+		configs, err := apiClient.ConfigList(ctx, types.ConfigListOptions{Filters: filters.NewArgs(filters.Arg("name", ref.ConfigName))})
+		if err != nil {
+			return err
+		}
+		id, err := exactName(ref.ConfigName, "config", len(configs), func(i int) (string, string) { return configs[i].ID, configs[i].Spec.Name })
+		if err != nil {
+			return err
+		}
+		ref.ConfigID = id
+	}
+	return nil
+}
+
+// exactName returns the ID of the object with the given name, as the name
+// filter also matches names that start with it.
+func exactName(name, kind string, count int, get func(i int) (string, string)) (string, error) {
+	for i := 0; i < count; i++ {
+		if id, n := get(i); n == name {
+			return id, nil
+		}
+	}
+	return "", errors.Errorf("%s not found: %s", kind, name)
+}
+
+// labelMap holds the new values of node labels, by key and old value.
+type labelMap map[string]map[string]string
+
+func parseLabelMaps(values []string) labelMap {
+	m := make(labelMap)
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		oldNew := strings.SplitN(kv[1], ":", 2)
+		if m[kv[0]] == nil {
+			m[kv[0]] = make(map[string]string)
+		}
+		m[kv[0]][oldNew[0]] = oldNew[1]
+	}
+	return m
+}
+
+func remapLabels(labels map[string]string, labelMap labelMap) map[string]string {
+	remapped := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if newValue, ok := labelMap[k][v]; ok {
+			v = newValue
+		}
+		remapped[k] = v
+	}
+	return remapped
+}
+
+// remapConstraints changes the placement constraints of a service so that they
+// match the nodes of the swarm it is imported in. Constraints on node IDs are
+// changed to constraints on the hostnames of the nodes, and the values of node
+// labels are changed as given by labelMap.
+func remapConstraints(constraints []string, nodeHostnames map[string]string, labelMap labelMap) []string {
+	var remapped []string
+	for _, constraint := range constraints {
+		remapped = append(remapped, remapConstraint(constraint, nodeHostnames, labelMap))
+	}
+	return remapped
+}
+
+func remapConstraint(constraint string, nodeHostnames map[string]string, labelMap labelMap) string {
+	for _, operator := range []string{"==", "!="} {
+		parts := strings.SplitN(constraint, operator, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch {
+		case key == "node.id":
+			if hostname, ok := nodeHostnames[value]; ok {
+				return fmt.Sprintf("node.hostname %s %s", operator, hostname)
+			}
+		case strings.HasPrefix(key, "node.labels."):
+			if newValue, ok := labelMap[strings.TrimPrefix(key, "node.labels.")][value]; ok {
+				return fmt.Sprintf("%s %s %s", key, operator, newValue)
+			}
+		}
+		return constraint
+	}
+	return constraint
+}
+
+func formatLabels(labels map[string]string) string {
+	var pairs []string
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func validateSecretFile(value string) (string, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", errors.Errorf("invalid secret %s: the format is NAME=FILE", value)
+	}
+	return value, nil
+}
+
+func validateLabelMap(value string) (string, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || !strings.Contains(parts[1], ":") {
+		return "", errors.Errorf("invalid label map %s: the format is KEY=OLD:NEW", value)
+	}
+	return value, nil
+}
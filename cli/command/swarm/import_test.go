@@ -0,0 +1,230 @@
+package swarm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/testutil"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func importArchive() *swarmArchive {
+	return &swarmArchive{
+		Manifest: archiveManifest{Version: archiveVersion},
+		Networks: []types.NetworkCreateRequest{
+			{Name: "backend", NetworkCreate: types.NetworkCreate{Driver: "overlay"}},
+			{Name: "frontend", NetworkCreate: types.NetworkCreate{Driver: "overlay"}},
+		},
+		Secrets: []swarm.SecretSpec{{Annotations: swarm.Annotations{Name: "db_password"}}},
+		Nodes: []archiveNode{
+			{ID: "old-node-1", Hostname: "node-1", Labels: map[string]string{"zone": "us-east"}},
+			{ID: "old-node-2", Hostname: "node-2", Labels: map[string]string{"zone": "us-west"}},
+		},
+		Services: []swarm.ServiceSpec{{
+			Annotations: swarm.Annotations{Name: "db"},
+			TaskTemplate: swarm.TaskSpec{
+				ContainerSpec: &swarm.ContainerSpec{
+					Image:   "postgres",
+					Secrets: []*swarm.SecretReference{{SecretName: "db_password"}},
+				},
+				Placement: &swarm.Placement{
+					Constraints: []string{"node.id==old-node-1", "node.labels.zone == us-east"},
+				},
+			},
+		}},
+	}
+}
+
+func writeImportArchive(t *testing.T, dir string) string {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeArchive(buf, importArchive()))
+	input := filepath.Join(dir, "swarm.tar.gz")
+	require.NoError(t, ioutil.WriteFile(input, buf.Bytes(), 0600))
+	return input
+}
+
+func importClient() *fakeClient {
+	return &fakeClient{
+		networkListFunc: func() ([]types.NetworkResource, error) {
+			return []types.NetworkResource{{Name: "frontend"}}, nil
+		},
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{{ID: "new-node-1", Description: swarm.NodeDescription{Hostname: "node-1"}}}, nil
+		},
+	}
+}
+
+func TestSwarmImportMissingSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cmd := newImportCommand(test.NewFakeCli(importClient()))
+	cmd.SetArgs([]string{})
+	cmd.SetOutput(ioutil.Discard)
+	cmd.Flags().Set("input", writeImportArchive(t, dir))
+	testutil.ErrorContains(t, cmd.Execute(), "set the value of these secrets with --secret NAME=FILE: db_password")
+}
+
+func TestSwarmImportDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	client := importClient()
+	client.networkCreateFunc = func(string, types.NetworkCreate) error {
+		t.Fatal("the network should not be created")
+		return nil
+	}
+	cli := test.NewFakeCli(client)
+	cmd := newImportCommand(cli)
+	cmd.SetArgs([]string{})
+	cmd.Flags().Set("input", writeImportArchive(t, dir))
+	cmd.Flags().Set("secret", "db_password=password.txt")
+	cmd.Flags().Set("map-label", "zone=us-east:eu-west")
+	cmd.Flags().Set("dry-run", "true")
+	require.NoError(t, cmd.Execute())
+
+	expected := `Would update the swarm settings
+Would create network backend
+Would create secret db_password from password.txt
+Would set labels zone=eu-west on node node-1
+Would create service db
+`
+	assert.Equal(t, expected, cli.OutBuffer().String())
+	assert.Equal(t, "Skipping network frontend: it already exists\nSkipping the labels of node node-2: there is no node with this hostname\n", cli.ErrBuffer().String())
+}
+
+func TestSwarmImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	passwordFile := filepath.Join(dir, "password.txt")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("password"), 0600))
+
+	var (
+		secrets  []swarm.Secret
+		labels   map[string]string
+		services []swarm.ServiceSpec
+	)
+	client := importClient()
+	client.secretCreateFunc = func(secret swarm.SecretSpec) error {
+		assert.Equal(t, []byte("password"), secret.Data)
+		secrets = append(secrets, swarm.Secret{ID: "new-secret-id", Spec: secret})
+		return nil
+	}
+	client.secretListFunc = func() ([]swarm.Secret, error) {
+		return secrets, nil
+	}
+	client.nodeInspectFunc = func() (swarm.Node, []byte, error) {
+		return swarm.Node{ID: "new-node-1"}, nil, nil
+	}
+	client.nodeUpdateFunc = func(nodeID string, node swarm.NodeSpec) error {
+		assert.Equal(t, "new-node-1", nodeID)
+		labels = node.Labels
+		return nil
+	}
+	client.serviceCreateFunc = func(service swarm.ServiceSpec) error {
+		services = append(services, service)
+		return nil
+	}
+
+	cmd := newImportCommand(test.NewFakeCli(client))
+	cmd.SetArgs([]string{})
+	cmd.Flags().Set("input", writeImportArchive(t, dir))
+	cmd.Flags().Set("secret", "db_password="+passwordFile)
+	cmd.Flags().Set("map-label", "zone=us-east:eu-west")
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, map[string]string{"zone": "eu-west"}, labels)
+	require.Len(t, services, 1)
+	taskTemplate := services[0].TaskTemplate
+	assert.Equal(t, []string{"node.hostname == node-1", "node.labels.zone == eu-west"}, taskTemplate.Placement.Constraints)
+	assert.Equal(t, "new-secret-id", taskTemplate.ContainerSpec.Secrets[0].SecretID)
+}
+
+func TestSwarmImportSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm-import")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	historyLimit := int64(10)
+	archive := &swarmArchive{
+		Manifest: archiveManifest{Version: archiveVersion},
+		Swarm: swarm.Spec{
+			Annotations:      swarm.Annotations{Name: "default"},
+			Orchestration:    swarm.OrchestrationConfig{TaskHistoryRetentionLimit: &historyLimit},
+			Raft:             swarm.RaftConfig{SnapshotInterval: 5000, ElectionTick: 20},
+			Dispatcher:       swarm.DispatcherConfig{HeartbeatPeriod: time.Minute},
+			CAConfig:         swarm.CAConfig{NodeCertExpiry: 24 * time.Hour, ForceRotate: 1},
+			EncryptionConfig: swarm.EncryptionConfig{AutoLockManagers: true},
+		},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeArchive(buf, archive))
+	input := filepath.Join(dir, "swarm.tar.gz")
+	require.NoError(t, ioutil.WriteFile(input, buf.Bytes(), 0600))
+
+	current := swarm.Spec{
+		Annotations: swarm.Annotations{Name: "default"},
+		Raft:        swarm.RaftConfig{SnapshotInterval: 10000, ElectionTick: 10},
+		CAConfig: swarm.CAConfig{
+			NodeCertExpiry: 90 * 24 * time.Hour,
+			ForceRotate:    3,
+			ExternalCAs:    []*swarm.ExternalCA{{Protocol: swarm.ExternalCAProtocolCFSSL, URL: "https://ca.example.com"}},
+		},
+	}
+	var updated swarm.Spec
+	client := importClient()
+	client.swarmInspectFunc = func() (swarm.Swarm, error) {
+		return swarm.Swarm{ClusterInfo: swarm.ClusterInfo{Spec: current}}, nil
+	}
+	client.swarmUpdateFunc = func(spec swarm.Spec, flags swarm.UpdateFlags) error {
+		updated = spec
+		return nil
+	}
+
+	cmd := newImportCommand(test.NewFakeCli(client))
+	cmd.SetArgs([]string{})
+	cmd.Flags().Set("input", input)
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, &historyLimit, updated.Orchestration.TaskHistoryRetentionLimit)
+	assert.Equal(t, uint64(5000), updated.Raft.SnapshotInterval)
+	assert.Equal(t, time.Minute, updated.Dispatcher.HeartbeatPeriod)
+	assert.Equal(t, 24*time.Hour, updated.CAConfig.NodeCertExpiry)
+	// The encryption and the CA of the target swarm are kept
+	assert.False(t, updated.EncryptionConfig.AutoLockManagers)
+	assert.Equal(t, uint64(3), updated.CAConfig.ForceRotate)
+	assert.Equal(t, current.CAConfig.ExternalCAs, updated.CAConfig.ExternalCAs)
+	assert.Equal(t, 10, updated.Raft.ElectionTick)
+}
+
+func TestRemapConstraints(t *testing.T) {
+	nodeHostnames := map[string]string{"old-node": "node-1"}
+	labelMap := parseLabelMaps([]string{"zone=a:b", "disk=ssd:nvme"})
+
+	testCases := []struct {
+		constraint string
+		expected   string
+	}{
+		{constraint: "node.id==old-node", expected: "node.hostname == node-1"},
+		{constraint: "node.id != old-node", expected: "node.hostname != node-1"},
+		{constraint: "node.id==unknown-node", expected: "node.id==unknown-node"},
+		{constraint: "node.labels.zone==a", expected: "node.labels.zone == b"},
+		{constraint: "node.labels.disk != ssd", expected: "node.labels.disk != nvme"},
+		{constraint: "node.labels.zone==c", expected: "node.labels.zone==c"},
+		{constraint: "node.role==manager", expected: "node.role==manager"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, []string{tc.expected}, remapConstraints([]string{tc.constraint}, nodeHostnames, labelMap))
+	}
+}
@@ -14,6 +14,11 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// joinURIScheme is the scheme of the join URIs printed by
+// `docker swarm join-token --uri`, which hold a join token and the addresses
+// of the managers: swarm://TOKEN@HOST:PORT[,HOST:PORT...]
+const joinURIScheme = "swarm://"
+
 type joinOptions struct {
 	remote     string
 	listenAddr NodeAddrOption
@@ -30,7 +35,7 @@ func newJoinCommand(dockerCli command.Cli) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "join [OPTIONS] HOST:PORT",
+		Use:   "join [OPTIONS] HOST:PORT|URI",
 		Short: "Join a swarm as a node and/or manager",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -59,6 +64,17 @@ func runJoin(dockerCli command.Cli, flags *pflag.FlagSet, opts joinOptions) erro
 		DataPathAddr:  opts.dataPathAddr,
 		RemoteAddrs:   []string{opts.remote},
 	}
+	if strings.HasPrefix(opts.remote, joinURIScheme) {
+		if opts.token != "" {
+			return errors.New("--token can't be used with a join URI, which holds the token")
+		}
+		token, addrs, err := parseJoinURI(opts.remote)
+		if err != nil {
+			return err
+		}
+		req.JoinToken = token
+		req.RemoteAddrs = addrs
+	}
 	if flags.Changed(flagAvailability) {
 		availability := swarm.NodeAvailability(strings.ToLower(opts.availability))
 		switch availability {
@@ -86,3 +102,24 @@ func runJoin(dockerCli command.Cli, flags *pflag.FlagSet, opts joinOptions) erro
 	}
 	return nil
 }
+
+// formatJoinURI returns the join URI of a token and the addresses of managers.
+func formatJoinURI(token string, addrs []string) string {
+	return joinURIScheme + token + "@" + strings.Join(addrs, ",")
+}
+
+// parseJoinURI returns the token and the addresses of managers of a join URI.
+func parseJoinURI(uri string) (string, []string, error) {
+	invalid := errors.Errorf("invalid join URI: the format is %sTOKEN@HOST:PORT[,HOST:PORT...]", joinURIScheme)
+	parts := strings.SplitN(strings.TrimPrefix(uri, joinURIScheme), "@", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", nil, invalid
+	}
+	addrs := strings.Split(parts[1], ",")
+	for _, addr := range addrs {
+		if addr == "" {
+			return "", nil, invalid
+		}
+	}
+	return parts[0], addrs, nil
+}
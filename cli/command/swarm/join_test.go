@@ -17,7 +17,7 @@ func TestSwarmJoinErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
 		args          []string
-		swarmJoinFunc func() error
+		swarmJoinFunc func(req swarm.JoinRequest) error
 		infoFunc      func() (types.Info, error)
 		expectedError string
 	}{
@@ -33,11 +33,21 @@ func TestSwarmJoinErrors(t *testing.T) {
 		{
 			name: "join-failed",
 			args: []string{"remote"},
-			swarmJoinFunc: func() error {
+			swarmJoinFunc: func(req swarm.JoinRequest) error {
 				return errors.Errorf("error joining the swarm")
 			},
 			expectedError: "error joining the swarm",
 		},
+		{
+			name:          "join-uri-with-token",
+			args:          []string{"--token", "token", "swarm://token@10.0.0.1:2377"},
+			expectedError: "--token can't be used with a join URI, which holds the token",
+		},
+		{
+			name:          "invalid-join-uri",
+			args:          []string{"swarm://token@"},
+			expectedError: "invalid join URI: the format is swarm://TOKEN@HOST:PORT[,HOST:PORT...]",
+		},
 		{
 			name: "join-failed-on-init",
 			args: []string{"remote"},
@@ -98,3 +108,30 @@ func TestSwarmJoin(t *testing.T) {
 		assert.Equal(t, strings.TrimSpace(cli.OutBuffer().String()), tc.expected)
 	}
 }
+
+func TestSwarmJoinURI(t *testing.T) {
+	var req swarm.JoinRequest
+	cli := test.NewFakeCli(&fakeClient{
+		swarmJoinFunc: func(r swarm.JoinRequest) error {
+			req = r
+			return nil
+		},
+	})
+	cmd := newJoinCommand(cli)
+	cmd.SetArgs([]string{"swarm://SWMTKN-1-token@10.0.0.1:2377,10.0.0.2:2377"})
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "SWMTKN-1-token", req.JoinToken)
+	assert.Equal(t, []string{"10.0.0.1:2377", "10.0.0.2:2377"}, req.RemoteAddrs)
+}
+
+func TestParseJoinURI(t *testing.T) {
+	token, addrs, err := parseJoinURI(formatJoinURI("SWMTKN-1-token", []string{"10.0.0.1:2377", "10.0.0.2:2377"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "SWMTKN-1-token", token)
+	assert.Equal(t, []string{"10.0.0.1:2377", "10.0.0.2:2377"}, addrs)
+
+	for _, uri := range []string{"swarm://", "swarm://token", "swarm://@10.0.0.1:2377", "swarm://token@10.0.0.1:2377,"} {
+		_, _, err := parseJoinURI(uri)
+		assert.Error(t, err, uri)
+	}
+}
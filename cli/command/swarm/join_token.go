@@ -2,19 +2,26 @@ package swarm
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/net/context"
 )
 
 type joinTokenOptions struct {
-	role   string
-	rotate bool
-	quiet  bool
+	role        string
+	rotate      bool
+	rotateAfter time.Duration
+	quiet       bool
+	uri         bool
 }
 
 func newJoinTokenCommand(dockerCli command.Cli) *cobra.Command {
@@ -32,7 +39,9 @@ func newJoinTokenCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.rotate, flagRotate, false, "Rotate join token")
+	flags.DurationVar(&opts.rotateAfter, flagRotateAfter, 0, "Set a deadline to rotate the join token, after a duration")
 	flags.BoolVarP(&opts.quiet, flagQuiet, "q", false, "Only display token")
+	flags.BoolVar(&opts.uri, flagURI, false, "Display a join URI, that holds the token and the addresses of the managers")
 
 	return cmd
 }
@@ -44,6 +53,9 @@ func runJoinToken(dockerCli command.Cli, opts joinTokenOptions) error {
 	if !worker && !manager {
 		return errors.New("unknown role " + opts.role)
 	}
+	if opts.rotateAfter < 0 {
+		return errors.New("--rotate-after must be a positive duration")
+	}
 
 	client := dockerCli.Client()
 	ctx := context.Background()
@@ -75,13 +87,27 @@ func runJoinToken(dockerCli command.Cli, opts joinTokenOptions) error {
 		return err
 	}
 
-	if opts.quiet && worker {
-		fmt.Fprintln(dockerCli.Out(), sw.JoinTokens.Worker)
-		return nil
+	token := sw.JoinTokens.Worker
+	if manager {
+		token = sw.JoinTokens.Manager
+	}
+
+	var deadline time.Time
+	if opts.rotateAfter > 0 {
+		deadline = time.Now().Add(opts.rotateAfter).UTC().Truncate(time.Second)
+		err := saveJoinTokenDeadline(joinTokenDeadline{
+			SwarmID:     sw.ID,
+			Role:        opts.role,
+			TokenDigest: joinTokenDigest(token),
+			Deadline:    deadline,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to record the deadline of the join token")
+		}
 	}
 
-	if opts.quiet && manager {
-		fmt.Fprintln(dockerCli.Out(), sw.JoinTokens.Manager)
+	if opts.quiet && !opts.uri {
+		fmt.Fprintln(dockerCli.Out(), token)
 		return nil
 	}
 
@@ -90,7 +116,53 @@ func runJoinToken(dockerCli command.Cli, opts joinTokenOptions) error {
 		return err
 	}
 
-	return printJoinCommand(ctx, dockerCli, info.Swarm.NodeID, worker, manager)
+	if opts.uri {
+		addrs, err := managerAddrs(ctx, client, info.Swarm.NodeID)
+		if err != nil {
+			return err
+		}
+		uri := formatJoinURI(token, addrs)
+		if opts.quiet {
+			fmt.Fprintln(dockerCli.Out(), uri)
+			return nil
+		}
+		fmt.Fprintf(dockerCli.Out(), "To add a %s to this swarm, run the following command:\n\n    docker swarm join %s\n\n", opts.role, uri)
+	} else if err := printJoinCommand(ctx, dockerCli, info.Swarm.NodeID, worker, manager); err != nil {
+		return err
+	}
+
+	if !deadline.IsZero() {
+		fmt.Fprintf(dockerCli.Out(), "Rotate this join token by %s with `docker swarm join-token --rotate %s`; `docker swarm doctor` warns once the rotation is overdue.\n", deadline.Format(time.RFC3339), opts.role)
+	}
+	return nil
+}
+
+// managerAddrs returns the addresses of the reachable managers of the swarm,
+// the one of the node first.
+func managerAddrs(ctx context.Context, apiClient client.APIClient, nodeID string) ([]string, error) {
+	filter := filters.NewArgs(filters.Arg("role", string(swarm.NodeRoleManager)))
+	nodes, err := apiClient.NodeList(ctx, types.NodeListOptions{Filters: filter})
+	if err != nil {
+		return nil, err
+	}
+
+	var local, addrs []string
+	for _, node := range nodes {
+		if node.ManagerStatus == nil || node.ManagerStatus.Reachability != swarm.ReachabilityReachable {
+			continue
+		}
+		if node.ID == nodeID {
+			local = append(local, node.ManagerStatus.Addr)
+		} else {
+			addrs = append(addrs, node.ManagerStatus.Addr)
+		}
+	}
+	sort.Strings(addrs)
+	addrs = append(local, addrs...)
+	if len(addrs) == 0 {
+		return nil, errors.New("no manager of the swarm is reachable")
+	}
+	return addrs, nil
 }
 
 func printJoinCommand(ctx context.Context, dockerCli command.Cli, nodeID string, worker bool, manager bool) error {
@@ -0,0 +1,88 @@
+package swarm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/pkg/errors"
+)
+
+// joinTokenDeadlinesFile is the file, in the configuration directory, that
+// holds the deadlines set by `docker swarm join-token --rotate-after`.
+const joinTokenDeadlinesFile = "swarm-join-tokens.json"
+
+// joinTokenDeadline is when the join token of a role has to be rotated. The
+// token is recorded by its digest, so that the file holds no secret and the
+// deadline no longer applies once the token is rotated.
+type joinTokenDeadline struct {
+	SwarmID     string
+	Role        string
+	TokenDigest string
+	Deadline    time.Time
+}
+
+func joinTokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
+
+// loadJoinTokenDeadlines reads the deadlines of the join tokens. There are
+// none if the file doesn't exist.
+func loadJoinTokenDeadlines() ([]joinTokenDeadline, error) {
+	filename := filepath.Join(cliconfig.Dir(), joinTokenDeadlinesFile)
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var deadlines []joinTokenDeadline
+	if err := json.Unmarshal(data, &deadlines); err != nil {
+		return nil, errors.Wrapf(err, "invalid join token deadlines in %s", filename)
+	}
+	return deadlines, nil
+}
+
+// saveJoinTokenDeadline records the deadline of a join token, in place of
+// the deadline of the previous token of the same role.
+func saveJoinTokenDeadline(deadline joinTokenDeadline) error {
+	deadlines, err := loadJoinTokenDeadlines()
+	if err != nil {
+		return err
+	}
+	updated := []joinTokenDeadline{deadline}
+	for _, d := range deadlines {
+		if d.SwarmID != deadline.SwarmID || d.Role != deadline.Role {
+			updated = append(updated, d)
+		}
+	}
+
+	data, err := json.MarshalIndent(updated, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cliconfig.Dir(), 0700); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(cliconfig.Dir(), joinTokenDeadlinesFile), data, 0600)
+}
+
+// findJoinTokenDeadline returns the deadline of the current join token of a
+// role, if one was set.
+func findJoinTokenDeadline(deadlines []joinTokenDeadline, swarmID, role, token string) (joinTokenDeadline, bool) {
+	digest := joinTokenDigest(token)
+	for _, d := range deadlines {
+		if d.SwarmID == swarmID && d.Role == role && d.TokenDigest == digest {
+			return d, true
+		}
+	}
+	return joinTokenDeadline{}, false
+}
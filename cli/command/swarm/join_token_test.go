@@ -3,8 +3,11 @@ package swarm
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
+	cliconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
@@ -14,6 +17,7 @@ import (
 	"github.com/docker/cli/internal/test/testutil"
 	"github.com/gotestyourself/gotestyourself/golden"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSwarmJoinTokenErrors(t *testing.T) {
@@ -71,6 +75,22 @@ func TestSwarmJoinTokenErrors(t *testing.T) {
 			},
 			expectedError: "error updating the swarm",
 		},
+		{
+			name: "negative-rotate-after",
+			args: []string{"worker"},
+			flags: map[string]string{
+				flagRotateAfter: "-1h",
+			},
+			expectedError: "--rotate-after must be a positive duration",
+		},
+		{
+			name: "no-reachable-manager",
+			args: []string{"worker"},
+			flags: map[string]string{
+				flagURI: "true",
+			},
+			expectedError: "no manager of the swarm is reachable",
+		},
 		{
 			name: "node-inspect-failed",
 			args: []string{"worker"},
@@ -168,6 +188,41 @@ func TestSwarmJoinToken(t *testing.T) {
 				return *Swarm(), nil
 			},
 		},
+		{
+			name: "worker-uri",
+			args: []string{"worker"},
+			flags: map[string]string{
+				flagURI: "true",
+			},
+			infoFunc: func() (types.Info, error) {
+				return types.Info{
+					Swarm: swarm.Info{
+						NodeID: "manager2",
+					},
+				}, nil
+			},
+			swarmInspectFunc: func() (swarm.Swarm, error) {
+				return *Swarm(), nil
+			},
+		},
+		{
+			name: "manager-uri-quiet",
+			args: []string{"manager"},
+			flags: map[string]string{
+				flagURI:   "true",
+				flagQuiet: "true",
+			},
+			infoFunc: func() (types.Info, error) {
+				return types.Info{
+					Swarm: swarm.Info{
+						NodeID: "manager2",
+					},
+				}, nil
+			},
+			swarmInspectFunc: func() (swarm.Swarm, error) {
+				return *Swarm(), nil
+			},
+		},
 		{
 			name: "worker-quiet",
 			args: []string{"worker"},
@@ -200,6 +255,7 @@ func TestSwarmJoinToken(t *testing.T) {
 			swarmInspectFunc: tc.swarmInspectFunc,
 			infoFunc:         tc.infoFunc,
 			nodeInspectFunc:  tc.nodeInspectFunc,
+			nodeListFunc:     joinTokenManagers,
 		})
 		cmd := newJoinTokenCommand(cli)
 		cmd.SetArgs(tc.args)
@@ -210,3 +266,65 @@ func TestSwarmJoinToken(t *testing.T) {
 		golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("jointoken-%s.golden", tc.name))
 	}
 }
+
+func joinTokenManagers() ([]swarm.Node, error) {
+	unreachable := Node(NodeID("manager4"), Manager(func(status *swarm.ManagerStatus) {
+		status.Addr = "10.0.0.4:2377"
+		status.Reachability = swarm.ReachabilityUnreachable
+	}))
+	return []swarm.Node{
+		*Node(NodeID("manager3"), Manager(func(status *swarm.ManagerStatus) { status.Addr = "10.0.0.3:2377" })),
+		*Node(NodeID("manager1"), Manager(func(status *swarm.ManagerStatus) { status.Addr = "10.0.0.1:2377" })),
+		*Node(NodeID("manager2"), Manager(func(status *swarm.ManagerStatus) { status.Addr = "10.0.0.2:2377" })),
+		*unreachable,
+	}, nil
+}
+
+func TestSwarmJoinTokenRotateAfter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "join-token")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	oldDir := cliconfig.Dir()
+	cliconfig.SetDir(dir)
+	defer cliconfig.SetDir(oldDir)
+
+	cli := test.NewFakeCli(&fakeClient{
+		swarmInspectFunc: func() (swarm.Swarm, error) {
+			return *Swarm(), nil
+		},
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return *Node(Manager()), []byte{}, nil
+		},
+	})
+	cmd := newJoinTokenCommand(cli)
+	cmd.SetArgs([]string{"--rotate-after", "24h", "worker"})
+	before := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, cli.OutBuffer().String(), "Rotate this join token by ")
+
+	deadlines, err := loadJoinTokenDeadlines()
+	require.NoError(t, err)
+	require.Len(t, deadlines, 1)
+	deadline, ok := findJoinTokenDeadline(deadlines, "swarm", "worker", "worker-join-token")
+	require.True(t, ok)
+	assert.False(t, deadline.Deadline.Before(before))
+	assert.True(t, deadline.Deadline.Before(time.Now().Add(25*time.Hour)))
+
+	cmd = newJoinTokenCommand(cli)
+	cmd.SetArgs([]string{"--rotate-after", "1h", "--quiet", "manager"})
+	require.NoError(t, cmd.Execute())
+	cmd = newJoinTokenCommand(cli)
+	cmd.SetArgs([]string{"--rotate-after", "48h", "--quiet", "worker"})
+	require.NoError(t, cmd.Execute())
+
+	deadlines, err = loadJoinTokenDeadlines()
+	require.NoError(t, err)
+	require.Len(t, deadlines, 2)
+	deadline, ok = findJoinTokenDeadline(deadlines, "swarm", "worker", "worker-join-token")
+	require.True(t, ok)
+	assert.True(t, deadline.Deadline.After(time.Now().Add(47*time.Hour)))
+	_, ok = findJoinTokenDeadline(deadlines, "swarm", "manager", "manager-join-token")
+	assert.True(t, ok)
+	_, ok = findJoinTokenDeadline(deadlines, "swarm", "manager", "rotated-manager-join-token")
+	assert.False(t, ok)
+}
@@ -24,6 +24,8 @@ const (
 	flagDataPathAddr        = "data-path-addr"
 	flagQuiet               = "quiet"
 	flagRotate              = "rotate"
+	flagRotateAfter         = "rotate-after"
+	flagURI                 = "uri"
 	flagToken               = "token"
 	flagTaskHistoryLimit    = "task-history-limit"
 	flagExternalCA          = "external-ca"
@@ -2,6 +2,7 @@ package progress
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"os/signal"
@@ -26,6 +27,10 @@ const (
 	rootsAction = " "
 )
 
+// ErrInterrupted is returned by RootRotationProgress when it is interrupted
+// before the root rotation converged.
+var ErrInterrupted = errors.New("interrupted before the root rotation converged")
+
 // RootRotationProgress outputs progress information for convergence of a root rotation.
 func RootRotationProgress(ctx context.Context, dclient client.APIClient, progressWriter io.WriteCloser) error {
 	defer progressWriter.Close()
@@ -62,10 +67,13 @@ func RootRotationProgress(ctx context.Context, dclient client.APIClient, progres
 
 		select {
 		case <-time.After(200 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
 		case <-sigint:
 			if !done {
 				progress.Message(progressOut, "", "Operation continuing in background.")
-				progress.Message(progressOut, "", "Use `swarmctl cluster inspect default` to check progress.")
+				progress.Message(progressOut, "", "Use `docker swarm ca --status` to check progress.")
+				return ErrInterrupted
 			}
 			return nil
 		}
@@ -0,0 +1,164 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/go-units"
+	"golang.org/x/net/context"
+)
+
+const treeHeader = "ID\tDESIRED STATE\tCURRENT STATE\tCREATED\tEXIT CODE\tIMAGE\tNODE\tERROR"
+
+// taskGroup holds the tasks of a slot of a replicated service, or of a node
+// of a global service, the most recent first.
+type taskGroup struct {
+	name  string
+	tasks []swarm.Task
+}
+
+// PrintTree prints tasks grouped by slot, or by node for global services. Each
+// group lists all of its tasks, the most recent first, with their state, exit
+// code, image and error, so that slots in which tasks keep failing stand out.
+// Nodes are shown by name unless the resolver doesn't resolve names.
+func PrintTree(ctx context.Context, dockerCli command.Cli, tasks []swarm.Task, resolver *idresolver.IDResolver, trunc bool) error {
+	sort.Stable(tasksBySlot(tasks))
+
+	var groups []*taskGroup
+	byName := map[string]*taskGroup{}
+	nodes := map[string]string{}
+	for _, task := range tasks {
+		serviceName, err := resolver.Resolve(ctx, swarm.Service{}, task.ServiceID)
+		if err != nil {
+			return err
+		}
+		nodes[task.ID], err = resolver.Resolve(ctx, swarm.Node{}, task.NodeID)
+		if err != nil {
+			return err
+		}
+
+		// The tasks of a global service are grouped by node ID, as node
+		// names may not be unique
+		key := fmt.Sprintf("%v.%v", serviceName, task.NodeID)
+		name := fmt.Sprintf("%v.%v", serviceName, nodes[task.ID])
+		if task.Slot != 0 {
+			key = fmt.Sprintf("%v.%v", serviceName, task.Slot)
+			name = key
+		}
+		group, ok := byName[key]
+		if !ok {
+			group = &taskGroup{name: name}
+			byName[key] = group
+			groups = append(groups, group)
+		}
+		group.tasks = append(group.tasks, task)
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, treeHeader)
+	for _, group := range groups {
+		writeTaskGroup(w, group, nodes, trunc)
+	}
+	return w.Flush()
+}
+
+func writeTaskGroup(w io.Writer, group *taskGroup, nodes map[string]string, trunc bool) {
+	failed := 0
+	for _, task := range group.tasks {
+		if task.Status.State == swarm.TaskStateFailed || task.Status.State == swarm.TaskStateRejected {
+			failed++
+		}
+	}
+	// The line of the group has a cell for each column, so that the columns
+	// of all the groups are aligned with the header
+	fmt.Fprintf(w, "%s (%d tasks, %d failed)%s\n", group.name, len(group.tasks), failed, strings.Repeat("\t", strings.Count(treeHeader, "\t")))
+
+	for i, task := range group.tasks {
+		prefix := "├─"
+		if i == len(group.tasks)-1 {
+			prefix = "└─"
+		}
+		id := task.ID
+		if trunc {
+			id = stringid.TruncateID(id)
+		}
+		taskErr := task.Status.Err
+		if taskErr != "" {
+			taskErr = fmt.Sprintf("%q", taskErr)
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			prefix,
+			id,
+			command.PrettyPrint(task.DesiredState),
+			fmt.Sprintf("%s %s", command.PrettyPrint(task.Status.State), timeAgo(task.Status.Timestamp)),
+			"created "+timeAgo(task.Meta.CreatedAt),
+			exitCode(task),
+			taskImage(task, trunc),
+			nodes[task.ID],
+			taskErr,
+		)
+	}
+}
+
+func timeAgo(t time.Time) string {
+	return strings.ToLower(units.HumanDuration(time.Since(t))) + " ago"
+}
+
+// exitCode returns the exit code of the container of a task once it exited.
+func exitCode(task swarm.Task) string {
+	switch task.Status.State {
+	case swarm.TaskStateComplete, swarm.TaskStateFailed, swarm.TaskStateShutdown:
+		if task.Status.ContainerStatus.ContainerID != "" {
+			return fmt.Sprintf("exit %d", task.Status.ContainerStatus.ExitCode)
+		}
+	}
+	return ""
+}
+
+// taskImage returns the image of a task with its digest, which is shortened
+// if trunc is set.
+func taskImage(task swarm.Task, trunc bool) string {
+	if task.Spec.ContainerSpec == nil {
+		return ""
+	}
+	image := task.Spec.ContainerSpec.Image
+	if !trunc {
+		return image
+	}
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	canonical, ok := ref.(reference.Canonical)
+	if !ok {
+		return reference.FamiliarString(ref)
+	}
+	digest := canonical.Digest()
+	name := reference.FamiliarString(reference.TrimNamed(ref))
+	if tagged, ok := ref.(reference.Tagged); ok {
+		name += ":" + tagged.Tag()
+	}
+	return fmt.Sprintf("%s@%s:%s", name, digest.Algorithm(), stringid.TruncateID(digest.Hex()))
+}
+
+// FilterSince returns the tasks that changed state since the given time,
+// and those that are meant to be running, so that the history of tasks is
+// limited to that time.
+func FilterSince(tasks []swarm.Task, since time.Time) []swarm.Task {
+	var filtered []swarm.Task
+	for _, task := range tasks {
+		if !task.Status.Timestamp.Before(since) || task.DesiredState == swarm.TaskStateRunning || task.DesiredState == swarm.TaskStateReady {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
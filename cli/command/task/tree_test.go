@@ -0,0 +1,124 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/cli/internal/test"
+	// Import builders to get the builder function as package function
+	. "github.com/docker/cli/internal/test/builders"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/gotestyourself/gotestyourself/golden"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func exited(code int) func(*swarm.TaskStatus) {
+	return func(status *swarm.TaskStatus) {
+		status.ContainerStatus = swarm.ContainerStatus{ContainerID: "container-id", ExitCode: code}
+	}
+}
+
+func created(t time.Time) func(*swarm.Task) {
+	return func(task *swarm.Task) {
+		task.Meta.CreatedAt = t
+	}
+}
+
+func TestTaskPrintTree(t *testing.T) {
+	apiClient := &fakeClient{
+		serviceInspectWithRaw: func(ref string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return *Service(ServiceName("web")), nil, nil
+		},
+		nodeInspectWithRaw: func(ref string) (swarm.Node, []byte, error) {
+			return *Node(NodeName("node-" + ref)), nil, nil
+		},
+	}
+	cli := test.NewFakeCli(apiClient)
+	now := time.Now()
+	image := "nginx:1.13@sha256:2d8a3ea6f5e8b4ad8ed9b8b5e2c1f5d3e8a7a9b3c5d6e7f8091a2b3c4d5e6f70"
+	tasks := []swarm.Task{
+		*Task(
+			TaskID("task-2-old"),
+			TaskSlot(2),
+			TaskNodeID("b"),
+			created(now.Add(-50*time.Minute)),
+			WithTaskSpec(TaskImage(image)),
+			TaskDesiredState(swarm.TaskStateShutdown),
+			WithStatus(TaskState(swarm.TaskStateShutdown), Timestamp(now.Add(-40*time.Minute)), exited(0)),
+		),
+		*Task(
+			TaskID("task-1-failed"),
+			TaskSlot(1),
+			TaskNodeID("a"),
+			created(now.Add(-30*time.Minute)),
+			WithTaskSpec(TaskImage(image)),
+			TaskDesiredState(swarm.TaskStateShutdown),
+			WithStatus(TaskState(swarm.TaskStateFailed), Timestamp(now.Add(-20*time.Minute)), exited(1), StatusErr("task: non-zero exit (1)")),
+		),
+		*Task(
+			TaskID("task-1-running"),
+			TaskSlot(1),
+			TaskNodeID("a"),
+			created(now.Add(-10*time.Minute)),
+			WithTaskSpec(TaskImage(image)),
+			TaskDesiredState(swarm.TaskStateRunning),
+			WithStatus(TaskState(swarm.TaskStateRunning), Timestamp(now.Add(-5*time.Minute))),
+		),
+		*Task(
+			TaskID("task-2-running"),
+			TaskSlot(2),
+			TaskNodeID("b"),
+			created(now.Add(-40*time.Minute)),
+			WithTaskSpec(TaskImage(image)),
+			TaskDesiredState(swarm.TaskStateRunning),
+			WithStatus(TaskState(swarm.TaskStateRunning), Timestamp(now.Add(-39*time.Minute))),
+		),
+	}
+	err := PrintTree(context.Background(), cli, tasks, idresolver.New(apiClient, false), true)
+	assert.NoError(t, err)
+	golden.Assert(t, cli.OutBuffer().String(), "task-print-tree.golden")
+}
+
+func TestTaskPrintTreeGlobalService(t *testing.T) {
+	apiClient := &fakeClient{
+		serviceInspectWithRaw: func(ref string, options types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return *Service(ServiceName("agent")), nil, nil
+		},
+		nodeInspectWithRaw: func(ref string) (swarm.Node, []byte, error) {
+			return *Node(NodeName("node-" + ref)), nil, nil
+		},
+	}
+	now := time.Now()
+	tasks := []swarm.Task{
+		*Task(TaskID("task-a"), TaskSlot(0), TaskNodeID("a"), created(now), WithStatus(Timestamp(now))),
+		*Task(TaskID("task-b"), TaskSlot(0), TaskNodeID("b"), created(now), WithStatus(Timestamp(now))),
+	}
+
+	cli := test.NewFakeCli(apiClient)
+	assert.NoError(t, PrintTree(context.Background(), cli, tasks, idresolver.New(apiClient, false), true))
+	assert.Contains(t, cli.OutBuffer().String(), "agent.node-a (1 tasks, 0 failed)")
+	assert.Contains(t, cli.OutBuffer().String(), "agent.node-b (1 tasks, 0 failed)")
+
+	cli = test.NewFakeCli(apiClient)
+	assert.NoError(t, PrintTree(context.Background(), cli, tasks, idresolver.New(apiClient, true), true))
+	assert.Contains(t, cli.OutBuffer().String(), ".a (1 tasks, 0 failed)")
+	assert.NotContains(t, cli.OutBuffer().String(), "node-a")
+}
+
+func TestFilterSince(t *testing.T) {
+	now := time.Now()
+	tasks := []swarm.Task{
+		*Task(TaskID("old-shutdown"), TaskDesiredState(swarm.TaskStateShutdown), WithStatus(Timestamp(now.Add(-2*time.Hour)))),
+		*Task(TaskID("old-running"), TaskDesiredState(swarm.TaskStateRunning), WithStatus(Timestamp(now.Add(-2*time.Hour)))),
+		*Task(TaskID("recent-failed"), TaskDesiredState(swarm.TaskStateShutdown), WithStatus(Timestamp(now.Add(-time.Minute)))),
+	}
+
+	var ids []string
+	for _, task := range FilterSince(tasks, now.Add(-time.Hour)) {
+		ids = append(ids, task.ID)
+	}
+	assert.Equal(t, []string{"old-running", "recent-failed"}, ids)
+}
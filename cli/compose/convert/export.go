@@ -0,0 +1,464 @@
+package convert
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// defaultFileMode is the mode of secrets and configs when none is specified.
+const defaultFileMode = os.FileMode(0444)
+
+// exporter accumulates the top-level objects that are referenced by the
+// services being converted, and the warnings for the fields that can't be
+// represented in a compose file.
+type exporter struct {
+	namespace    Namespace
+	networkNames map[string]string
+	config       *composetypes.Config
+	warnings     []string
+}
+
+// ServiceSpecs converts swarm ServiceSpecs into a compose-file Config, it is
+// the inverse of Services. Services, networks and volumes that belong to the
+// namespace are descoped, while all other networks and volumes, and all
+// secrets and configs, are declared as external. networkNames maps network IDs
+// to names. A warning is returned for every field that can't be represented
+// in a compose file.
+func ServiceSpecs(namespace Namespace, specs []swarm.ServiceSpec, networkNames map[string]string) (*composetypes.Config, []string) {
+	e := &exporter{
+		namespace:    namespace,
+		networkNames: networkNames,
+		config: &composetypes.Config{
+			Networks: make(map[string]composetypes.NetworkConfig),
+			Volumes:  make(map[string]composetypes.VolumeConfig),
+			Secrets:  make(map[string]composetypes.SecretConfig),
+			Configs:  make(map[string]composetypes.ConfigObjConfig),
+		},
+	}
+	for _, spec := range specs {
+		e.config.Services = append(e.config.Services, e.service(spec))
+	}
+	sort.Slice(e.config.Services, func(i, j int) bool {
+		return e.config.Services[i].Name < e.config.Services[j].Name
+	})
+	return e.config, e.warnings
+}
+
+func (e *exporter) warnf(service, format string, args ...interface{}) {
+	e.warnings = append(e.warnings, fmt.Sprintf("service %s: ", service)+fmt.Sprintf(format, args...))
+}
+
+// descope returns the name of an object relative to the namespace, and
+// whether it belongs to the namespace.
+func (e *exporter) descope(name string) (string, bool) {
+	if e.namespace.name == "" || !strings.HasPrefix(name, e.namespace.name+"_") {
+		return name, false
+	}
+	return e.namespace.Descope(name), true
+}
+
+// nolint: gocyclo
+func (e *exporter) service(spec swarm.ServiceSpec) composetypes.ServiceConfig {
+	name, _ := e.descope(spec.Name)
+	task := spec.TaskTemplate
+	cspec := task.ContainerSpec
+	if cspec == nil {
+		cspec = &swarm.ContainerSpec{}
+	}
+	if task.PluginSpec != nil {
+		e.warnf(name, "plugin services are not supported")
+	}
+
+	service := composetypes.ServiceConfig{
+		Name:            name,
+		Image:           cspec.Image,
+		Entrypoint:      cspec.Command,
+		Command:         cspec.Args,
+		Hostname:        cspec.Hostname,
+		Environment:     exportEnvironment(cspec.Env),
+		Labels:          withoutStackLabels(cspec.Labels),
+		WorkingDir:      cspec.Dir,
+		User:            cspec.User,
+		StopGracePeriod: cspec.StopGracePeriod,
+		StopSignal:      cspec.StopSignal,
+		Tty:             cspec.TTY,
+		StdinOpen:       cspec.OpenStdin,
+		ReadOnly:        cspec.ReadOnly,
+		HealthCheck:     exportHealthcheck(cspec.Healthcheck),
+		Deploy: composetypes.DeployConfig{
+			Labels:        withoutStackLabels(spec.Labels),
+			Resources:     exportResources(task.Resources),
+			RestartPolicy: exportRestartPolicy(task.RestartPolicy),
+			UpdateConfig:  exportUpdateConfig(spec.UpdateConfig),
+		},
+	}
+	// Services deployed from a stack are pinned to a digest, the image label
+	// holds the reference that was in the compose file.
+	if image, ok := spec.Labels[LabelImage]; ok {
+		service.Image = image
+	}
+
+	if spec.Mode.Global != nil {
+		service.Deploy.Mode = "global"
+	} else if spec.Mode.Replicated != nil {
+		service.Deploy.Replicas = spec.Mode.Replicated.Replicas
+	}
+	if spec.RollbackConfig != nil {
+		e.warnf(name, "the rollback configuration can not be represented")
+	}
+
+	if len(cspec.Groups) > 0 {
+		e.warnf(name, "supplementary groups can not be represented")
+	}
+	for _, host := range cspec.Hosts {
+		if service.ExtraHosts == nil {
+			service.ExtraHosts = make(composetypes.MappingWithColon)
+		}
+		// Hosts are in "IP hostname [aliases...]" format
+		fields := strings.Fields(host)
+		if len(fields) < 2 {
+			e.warnf(name, "the invalid host entry %q is skipped", host)
+			continue
+		}
+		for _, hostname := range fields[1:] {
+			service.ExtraHosts[hostname] = fields[0]
+		}
+	}
+	if dns := cspec.DNSConfig; dns != nil {
+		service.DNS = dns.Nameservers
+		service.DNSSearch = dns.Search
+		if len(dns.Options) > 0 {
+			e.warnf(name, "DNS options can not be represented")
+		}
+	}
+	if privileges := cspec.Privileges; privileges != nil {
+		if spec := privileges.CredentialSpec; spec != nil {
+			service.CredentialSpec = composetypes.CredentialSpecConfig{File: spec.File, Registry: spec.Registry}
+		}
+		if privileges.SELinuxContext != nil {
+			e.warnf(name, "the SELinux context can not be represented")
+		}
+	}
+	if task.LogDriver != nil {
+		service.Logging = &composetypes.LoggingConfig{
+			Driver:  task.LogDriver.Name,
+			Options: task.LogDriver.Options,
+		}
+	}
+	if placement := task.Placement; placement != nil {
+		service.Deploy.Placement.Constraints = placement.Constraints
+		for _, pref := range placement.Preferences {
+			if pref.Spread != nil {
+				service.Deploy.Placement.Preferences = append(service.Deploy.Placement.Preferences,
+					composetypes.PlacementPreferences{Spread: pref.Spread.SpreadDescriptor})
+			}
+		}
+	}
+	if endpoint := spec.EndpointSpec; endpoint != nil {
+		if endpoint.Mode != swarm.ResolutionModeVIP {
+			service.Deploy.EndpointMode = string(endpoint.Mode)
+		}
+		for _, port := range endpoint.Ports {
+			service.Ports = append(service.Ports, composetypes.ServicePortConfig{
+				Mode:      string(port.PublishMode),
+				Target:    port.TargetPort,
+				Published: port.PublishedPort,
+				Protocol:  string(port.Protocol),
+			})
+		}
+	}
+
+	networks := task.Networks
+	if len(networks) == 0 {
+		// ServiceSpec.Networks is used by older daemons
+		networks = spec.Networks
+	}
+	service.Networks = e.networks(name, networks)
+	service.Volumes = e.volumes(name, cspec.Mounts)
+	service.Secrets = e.secrets(name, cspec.Secrets)
+	service.Configs = e.configs(name, cspec.Configs)
+
+	return service
+}
+
+func (e *exporter) networks(service string, attachments []swarm.NetworkAttachmentConfig) map[string]*composetypes.ServiceNetworkConfig {
+	networks := make(map[string]*composetypes.ServiceNetworkConfig)
+	for _, attachment := range attachments {
+		networkName := attachment.Target
+		if name, ok := e.networkNames[attachment.Target]; ok {
+			networkName = name
+		}
+		key, scoped := e.descope(networkName)
+		if scoped {
+			if key != defaultNetwork {
+				e.config.Networks[key] = composetypes.NetworkConfig{}
+			}
+		} else {
+			e.config.Networks[key] = composetypes.NetworkConfig{External: composetypes.External{External: true}}
+		}
+
+		// Services are given their name as alias on user defined networks
+		var aliases []string
+		for _, alias := range attachment.Aliases {
+			if alias != service || !container.NetworkMode(networkName).IsUserDefined() {
+				aliases = append(aliases, alias)
+			}
+		}
+		if len(aliases) > 0 {
+			networks[key] = &composetypes.ServiceNetworkConfig{Aliases: aliases}
+		} else {
+			networks[key] = nil
+		}
+		if len(attachment.DriverOpts) > 0 {
+			e.warnf(service, "the driver options of network %s can not be represented", key)
+		}
+	}
+	// The default network of the stack is used when none is specified
+	if _, ok := networks[defaultNetwork]; ok && len(networks) == 1 && networks[defaultNetwork] == nil {
+		return nil
+	}
+	return networks
+}
+
+func (e *exporter) volumes(service string, mounts []mount.Mount) []composetypes.ServiceVolumeConfig {
+	var volumes []composetypes.ServiceVolumeConfig
+	for _, m := range mounts {
+		volume := composetypes.ServiceVolumeConfig{
+			Type:        string(m.Type),
+			Source:      m.Source,
+			Target:      m.Target,
+			ReadOnly:    m.ReadOnly,
+			Consistency: string(m.Consistency),
+		}
+		if m.BindOptions != nil && m.BindOptions.Propagation != "" {
+			volume.Bind = &composetypes.ServiceVolumeBind{Propagation: string(m.BindOptions.Propagation)}
+		}
+		if m.TmpfsOptions != nil {
+			e.warnf(service, "the tmpfs options of %s can not be represented", m.Target)
+		}
+		if m.Type == mount.TypeVolume && m.Source != "" {
+			volume.Source = e.volume(service, m)
+		}
+		if m.VolumeOptions != nil && m.VolumeOptions.NoCopy {
+			volume.Volume = &composetypes.ServiceVolumeVolume{NoCopy: true}
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+// volume declares the named volume of a mount, and returns its name.
+func (e *exporter) volume(service string, m mount.Mount) string {
+	key, scoped := e.descope(m.Source)
+	if !scoped {
+		if m.VolumeOptions != nil && m.VolumeOptions.DriverConfig != nil {
+			e.warnf(service, "the driver of volume %s is ignored, as it is declared as external", key)
+		}
+		e.config.Volumes[key] = composetypes.VolumeConfig{External: composetypes.External{External: true}}
+		return key
+	}
+
+	var config composetypes.VolumeConfig
+	if options := m.VolumeOptions; options != nil {
+		config.Labels = withoutStackLabels(options.Labels)
+		if options.DriverConfig != nil {
+			config.Driver = options.DriverConfig.Name
+			config.DriverOpts = options.DriverConfig.Options
+		}
+	}
+	e.config.Volumes[key] = config
+	return key
+}
+
+func (e *exporter) secrets(service string, refs []*swarm.SecretReference) []composetypes.ServiceSecretConfig {
+	var secrets []composetypes.ServiceSecretConfig
+	for _, ref := range refs {
+		key, external := e.fileObject(service, "secret", ref.SecretName)
+		e.config.Secrets[key] = composetypes.SecretConfig{External: external}
+		if ref.File == nil {
+			e.warnf(service, "secret %s is not exposed as a file", key)
+			continue
+		}
+		secrets = append(secrets, composetypes.ServiceSecretConfig(exportFileReference(key, ref.File.Name, ref.File.UID, ref.File.GID, ref.File.Mode)))
+	}
+	return secrets
+}
+
+func (e *exporter) configs(service string, refs []*swarm.ConfigReference) []composetypes.ServiceConfigObjConfig {
+	var configs []composetypes.ServiceConfigObjConfig
+	for _, ref := range refs {
+		key, external := e.fileObject(service, "config", ref.ConfigName)
+		e.config.Configs[key] = composetypes.ConfigObjConfig{External: external}
+		if ref.File == nil {
+			e.warnf(service, "config %s is not exposed as a file", key)
+			continue
+		}
+		configs = append(configs, composetypes.ServiceConfigObjConfig(exportFileReference(key, ref.File.Name, ref.File.UID, ref.File.GID, ref.File.Mode)))
+	}
+	return configs
+}
+
+// fileObject returns the name by which services refer to a secret or config,
+// and how it is declared. The content of secrets and configs can't be read
+// back, so those that belong to the namespace are declared as external too,
+// under their name in the namespace.
+func (e *exporter) fileObject(service, kind, name string) (string, composetypes.External) {
+	key, scoped := e.descope(name)
+	external := composetypes.External{External: true}
+	if scoped {
+		e.warnf(service, "the content of %s %s can not be exported, it is declared as external", kind, key)
+		external.Name = name
+	}
+	return key, external
+}
+
+func exportFileReference(source, target, uid, gid string, mode os.FileMode) composetypes.ServiceSecretConfig {
+	ref := composetypes.ServiceSecretConfig{Source: source}
+	if target != source {
+		ref.Target = target
+	}
+	if uid != "0" {
+		ref.UID = uid
+	}
+	if gid != "0" {
+		ref.GID = gid
+	}
+	if mode != defaultFileMode {
+		m := uint32(mode)
+		ref.Mode = &m
+	}
+	return ref
+}
+
+// withoutStackLabels returns the labels without those added by stack deploy.
+func withoutStackLabels(labels map[string]string) composetypes.Labels {
+	var result composetypes.Labels
+	for k, v := range labels {
+		if k == LabelNamespace || k == LabelImage {
+			continue
+		}
+		if result == nil {
+			result = make(composetypes.Labels)
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func exportEnvironment(env []string) composetypes.MappingWithEquals {
+	if len(env) == 0 {
+		return nil
+	}
+	result := make(composetypes.MappingWithEquals, len(env))
+	for _, variable := range env {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) == 1 {
+			result[parts[0]] = nil
+			continue
+		}
+		value := parts[1]
+		result[parts[0]] = &value
+	}
+	return result
+}
+
+func exportHealthcheck(healthcheck *container.HealthConfig) *composetypes.HealthCheckConfig {
+	if healthcheck == nil {
+		return nil
+	}
+	if len(healthcheck.Test) == 1 && healthcheck.Test[0] == "NONE" {
+		return &composetypes.HealthCheckConfig{Disable: true}
+	}
+	config := &composetypes.HealthCheckConfig{
+		Test:        healthcheck.Test,
+		Timeout:     durationOrNil(healthcheck.Timeout),
+		Interval:    durationOrNil(healthcheck.Interval),
+		StartPeriod: durationOrNil(healthcheck.StartPeriod),
+	}
+	if healthcheck.Retries != 0 {
+		retries := uint64(healthcheck.Retries)
+		config.Retries = &retries
+	}
+	return config
+}
+
+func durationOrNil(d time.Duration) *time.Duration {
+	if d == 0 {
+		return nil
+	}
+	return &d
+}
+
+func exportResources(resources *swarm.ResourceRequirements) composetypes.Resources {
+	var result composetypes.Resources
+	if resources == nil {
+		return result
+	}
+	if limits := resources.Limits; limits != nil && (limits.NanoCPUs != 0 || limits.MemoryBytes != 0) {
+		result.Limits = &composetypes.Resource{
+			NanoCPUs:    exportCPUs(limits.NanoCPUs),
+			MemoryBytes: composetypes.UnitBytes(limits.MemoryBytes),
+		}
+	}
+	if reservations := resources.Reservations; reservations != nil && (reservations.NanoCPUs != 0 || reservations.MemoryBytes != 0 || len(reservations.GenericResources) > 0) {
+		result.Reservations = &composetypes.Resource{
+			NanoCPUs:    exportCPUs(reservations.NanoCPUs),
+			MemoryBytes: composetypes.UnitBytes(reservations.MemoryBytes),
+		}
+		for _, res := range reservations.GenericResources {
+			if res.DiscreteResourceSpec == nil {
+				continue
+			}
+			result.Reservations.GenericResources = append(result.Reservations.GenericResources, composetypes.GenericResource{
+				DiscreteResourceSpec: &composetypes.DiscreteGenericResource{
+					Kind:  res.DiscreteResourceSpec.Kind,
+					Value: res.DiscreteResourceSpec.Value,
+				},
+			})
+		}
+	}
+	return result
+}
+
+func exportCPUs(nanoCPUs int64) string {
+	if nanoCPUs == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(nanoCPUs)/1e9, 'f', -1, 64)
+}
+
+func exportRestartPolicy(policy *swarm.RestartPolicy) *composetypes.RestartPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &composetypes.RestartPolicy{
+		Condition:   string(policy.Condition),
+		Delay:       policy.Delay,
+		MaxAttempts: policy.MaxAttempts,
+		Window:      policy.Window,
+	}
+}
+
+func exportUpdateConfig(config *swarm.UpdateConfig) *composetypes.UpdateConfig {
+	if config == nil {
+		return nil
+	}
+	parallelism := config.Parallelism
+	return &composetypes.UpdateConfig{
+		Parallelism:     &parallelism,
+		Delay:           config.Delay,
+		FailureAction:   config.FailureAction,
+		Monitor:         config.Monitor,
+		MaxFailureRatio: config.MaxFailureRatio,
+		Order:           config.Order,
+	}
+}
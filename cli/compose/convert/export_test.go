@@ -0,0 +1,129 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceSpecs(t *testing.T) {
+	namespace := NewNamespace("app")
+	replicas := uint64(3)
+	delay := 5 * time.Second
+
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			Name: "app_web",
+			Labels: map[string]string{
+				LabelNamespace: "app",
+				LabelImage:     "nginx:1.13",
+				"tier":         "front",
+			},
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image:   "nginx:1.13@sha256:abcd",
+				Args:    []string{"nginx", "-g", "daemon off;"},
+				Env:     []string{"DEBUG", "PORT=80"},
+				Hosts:   []string{"10.0.0.1 db database"},
+				Groups:  []string{"staff"},
+				Labels:  map[string]string{LabelNamespace: "app"},
+				Secrets: []*swarm.SecretReference{{SecretName: "app_tls", File: &swarm.SecretReferenceFileTarget{Name: "tls", UID: "0", GID: "0", Mode: 0400}}},
+				Configs: []*swarm.ConfigReference{{ConfigName: "nginx_conf", File: &swarm.ConfigReferenceFileTarget{Name: "/etc/nginx/nginx.conf", UID: "0", GID: "0", Mode: 0444}}},
+				Mounts: []mount.Mount{
+					{Type: mount.TypeVolume, Source: "app_data", Target: "/data", VolumeOptions: &mount.VolumeOptions{
+						Labels:       map[string]string{LabelNamespace: "app"},
+						DriverConfig: &mount.Driver{Name: "local"},
+					}},
+					{Type: mount.TypeBind, Source: "/srv", Target: "/srv", ReadOnly: true},
+				},
+			},
+			Resources: &swarm.ResourceRequirements{
+				Limits:       &swarm.Resources{NanoCPUs: 500000000, MemoryBytes: 64 << 20},
+				Reservations: &swarm.Resources{},
+			},
+			RestartPolicy: &swarm.RestartPolicy{Condition: swarm.RestartPolicyConditionOnFailure, Delay: &delay},
+			Placement:     &swarm.Placement{Constraints: []string{"node.role==worker"}},
+			Networks: []swarm.NetworkAttachmentConfig{
+				{Target: "id-front", Aliases: []string{"web"}},
+				{Target: "id-shared"},
+			},
+		},
+		Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+		EndpointSpec: &swarm.EndpointSpec{
+			Mode:  swarm.ResolutionModeVIP,
+			Ports: []swarm.PortConfig{{Protocol: swarm.PortConfigProtocolTCP, TargetPort: 80, PublishedPort: 8080, PublishMode: swarm.PortConfigPublishModeIngress}},
+		},
+	}
+
+	networkNames := map[string]string{"id-front": "app_front", "id-shared": "shared"}
+	config, warnings := ServiceSpecs(namespace, []swarm.ServiceSpec{spec}, networkNames)
+
+	debug, port := (*string)(nil), "80"
+	expected := composetypes.ServiceConfig{
+		Name:        "web",
+		Image:       "nginx:1.13",
+		Command:     []string{"nginx", "-g", "daemon off;"},
+		Environment: composetypes.MappingWithEquals{"DEBUG": debug, "PORT": &port},
+		ExtraHosts:  composetypes.MappingWithColon{"db": "10.0.0.1", "database": "10.0.0.1"},
+		Secrets:     []composetypes.ServiceSecretConfig{{Source: "tls", Mode: uint32Ptr(0400)}},
+		Configs:     []composetypes.ServiceConfigObjConfig{{Source: "nginx_conf", Target: "/etc/nginx/nginx.conf"}},
+		Volumes: []composetypes.ServiceVolumeConfig{
+			{Type: "volume", Source: "data", Target: "/data"},
+			{Type: "bind", Source: "/srv", Target: "/srv", ReadOnly: true},
+		},
+		Networks: map[string]*composetypes.ServiceNetworkConfig{"front": nil, "shared": nil},
+		Ports:    []composetypes.ServicePortConfig{{Mode: "ingress", Target: 80, Published: 8080, Protocol: "tcp"}},
+		Deploy: composetypes.DeployConfig{
+			Replicas: &replicas,
+			Labels:   composetypes.Labels{"tier": "front"},
+			Resources: composetypes.Resources{
+				Limits: &composetypes.Resource{NanoCPUs: "0.5", MemoryBytes: 64 << 20},
+			},
+			RestartPolicy: &composetypes.RestartPolicy{Condition: "on-failure", Delay: &delay},
+			Placement:     composetypes.Placement{Constraints: []string{"node.role==worker"}},
+		},
+	}
+	assert.Equal(t, []composetypes.ServiceConfig{expected}, config.Services)
+
+	assert.Equal(t, map[string]composetypes.NetworkConfig{
+		"front":  {},
+		"shared": {External: composetypes.External{External: true}},
+	}, config.Networks)
+	assert.Equal(t, map[string]composetypes.VolumeConfig{"data": {Driver: "local"}}, config.Volumes)
+	assert.Equal(t, map[string]composetypes.SecretConfig{
+		"tls": {External: composetypes.External{External: true, Name: "app_tls"}},
+	}, config.Secrets)
+	assert.Equal(t, map[string]composetypes.ConfigObjConfig{
+		"nginx_conf": {External: composetypes.External{External: true}},
+	}, config.Configs)
+
+	assert.Equal(t, []string{
+		"service web: supplementary groups can not be represented",
+		"service web: the content of secret tls can not be exported, it is declared as external",
+	}, warnings)
+}
+
+func TestServiceSpecsInvalidHosts(t *testing.T) {
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "app_web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image: "nginx:1.13",
+				Hosts: []string{"", "  ", "10.0.0.2", "10.0.0.1 db"},
+			},
+		},
+	}
+
+	config, warnings := ServiceSpecs(NewNamespace("app"), []swarm.ServiceSpec{spec}, nil)
+	assert.Equal(t, composetypes.MappingWithColon{"db": "10.0.0.1"}, config.Services[0].ExtraHosts)
+	assert.Equal(t, []string{
+		`service web: the invalid host entry "" is skipped`,
+		`service web: the invalid host entry "  " is skipped`,
+		`service web: the invalid host entry "10.0.0.2" is skipped`,
+	}, warnings)
+}
@@ -84,6 +84,27 @@ func TestConvertResourcesFull(t *testing.T) {
 	assert.Equal(t, expected, resources)
 }
 
+func TestConvertResourcesGenericResources(t *testing.T) {
+	source := composetypes.Resources{
+		Reservations: &composetypes.Resource{
+			GenericResources: []composetypes.GenericResource{
+				{DiscreteResourceSpec: &composetypes.DiscreteGenericResource{Kind: "gpu", Value: 2}},
+			},
+		},
+	}
+	resources, err := convertResources(source)
+	assert.NoError(t, err)
+
+	expected := &swarm.ResourceRequirements{
+		Reservations: &swarm.Resources{
+			GenericResources: []swarm.GenericResource{
+				{DiscreteResourceSpec: &swarm.DiscreteGenericResource{Kind: "gpu", Value: 2}},
+			},
+		},
+	}
+	assert.Equal(t, expected, resources)
+}
+
 func TestConvertResourcesOnlyMemory(t *testing.T) {
 	source := composetypes.Resources{
 		Limits: &composetypes.Resource{
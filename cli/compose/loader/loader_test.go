@@ -661,6 +661,52 @@ volumes:
 	assert.Contains(t, err.Error(), "external_volume")
 }
 
+func TestLoadGenericResources(t *testing.T) {
+	config, err := loadYAML(`
+version: "3.5"
+services:
+  web:
+    image: busybox
+    deploy:
+      resources:
+        reservations:
+          memory: 20M
+          generic_resources:
+            - discrete_resource_spec:
+                kind: fpga
+                value: 2
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expected := &types.Resource{
+		MemoryBytes: types.UnitBytes(20 * 1024 * 1024),
+		GenericResources: []types.GenericResource{
+			{DiscreteResourceSpec: &types.DiscreteGenericResource{Kind: "fpga", Value: 2}},
+		},
+	}
+	assert.Equal(t, expected, config.Services[0].Deploy.Resources.Reservations)
+}
+
+func TestLoadGenericResourcesUnsupportedVersion(t *testing.T) {
+	_, err := loadYAML(`
+version: "3.4"
+services:
+  web:
+    image: busybox
+    deploy:
+      resources:
+        reservations:
+          generic_resources:
+            - discrete_resource_spec:
+                kind: fpga
+                value: 2
+`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "generic_resources")
+}
+
 func durationPtr(value time.Duration) *time.Duration {
 	return &value
 }
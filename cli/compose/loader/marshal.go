@@ -0,0 +1,213 @@
+package loader
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// MarshalVersion is the version of the compose files written by Marshal.
+const MarshalVersion = "3.5"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	externalType = reflect.TypeOf(types.External{})
+	ulimitsType  = reflect.TypeOf(types.UlimitsConfig{})
+	bytesType    = reflect.TypeOf(types.UnitBytes(0))
+)
+
+// Marshal writes a Config as a compose file that can be read back with Load.
+// Fields are named as they are read by Load, and fields that are not set are
+// omitted.
+func Marshal(config *types.Config) ([]byte, error) {
+	services := yaml.MapSlice{}
+	for _, service := range config.Services {
+		services = append(services, yaml.MapItem{Key: service.Name, Value: marshalValue(reflect.ValueOf(service))})
+	}
+
+	dict := yaml.MapSlice{
+		{Key: "version", Value: MarshalVersion},
+		{Key: "services", Value: services},
+	}
+	sections := []struct {
+		key   string
+		value interface{}
+	}{
+		{"networks", config.Networks},
+		{"volumes", config.Volumes},
+		{"secrets", config.Secrets},
+		{"configs", config.Configs},
+	}
+	for _, section := range sections {
+		if value := marshalValue(reflect.ValueOf(section.value)); value != nil {
+			dict = append(dict, yaml.MapItem{Key: section.key, Value: value})
+		}
+	}
+	return yaml.Marshal(dict)
+}
+
+// marshalValue converts a value to the types that are written by the yaml
+// package, or returns nil if the value is not set.
+// nolint: gocyclo
+func marshalValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		// A pointer to a zero value is set, e.g. `replicas: 0`
+		elem := value.Elem()
+		if elem.Kind() == reflect.Struct {
+			if v := marshalValue(elem); v != nil {
+				return v
+			}
+			return yaml.MapSlice{}
+		}
+		return marshalScalar(elem, true)
+	case reflect.Struct:
+		if value.Type() == ulimitsType && value.FieldByName("Single").Int() != 0 {
+			return value.FieldByName("Single").Int()
+		}
+		if value.Type() == externalType {
+			return marshalExternal(value.Interface().(types.External))
+		}
+		return marshalStruct(value)
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil
+		}
+		list := make([]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item := marshalValue(value.Index(i))
+			if item == nil {
+				item = yaml.MapSlice{}
+			}
+			list = append(list, item)
+		}
+		return list
+	case reflect.Map:
+		if value.Len() == 0 {
+			return nil
+		}
+		var keys []string
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		dict := yaml.MapSlice{}
+		for _, key := range keys {
+			// Map entries are kept even if they are not set, e.g. an
+			// environment variable without value, or a network without
+			// options.
+			item := withoutDefaultExternalName(value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())), key)
+			dict = append(dict, yaml.MapItem{Key: key, Value: marshalValue(item)})
+		}
+		return dict
+	default:
+		return marshalScalar(value, false)
+	}
+}
+
+func marshalStruct(value reflect.Value) interface{} {
+	dict := yaml.MapSlice{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		// The name of a service is its key in the services section
+		if field.PkgPath != "" || (value.Type() == reflect.TypeOf(types.ServiceConfig{}) && field.Name == "Name") {
+			continue
+		}
+		if v := marshalValue(value.Field(i)); v != nil {
+			dict = append(dict, yaml.MapItem{Key: fieldKey(field), Value: v})
+		}
+	}
+	if len(dict) == 0 {
+		return nil
+	}
+	return dict
+}
+
+// fieldKey returns the key of a struct field, which is matched against the
+// name of the field by the loader unless it is specified by a tag.
+func fieldKey(field reflect.StructField) string {
+	if tag := field.Tag.Get("mapstructure"); tag != "" {
+		return tag
+	}
+	return strings.ToLower(field.Name)
+}
+
+// marshalScalar returns a scalar value, or nil if it is the zero value and
+// keepZero is false.
+func marshalScalar(value reflect.Value, keepZero bool) interface{} {
+	if !keepZero && value.Interface() == reflect.Zero(value.Type()).Interface() {
+		return nil
+	}
+	switch value.Type() {
+	case durationType:
+		return value.Interface().(time.Duration).String()
+	case bytesType:
+		return marshalBytes(value.Int())
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint()
+	case reflect.Float32:
+		// Format with the precision of the value, so that 0.1 isn't written
+		// as 0.10000000149011612
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(value.Float(), 'f', -1, 32), 64)
+		return f
+	case reflect.Float64:
+		return value.Float()
+	}
+	return value.Interface()
+}
+
+// marshalBytes returns a size in the largest unit that represents it
+// exactly, sizes are read in binary units.
+func marshalBytes(size int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}} {
+		if size%unit.size == 0 {
+			return strconv.FormatInt(size/unit.size, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// withoutDefaultExternalName returns a copy of a network, volume, secret or
+// config without its external name if it is the key of the object, which is
+// the name that Load sets by default.
+func withoutDefaultExternalName(value reflect.Value, key string) reflect.Value {
+	if value.Kind() != reflect.Struct {
+		return value
+	}
+	external := value.FieldByName("External")
+	if !external.IsValid() || external.Type() != externalType || external.FieldByName("Name").String() != key {
+		return value
+	}
+	c := reflect.New(value.Type()).Elem()
+	c.Set(value)
+	c.FieldByName("External").FieldByName("Name").SetString("")
+	return c
+}
+
+func marshalExternal(external types.External) interface{} {
+	switch {
+	case !external.External:
+		return nil
+	case external.Name == "":
+		return true
+	default:
+		return yaml.MapSlice{{Key: "name", Value: external.Name}}
+	}
+}
@@ -0,0 +1,93 @@
+package loader
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	bytes, err := ioutil.ReadFile("full-example.yml")
+	require.NoError(t, err)
+
+	env := map[string]string{"HOME": "/home/foo", "QUX": "qux_from_environment"}
+	config, err := loadYAMLWithEnv(string(bytes), env)
+	require.NoError(t, err)
+
+	// Ports are written in the long syntax, which requires them to be unique.
+	// The example has ports that only differ by their host IP, which is not
+	// part of the long syntax.
+	service := &config.Services[0]
+	var ports []types.ServicePortConfig
+	seen := map[types.ServicePortConfig]bool{}
+	for _, port := range service.Ports {
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	service.Ports = ports
+
+	marshaled, err := Marshal(config)
+	require.NoError(t, err)
+
+	reloaded, err := loadYAMLWithEnv(string(marshaled), env)
+	require.NoError(t, err, string(marshaled))
+	assert.Equal(t, config, reloaded)
+}
+
+func TestMarshal(t *testing.T) {
+	replicas := uint64(0)
+	stopGracePeriod := 20 * time.Second
+	config := &types.Config{
+		Services: []types.ServiceConfig{
+			{
+				Name:            "web",
+				Image:           "nginx:1.13",
+				Environment:     types.MappingWithEquals{"DEBUG": nil, "PORT": strPtr("80")},
+				StopGracePeriod: &stopGracePeriod,
+				Deploy: types.DeployConfig{
+					Replicas:     &replicas,
+					UpdateConfig: &types.UpdateConfig{MaxFailureRatio: 0.1},
+				},
+				Networks: map[string]*types.ServiceNetworkConfig{"front": nil},
+			},
+		},
+		Networks: map[string]types.NetworkConfig{
+			"front": {External: types.External{External: true, Name: "front"}},
+		},
+		Secrets: map[string]types.SecretConfig{
+			"tls": {External: types.External{External: true, Name: "web_tls"}},
+		},
+	}
+
+	expected := `version: "3.5"
+services:
+  web:
+    deploy:
+      replicas: 0
+      update_config:
+        max_failure_ratio: 0.1
+    environment:
+      DEBUG: null
+      PORT: "80"
+    image: nginx:1.13
+    networks:
+      front: null
+    stop_grace_period: 20s
+networks:
+  front:
+    external: true
+secrets:
+  tls:
+    external:
+      name: web_tls
+`
+	marshaled, err := Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(marshaled))
+}
@@ -5,6 +5,7 @@
 // data/config_schema_v3.2.json
 // data/config_schema_v3.3.json
 // data/config_schema_v3.4.json
+// data/config_schema_v3.5.json
 // DO NOT EDIT!
 
 package schema
@@ -172,6 +173,26 @@ func dataConfig_schema_v34Json() (*asset, error) {
 	return a, nil
 }
 
+var _dataConfig_schema_v35Json = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5b\x4f\x8f\xdb\xb8\x15\xbf\xeb\x53\x08\x4c\x6e\xf1\xcc\x2c\xd0\x6d\x81\xe6\xd6\x63\x4f\xed\xb9\x03\x47\xa0\xa5\x67\x9b\x3b\x12\xc9\x25\x29\x67\xbc\x81\xbf\x7b\x41\x89\x92\x49\x8a\x12\x29\x8f\x9b\x49\x17\x89\x06\xc8\x8c\xf4\x7b\x8f\xef\x3f\x1f\x29\xea\x5b\x96\xe7\xe8\xa3\x2c\x8f\xd0\x60\xf4\x39\x47\x47\xa5\xf8\xe7\xa7\xa7\xdf\x24\xa3\x0f\xfd\xdd\x47\x26\x0e\x4f\x95\xc0\x7b\xf5\xf0\xcb\xaf\x4f\xfd\xbd\x0f\x68\xa3\xe9\x48\xa5\x49\x4a\x46\xf7\xe4\x50\xf4\x4f\x8a\xd3\x5f\x1e\xff\xfa\xa8\xc9\x7b\x88\x3a\x73\xd0\x20\xb6\xfb\x0d\x4a\xd5\xdf\x13\xf0\x7b\x4b\x04\x68\xe2\x67\x74\x02\x21\x09\xa3\x68\xbb\xc9\xf4\x33\x2e\x18\x07\xa1\x08\x48\xf4\x39\xd7\xc2\xe5\xf9\x08\x19\x6e\x58\x6c\xa5\x12\x84\x1e\x50\x87\xbb\x74\x1c\xf2\x1c\x49\x10\x27\x52\x5a\x1c\x46\x51\x3f\x3c\x5d\xf9\x3f\x8d\xb0\x8d\xcf\xd5\x12\xb6\xbb\xcf\xb1\x52\x20\xe8\xbf\xa7\xb2\xe9\x0b\x7d\x79\xc6\x0f\x7f\xfc\xe3\xe1\x3f\xbf\x3c\xfc\xfd\xb1\x78\xd8\x7e\xfa\xe8\x3c\xd6\xf6\x15\xb0\xd7\x46\xf8\xf0\x54\xc1\x9e\x50\xa2\x08\xa3\xe3\xf8\x68\x44\x5e\xcc\x6f\x97\x71\x60\x5c\x55\x1d\x18\xd7\xce\xd8\x7b\x5c\x4b\x70\x75\xa6\xa0\xbe\x32\xf1\x12\xd3\x79\x84\xbd\x93\xce\x66\xfc\x80\xce\xae\x3a\x27\x56\xb7\x0d\xc4\xb4\x19\x50\xef\xa4\x4c\x3f\xfc\x7d\xfc\x27\xa1\x14\xa0\x62\x0a\x0f\xa8\x77\x52\xb8\x1f\xfe\x3e\x0a\xf7\x55\x23\xa6\xf0\x80\x7a\x27\x85\xfb\xe1\xdf\xa6\x70\x36\x28\x1d\x96\x11\x7d\x79\x7d\xd0\xff\x5f\x3a\x9e\x8b\xfc\x3a\xd3\x21\x4b\x3e\x4d\x67\xcc\x39\x14\x93\x80\x39\x43\x35\x67\xde\x9e\xc3\x83\xab\x13\x1c\x53\xa1\x0a\x78\xcd\xce\xfa\xde\x8c\xcd\x7a\x40\x03\x54\xa1\xd1\x4c\x79\x8e\x76\x2d\xa9\x2b\x87\x55\x9e\x23\x46\xe1\x5f\x9a\xc5\xb3\x75\x33\xcf\xbf\xf9\xe5\xdd\xe2\xa3\x7f\x6c\x16\x4b\x41\x91\xe7\xcb\xba\x0c\xff\x50\xc9\xa8\x82\x57\x85\x3e\x47\x87\xd6\x3f\xa8\x62\xe5\x0b\x88\x3d\xa9\x21\x95\x02\x8b\x83\x5c\x30\x59\x4d\xa4\x2a\x98\x28\x2a\x52\xaa\x20\x7d\x8d\x77\x50\xbf\x89\x43\x89\xcb\x23\x14\x7b\xc1\x9a\x28\x97\x7d\xd1\x6b\x22\x83\x8c\x86\x0a\x9e\xa8\xb9\xc2\xe2\x00\x61\xcb\x7a\xe0\x09\x75\x3c\xb7\xfc\xb4\xd4\xd7\x36\x0b\x30\x44\x25\xe6\x05\xae\x2a\x47\x0e\x2c\x04\x3e\xa3\x4d\x8e\x88\x82\x46\x06\x45\xdc\xe4\xa8\xa5\xe4\xf7\x16\xfe\x69\x20\x4a\xb4\xe0\xf3\xad\x04\xe3\xf7\x67\x7c\x10\xac\xe5\x05\xc7\x42\x27\x52\x90\x85\x05\x66\x4d\x83\xe9\xbd\xb2\x6b\x8d\x1e\x09\x96\x9f\xd4\x79\x27\x65\xcd\x18\xf6\xa3\x71\x34\xeb\xe6\xac\x36\x71\x7d\xa6\xf5\x22\x5e\x31\xe2\x35\x43\x97\x5c\xd6\x8a\x32\xb5\x04\x2c\xa7\x42\x10\xdf\x92\x2a\x1d\x7c\x58\x03\x6e\x58\xe5\xca\x4d\xdb\x66\x07\x62\x92\x92\x6e\x66\x4d\xff\xde\x66\xa1\x27\xd6\x98\x5d\x65\xc5\x84\x82\x28\x28\x6e\x62\xb6\x42\xa5\x80\x0a\xa8\x22\xb8\x2e\x24\x87\xd2\x81\x0f\x9e\x5a\xf0\x0c\x4a\x2a\xc9\x48\xc0\x81\x48\x25\xce\x41\xe4\x08\xbc\xd8\x82\x55\xc0\x81\x56\xb2\x60\xf4\xb6\xea\x89\x2a\x18\x57\x24\x7e\xe8\xbf\xa9\x4c\x54\x74\x69\x56\xe8\xd9\xe8\x79\x41\xcf\x0f\xae\x40\x54\x16\x12\xb0\x28\x8f\x37\xd2\xb3\x06\x13\x9a\xe2\x54\xa0\x4a\x9c\x39\x23\x7d\x19\xcb\xa2\x19\xbd\xc4\xcc\x7d\x9e\x60\xc0\x4b\x16\x8a\x56\x57\xbc\x53\x31\xc6\xcd\x6a\x33\x00\x3d\x11\xc1\x68\x33\x14\xe9\xb4\xd9\xd9\xa2\x7f\xe5\x4c\xc2\xdb\x8b\xa3\xa1\x78\x1e\x14\xdf\x8c\x39\xbd\xb5\xc9\xf3\x1c\xed\x99\x68\xb0\x76\xc5\x30\xb6\xf5\xd8\xd2\x2c\x0f\x45\xde\xf8\xd4\xd3\x41\x77\xb5\xb8\x2e\x6a\x42\x5f\x5c\x37\xdc\x23\xc4\xe1\x55\x09\x5c\x1c\x99\x54\xb7\x34\x40\xe8\x08\xb8\x56\xc7\xf2\x08\xe5\xcb\x02\xb9\x8d\x72\xa8\x99\x54\x29\x41\x4e\x1a\x7c\x88\x83\x78\x19\x83\xdc\xdc\xe8\xa1\xbb\x1a\xdf\x62\xcb\x0e\x07\x0d\x9d\x8b\xb8\xeb\x0c\x9a\xad\x99\x3e\x51\x25\xc8\x09\x44\x58\xa8\x29\x9a\xf1\xeb\x7a\x67\xb8\xb9\x24\xcb\xf0\xe4\xfa\x2f\xb2\x40\xb4\x2f\xf4\xe5\xf1\xd3\x47\x5b\xb2\x40\x56\x75\xf9\x55\xd7\x68\x7b\xc9\x26\xf4\xde\x24\x39\xbd\xe3\x69\x98\xd6\xe7\x3a\x5e\x69\x70\xa9\xdb\x59\x01\x72\xc6\xaf\x99\xdf\xaf\x17\x93\x39\xff\x8a\x9d\x80\x65\x6a\xa5\x5e\x3d\x11\xde\xb6\x7c\x4b\x72\x5d\x74\x8d\x1f\xd1\x66\xb8\x42\x24\xa9\x51\x96\xd6\x39\x1a\x1c\xae\x09\x96\x10\x4f\xf6\x59\x43\xda\x17\x22\xfc\xf4\x6b\x62\x4c\xf8\x97\xa6\xfd\xdb\x22\xed\x0c\xe9\x2c\xcf\xf4\xa5\x5b\x84\xd5\x55\x14\xda\xd6\x75\x50\x90\x6d\x36\xe1\x95\x45\x78\xa7\x8b\x77\xc9\x42\x03\x59\x0c\x11\x27\xd5\x7c\xad\xe8\x2a\x84\x9d\x60\x9c\x09\x67\x93\xcf\x09\x2c\x53\xb0\xed\x47\x63\xe9\xce\x92\x22\xd8\x36\xd7\x50\xa7\xae\x13\x7e\x3f\xf8\x65\x33\x4b\x74\x15\x3d\x4e\x94\xad\xcf\x8f\x78\x66\x4c\x57\x26\x46\xa4\xe4\x15\x15\xa1\x0a\x0e\x20\x66\x08\x78\xbb\xab\x89\x3c\x42\xb5\x86\x46\x30\xc5\x4a\x56\x07\xc5\x9a\x10\x04\x78\xac\x49\x86\x4b\x36\x17\xda\x0e\xe3\xc0\xac\x1d\x9e\x28\xb8\x20\x27\x52\xc3\xc1\xd3\x78\xc7\x58\x0d\x98\xda\x1a\x23\x01\xb8\x2a\x18\xad\xcf\x09\x48\xa9\xb0\x88\xad\x64\x91\x84\xb2\x15\x44\x9d\x0b\xc6\xd5\xbd\x1a\x93\x2b\xf3\x63\x53\x48\xf2\x87\x13\x2c\xcf\x56\xd4\x1b\x46\x5b\x4f\x20\x01\xdf\x27\xfd\x46\x3d\x7c\xc4\xff\x26\x6d\x7e\x6e\x45\xc4\xb7\x22\xe4\x59\x96\xea\xb6\xde\x5a\xaa\x8a\xd0\x82\x71\xa0\xd1\xdc\x90\x8a\xf1\xe2\x20\x70\x09\x05\x07\x41\x58\xd0\x14\x4e\x81\xad\x5a\x81\x75\xdf\x34\x65\x23\xc9\x81\xe2\x70\xdd\xb1\xa0\xaa\xe1\x7b\x79\xdb\xea\x55\xa9\x78\xb2\xb7\x35\x69\xc8\x7c\xd2\x04\xa2\x36\xa1\x5f\xeb\x7b\xb5\x70\x8b\x36\x9b\x5d\x79\x5a\xc9\xf6\xf9\x59\xe2\xce\xe7\x58\x4a\x96\xe5\x39\x3a\x62\xb1\x62\xea\xd0\x7e\x64\x7b\x15\x26\x08\xe0\x83\x4c\xdc\xb7\xe5\x1d\xbf\x8d\x11\x64\x1b\xc4\xaf\x98\x6d\xfc\x24\x72\xd3\xc8\x7d\x7a\xc9\x02\x62\xa2\x56\x46\x17\x71\x1d\x86\xca\xa5\x05\xc8\x08\x1d\x5e\xe8\xba\x0e\xf8\xf1\x2b\xb4\xe3\xa3\x0e\xbe\xbd\xa9\x8e\x9b\x91\xe2\x52\x7e\x97\xaa\x9f\xdc\x11\x5c\x2f\xbd\xe1\x2b\x89\x54\x40\xcb\x73\xfa\x40\x3b\x32\x79\x79\x71\xbd\xe2\xe6\x4f\x4d\x5f\x83\xc2\x87\xbe\xde\x86\xc4\x0b\xd2\x85\xee\x86\x15\x31\x27\x02\xbe\x8b\x2a\x94\x95\x8c\xcf\xb8\x26\x5d\x8d\x2c\x76\xc7\xfd\xdb\x0b\xeb\xa5\x3e\xd4\x26\xb5\xac\x85\xbe\x32\xf1\xa2\x77\x95\x2b\x12\xae\x1c\x99\x47\x12\x2f\x68\x43\xc7\xeb\xef\xf5\x2d\xbd\x08\xb7\xa1\xe3\x48\x33\xee\x59\x94\x60\x93\x2d\x7b\x0d\x55\x44\xe2\x5d\x0d\x61\x47\x0d\xd4\xba\xd7\xa4\x0a\xc4\x09\xd7\xb7\x35\x0c\x02\x94\x30\x43\x4f\x5a\x29\x0b\xa6\x40\xfe\x98\xdb\xf0\x8a\x34\xc0\x5a\x75\x9b\xf2\xdd\x72\x64\x7d\xbf\x65\x18\x5c\x86\x20\x1a\x5e\xf5\x0c\xc7\x17\x22\x21\x64\x21\x07\x59\x86\x81\x9f\xc7\x10\x1a\x76\x01\xa2\x61\x92\x32\x3d\x02\xad\xba\x17\x29\x49\x73\xa9\x00\x5e\x93\x12\xcb\x70\xfb\x71\x97\x3d\xe7\x96\x57\x58\x41\x61\x4e\xc9\xd8\xea\xcc\x27\xd3\x92\x11\x4c\xf7\x28\x70\x5d\x43\x4d\x64\x13\x13\xdd\x38\xac\xc6\xe7\x15\x5e\xf7\xc8\xf7\x98\xd4\xad\x80\x02\x97\xb3\x93\x82\x47\xd1\x30\x4a\x14\x13\xb7\x0f\xd9\xe0\xd7\x62\x18\xb6\x83\x44\xb2\x56\xff\x20\x26\xaa\x70\xab\xb5\xd1\x71\xd1\x36\x81\x66\xa7\xcf\x8b\x87\x3d\x11\xb2\x8b\x44\xbd\x36\x31\x7f\x39\x48\x67\x13\xdb\x19\x37\x5e\x7b\x3d\x12\x24\xa0\x5f\x8b\xfa\x8e\xbd\x39\x1c\x82\xcb\x8f\x18\xc7\x18\x57\x7d\xa1\x92\xb7\xe1\x6a\xe5\x73\xd2\x2e\x83\x86\xc5\xde\x18\x07\xec\x97\x6e\xc3\x00\xa9\xb6\xa5\x9e\x11\xe6\x5e\x7d\xfc\x28\x06\x08\xa0\x0f\x40\x41\x90\xb2\x70\xa2\x61\xa6\xba\x4c\xb1\xf7\x34\x69\x36\xc3\x26\x8d\x45\x60\xd7\xab\xe0\xac\x26\x7d\x73\x9b\xa5\xf9\x62\xc9\x0f\xba\x5f\xee\x55\x49\x30\xed\x5b\x4b\x9d\xae\x3b\x7a\x69\xde\x70\x25\x1d\x2e\x73\xa5\xf5\x2b\xa1\x15\xfb\xba\x62\x40\x8b\xfc\x8d\xd6\xe6\x35\x2e\xc1\x9b\x85\xdf\x6a\x68\xa9\x04\x26\xd4\xd3\x3d\xa5\x7b\xb1\x07\xe9\x86\x81\x3d\x08\xa0\xd3\x2a\xe7\x48\x68\x38\xfb\x8f\xc7\x71\xbc\x07\xcb\xba\xc5\x35\x34\x08\xc9\xf5\xb2\x2d\xa8\xc7\x04\xee\x29\x96\xee\x29\x43\x7e\xd7\x54\xcb\x3c\xd2\x15\x7d\x7f\xb0\xdc\x2c\xb5\x6e\x53\x82\x4d\xe6\xf9\xc0\xf5\x5e\xc0\x6b\xf3\xde\x9a\xf7\x92\x5e\x0c\xe8\x23\xd4\x30\x8e\x3c\x1e\x74\xb2\x50\x4b\xbc\xe3\x51\x80\x5e\xcc\x6a\x7a\xe2\x7f\x97\x8b\xde\x6e\xc1\x75\x0b\xc1\xe6\xc3\x41\x3a\xde\x4c\xf5\xa7\x1d\x10\x16\x83\x14\xe2\x4b\xf0\xd3\x86\x98\x4f\x07\xd8\x30\xd6\x1b\x7a\xf1\xa4\xc3\x09\xe6\x08\x83\x7e\xbb\xe1\xbb\x61\xc9\x7d\x49\xbb\xa3\xf1\x03\x08\x6e\xcf\x16\xb4\x34\xe1\xb8\x59\x25\xd8\x82\x44\x49\x16\x19\xea\xec\x74\x31\xb0\x90\x59\x79\x3e\x9b\x61\x13\xca\x80\xd4\x71\xd9\x0d\x42\xb6\x3b\x3a\xb3\xf9\x35\x81\x7b\x3a\xad\x09\x7a\xd7\x1d\xfe\x5f\x0e\xe3\x34\x96\x97\xcd\xf4\xa4\x95\xa7\xe3\xa0\xd0\xf3\xb8\xaf\xb0\x19\x6d\xb5\x4d\x76\xf1\xec\x31\xa7\xfb\xc9\x4f\xe8\x55\xfe\xc5\xbd\x10\xac\x14\x2e\x8f\x49\xdb\x26\x2b\x57\xaf\x86\x70\xe4\xb0\x62\x96\x99\x6c\xee\x05\xcb\x90\x41\x8d\xfc\x6f\xaf\x42\x29\xe7\xce\xfe\x1c\x95\xea\xff\x3d\xae\xbf\x5f\x0c\x9a\x2f\xb0\x22\x31\x68\x50\x9b\xcc\xb5\xa3\xef\xe4\xd9\xc8\x4b\x38\x47\xfd\x03\xf8\x6c\xfc\xfd\x7d\x5c\x31\x99\xe8\x82\xae\x30\xa8\x4d\xe6\x9a\xe7\xa7\x2b\xee\xe9\x0a\xef\x15\xf7\x55\x87\xc0\xde\xf6\x92\x25\x93\xcf\xe1\x19\x8a\xad\x2b\x86\x0f\xb3\xe4\x08\xf7\x3e\xd7\x9e\x67\x56\xa8\xb9\x37\x2c\xde\xa0\xc6\x88\xcb\x9a\x07\x62\xe3\xd6\xba\xff\xf8\x69\x72\x2f\xcf\x17\x26\x81\x71\xd6\x73\x48\xae\x71\xe3\x44\x4e\x8a\xef\x3d\x92\x6f\xbe\x85\xd7\x1f\x2e\x0a\xfb\xd4\xdb\x36\x30\xa0\xe1\x13\x73\xc7\x08\x73\xf9\x3f\xd0\x4f\x3e\xbe\xd4\x7a\xd2\xb3\xe7\x25\x1d\x85\xce\xeb\xe4\xfe\xc3\x49\xfb\x20\xd3\x04\xd2\x9f\x3e\xb7\x26\x5a\x2b\xbd\xe7\x93\x3b\xf8\x49\xa6\xff\x32\x7b\xf8\x34\xd2\x3e\x18\x70\xc9\xfc\xdf\xcc\x8a\x2d\xcb\xf3\x4b\x76\xc9\xfe\x3b\x00\xea\x89\x44\x73\x54\x40\x00\x00")
+
+func dataConfig_schema_v35JsonBytes() ([]byte, error) {
+	return bindataRead(
+		_dataConfig_schema_v35Json,
+		"data/config_schema_v3.5.json",
+	)
+}
+
+func dataConfig_schema_v35Json() (*asset, error) {
+	bytes, err := dataConfig_schema_v35JsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/config_schema_v3.5.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -229,6 +250,7 @@ var _bindata = map[string]func() (*asset, error){
 	"data/config_schema_v3.2.json": dataConfig_schema_v32Json,
 	"data/config_schema_v3.3.json": dataConfig_schema_v33Json,
 	"data/config_schema_v3.4.json": dataConfig_schema_v34Json,
+	"data/config_schema_v3.5.json": dataConfig_schema_v35Json,
 }
 
 // AssetDir returns the file names below a certain
@@ -277,6 +299,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"config_schema_v3.2.json": &bintree{dataConfig_schema_v32Json, map[string]*bintree{}},
 		"config_schema_v3.3.json": &bintree{dataConfig_schema_v33Json, map[string]*bintree{}},
 		"config_schema_v3.4.json": &bintree{dataConfig_schema_v34Json, map[string]*bintree{}},
+		"config_schema_v3.5.json": &bintree{dataConfig_schema_v35Json, map[string]*bintree{}},
 	}},
 }}
 
@@ -44,6 +44,7 @@ type ConfigFile struct {
 	NodesFormat          string                      `json:"nodesFormat,omitempty"`
 	PruneFilters         []string                    `json:"pruneFilters,omitempty"`
 	Proxies              map[string]ProxyConfig      `json:"proxies,omitempty"`
+	NodeHosts            map[string]string           `json:"nodeHosts,omitempty"`
 }
 
 // ProxyConfig contains proxy configuration settings